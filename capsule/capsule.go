@@ -24,6 +24,7 @@ func New() *Manager {
 		Pool:        &Pool{},
 		Connections: &sync.Map{},
 		Option:      &dbal.Option{},
+		Scopes:      query.NewScopes(),
 	}
 }
 
@@ -55,6 +56,15 @@ func Query() query.Query {
 	return Global.Query()
 }
 
+// Transaction Execute the callback within a transaction using the global manager.
+func Transaction(callback func(qb query.Query) error) error {
+	if Global == nil {
+		err := errors.New("the global capsule not set")
+		panic(err)
+	}
+	return Global.Transaction(callback)
+}
+
 // ************************************************************
 // THE FOLLOWING LINES WILL BE DEPRECATED
 // ************************************************************
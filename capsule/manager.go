@@ -92,9 +92,36 @@ func (manager *Manager) Query() query.Query {
 			Read:        &read.DB,
 			ReadConfig:  read.Config,
 			Option:      manager.Option,
+			Scopes:      manager.Scopes,
+			Timestamps:  manager.Timestamps,
 		})
 }
 
+// UseTimestamps Fill the created_at and updated_at columns of the tables on write for the query builders of the manager.
+func (manager *Manager) UseTimestamps(option ...*query.Timestamps) *Manager {
+	manager.Timestamps = &query.Timestamps{}
+	if len(option) > 0 && option[0] != nil {
+		manager.Timestamps = option[0]
+	}
+	return manager
+}
+
+// RegisterScope Register a global query scope of the table, the scope is applied to the query builders of the manager.
+func (manager *Manager) RegisterScope(table string, name string, apply func(qb query.Query)) *Manager {
+	manager.Scopes.Register(table, name, apply)
+	return manager
+}
+
+// Begin Start a new transaction on the primary connection and return a query builder bound to it.
+func (manager *Manager) Begin() (query.Query, error) {
+	return manager.Query().Begin()
+}
+
+// Transaction Execute the callback within a transaction on the primary connection.
+func (manager *Manager) Transaction(callback func(qb query.Query) error) error {
+	return manager.Query().Transaction(callback)
+}
+
 // Close the connections
 func (manager *Manager) Close() error {
 
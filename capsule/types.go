@@ -5,6 +5,7 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/query"
 )
 
 // Manager The database manager
@@ -12,6 +13,8 @@ type Manager struct {
 	Pool        *Pool
 	Connections *sync.Map // map[string]*Connection
 	Option      *dbal.Option
+	Scopes      *query.Scopes     // The global query scopes shared by the query builders of the manager
+	Timestamps  *query.Timestamps // The automatic timestamps of the query builders of the manager, the timestamps are not filled if nil
 }
 
 // Pool the connection pool
@@ -0,0 +1,767 @@
+package dbal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ASTVersion the version of the query AST, the AST of the other versions could not be decoded.
+const ASTVersion = 1
+
+// astKinds the basic types of the values in the query AST
+var astKinds = map[string]reflect.Type{
+	"string":  reflect.TypeOf(""),
+	"bool":    reflect.TypeOf(false),
+	"int":     reflect.TypeOf(int(0)),
+	"int8":    reflect.TypeOf(int8(0)),
+	"int16":   reflect.TypeOf(int16(0)),
+	"int32":   reflect.TypeOf(int32(0)),
+	"int64":   reflect.TypeOf(int64(0)),
+	"uint":    reflect.TypeOf(uint(0)),
+	"uint8":   reflect.TypeOf(uint8(0)),
+	"uint16":  reflect.TypeOf(uint16(0)),
+	"uint32":  reflect.TypeOf(uint32(0)),
+	"uint64":  reflect.TypeOf(uint64(0)),
+	"float32": reflect.TypeOf(float32(0)),
+	"float64": reflect.TypeOf(float64(0)),
+}
+
+// MarshalJSON Encode the query as the versioned query AST. The sub-queries compiled into SQL are kept as they are,
+// so the AST should be replayed with the grammar of the same driver if the query has the sub-queries.
+func (query Query) MarshalJSON() ([]byte, error) {
+	ast, err := query.toAST()
+	if err != nil {
+		return nil, err
+	}
+	ast.Version = ASTVersion
+	return json.Marshal(ast)
+}
+
+// UnmarshalJSON Decode the query from the query AST
+func (query *Query) UnmarshalJSON(data []byte) error {
+	ast := astQuery{}
+	err := json.Unmarshal(data, &ast)
+	if err != nil {
+		return err
+	}
+
+	if ast.Version != ASTVersion {
+		return fmt.Errorf("the version %d of the query AST is not supported, the version should be %d", ast.Version, ASTVersion)
+	}
+
+	new, err := ast.toQuery()
+	if err != nil {
+		return err
+	}
+	*query = *new
+	return nil
+}
+
+// toAST Convert the query to the query AST
+func (query *Query) toAST() (*astQuery, error) {
+	var err error
+	ast := &astQuery{
+		UseWriteConnection: query.UseWriteConnection,
+		UnionLimit:         query.UnionLimit,
+		UnionOffset:        query.UnionOffset,
+		Limit:              query.Limit,
+		Offset:             query.Offset,
+		Distinct:           query.Distinct,
+		IsJoinClause:       query.IsJoinClause,
+		BindingOffset:      query.BindingOffset,
+		SQL:                query.SQL,
+		WithoutScopes:      query.CopyWithoutScopes(),
+		OnlyTrashed:        query.OnlyTrashed,
+	}
+
+	if query.CTEs != nil {
+		ast.CTEs = []astCTE{}
+		for _, cte := range query.CTEs {
+			value := astCTE{Name: cte.Name, Columns: cte.Columns, Recursive: cte.Recursive, Materialized: cte.Materialized, SQL: cte.SQL, Offset: cte.Offset}
+			if value.Query, err = encodeASTQuery(cte.Query); err != nil {
+				return nil, err
+			}
+			ast.CTEs = append(ast.CTEs, value)
+		}
+	}
+
+	if ast.Lock, err = encodeASTValue(query.Lock); err != nil {
+		return nil, err
+	}
+
+	if ast.From, err = encodeASTFrom(query.From.Type, query.From.Name, query.From.Alias, query.From.Offset, query.From.SQL); err != nil {
+		return nil, err
+	}
+
+	if ast.Columns, err = encodeASTValues(query.Columns); err != nil {
+		return nil, err
+	}
+
+	ast.Aggregate.Func = query.Aggregate.Func
+	if ast.Aggregate.Columns, err = encodeASTValues(query.Aggregate.Columns); err != nil {
+		return nil, err
+	}
+
+	if ast.Wheres, err = encodeASTWheres(query.Wheres); err != nil {
+		return nil, err
+	}
+
+	if query.Joins != nil {
+		ast.Joins = []astJoin{}
+		for _, join := range query.Joins {
+			value := astJoin{Type: join.Type, Alias: join.Alias, Offset: join.Offset}
+			if value.Name, err = encodeASTValue(join.Name); err != nil {
+				return nil, err
+			}
+			if value.SQL, err = encodeASTValue(join.SQL); err != nil {
+				return nil, err
+			}
+			if value.Query, err = encodeASTQuery(join.Query); err != nil {
+				return nil, err
+			}
+			ast.Joins = append(ast.Joins, value)
+		}
+	}
+
+	if query.Unions != nil {
+		ast.Unions = []astUnion{}
+		for _, union := range query.Unions {
+			value := astUnion{All: union.All}
+			if value.Query, err = encodeASTQuery(union.Query); err != nil {
+				return nil, err
+			}
+			ast.Unions = append(ast.Unions, value)
+		}
+	}
+
+	if ast.UnionOrders, err = encodeASTOrders(query.UnionOrders); err != nil {
+		return nil, err
+	}
+
+	if ast.Orders, err = encodeASTOrders(query.Orders); err != nil {
+		return nil, err
+	}
+
+	if ast.Groups, err = encodeASTValues(query.Groups); err != nil {
+		return nil, err
+	}
+
+	if query.Havings != nil {
+		ast.Havings = []astHaving{}
+		for _, having := range query.Havings {
+			value := astHaving{Type: having.Type, Operator: having.Operator, Boolean: having.Boolean, Offset: having.Offset, Not: having.Not, SQL: having.SQL}
+			if value.Column, err = encodeASTValue(having.Column); err != nil {
+				return nil, err
+			}
+			if value.Value, err = encodeASTValue(having.Value); err != nil {
+				return nil, err
+			}
+			if value.Values, err = encodeASTValues(having.Values); err != nil {
+				return nil, err
+			}
+			ast.Havings = append(ast.Havings, value)
+		}
+	}
+
+	if query.Windows != nil {
+		ast.Windows = []astWindow{}
+		for _, window := range query.Windows {
+			value, err := encodeASTWindow(window)
+			if err != nil {
+				return nil, err
+			}
+			ast.Windows = append(ast.Windows, value)
+		}
+	}
+
+	if ast.Returning, err = encodeASTValues(query.Returning); err != nil {
+		return nil, err
+	}
+
+	if query.Bindings != nil {
+		ast.Bindings = map[string][]astValue{}
+		for key, bindings := range query.Bindings {
+			if ast.Bindings[key], err = encodeASTValues(bindings); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if ast.DistinctColumns, err = encodeASTValues(query.DistinctColumns); err != nil {
+		return nil, err
+	}
+
+	if query.Remember != nil {
+		remember := astRemember(*query.Remember)
+		ast.Remember = &remember
+	}
+
+	return ast, nil
+}
+
+// toQuery Convert the query AST to the query
+func (ast *astQuery) toQuery() (*Query, error) {
+	var err error
+	query := &Query{
+		UseWriteConnection: ast.UseWriteConnection,
+		UnionLimit:         ast.UnionLimit,
+		UnionOffset:        ast.UnionOffset,
+		Limit:              ast.Limit,
+		Offset:             ast.Offset,
+		Distinct:           ast.Distinct,
+		IsJoinClause:       ast.IsJoinClause,
+		BindingOffset:      ast.BindingOffset,
+		SQL:                ast.SQL,
+		WithoutScopes:      ast.WithoutScopes,
+		OnlyTrashed:        ast.OnlyTrashed,
+	}
+
+	if ast.CTEs != nil {
+		query.CTEs = []CTE{}
+		for _, value := range ast.CTEs {
+			cte := CTE{Name: value.Name, Columns: value.Columns, Recursive: value.Recursive, Materialized: value.Materialized, SQL: value.SQL, Offset: value.Offset}
+			if cte.Query, err = decodeASTQuery(value.Query); err != nil {
+				return nil, err
+			}
+			query.CTEs = append(query.CTEs, cte)
+		}
+	}
+
+	if query.Lock, err = ast.Lock.decode(); err != nil {
+		return nil, err
+	}
+
+	query.From = From{Type: ast.From.Type, Alias: ast.From.Alias, Offset: ast.From.Offset, SQL: ast.From.SQL}
+	if query.From.Name, err = ast.From.Name.decode(); err != nil {
+		return nil, err
+	}
+
+	if query.Columns, err = decodeASTValues(ast.Columns); err != nil {
+		return nil, err
+	}
+
+	query.Aggregate.Func = ast.Aggregate.Func
+	if query.Aggregate.Columns, err = decodeASTValues(ast.Aggregate.Columns); err != nil {
+		return nil, err
+	}
+
+	if query.Wheres, err = decodeASTWheres(ast.Wheres); err != nil {
+		return nil, err
+	}
+
+	if ast.Joins != nil {
+		query.Joins = []Join{}
+		for _, value := range ast.Joins {
+			join := Join{Type: value.Type, Alias: value.Alias, Offset: value.Offset}
+			if join.Name, err = value.Name.decode(); err != nil {
+				return nil, err
+			}
+			if join.SQL, err = value.SQL.decode(); err != nil {
+				return nil, err
+			}
+			if join.Query, err = decodeASTQuery(value.Query); err != nil {
+				return nil, err
+			}
+			query.Joins = append(query.Joins, join)
+		}
+	}
+
+	if ast.Unions != nil {
+		query.Unions = []Union{}
+		for _, value := range ast.Unions {
+			union := Union{All: value.All}
+			if union.Query, err = decodeASTQuery(value.Query); err != nil {
+				return nil, err
+			}
+			query.Unions = append(query.Unions, union)
+		}
+	}
+
+	if query.UnionOrders, err = decodeASTOrders(ast.UnionOrders); err != nil {
+		return nil, err
+	}
+
+	if query.Orders, err = decodeASTOrders(ast.Orders); err != nil {
+		return nil, err
+	}
+
+	if query.Groups, err = decodeASTValues(ast.Groups); err != nil {
+		return nil, err
+	}
+
+	if ast.Havings != nil {
+		query.Havings = []Having{}
+		for _, value := range ast.Havings {
+			having := Having{Type: value.Type, Operator: value.Operator, Boolean: value.Boolean, Offset: value.Offset, Not: value.Not, SQL: value.SQL}
+			if having.Column, err = value.Column.decode(); err != nil {
+				return nil, err
+			}
+			if having.Value, err = value.Value.decode(); err != nil {
+				return nil, err
+			}
+			if having.Values, err = decodeASTValues(value.Values); err != nil {
+				return nil, err
+			}
+			query.Havings = append(query.Havings, having)
+		}
+	}
+
+	if ast.Windows != nil {
+		query.Windows = []Window{}
+		for _, value := range ast.Windows {
+			window, err := value.toWindow()
+			if err != nil {
+				return nil, err
+			}
+			query.Windows = append(query.Windows, window)
+		}
+	}
+
+	if query.Returning, err = decodeASTValues(ast.Returning); err != nil {
+		return nil, err
+	}
+
+	if ast.Bindings != nil {
+		query.Bindings = map[string][]interface{}{}
+		for key, values := range ast.Bindings {
+			if query.Bindings[key], err = decodeASTValues(values); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if query.DistinctColumns, err = decodeASTValues(ast.DistinctColumns); err != nil {
+		return nil, err
+	}
+
+	if ast.Remember != nil {
+		remember := Remember(*ast.Remember)
+		query.Remember = &remember
+	}
+
+	return query, nil
+}
+
+// encodeASTQuery Convert the sub-query to the query AST, returns nil if the query is nil
+func encodeASTQuery(query *Query) (*astQuery, error) {
+	if query == nil {
+		return nil, nil
+	}
+	return query.toAST()
+}
+
+// decodeASTQuery Convert the query AST to the sub-query, returns nil if the AST is nil
+func decodeASTQuery(ast *astQuery) (*Query, error) {
+	if ast == nil {
+		return nil, nil
+	}
+	return ast.toQuery()
+}
+
+// encodeASTFrom Convert the from or the select to the query AST
+func encodeASTFrom(typ string, name interface{}, alias string, offset int, sql string) (astFrom, error) {
+	value, err := encodeASTValue(name)
+	if err != nil {
+		return astFrom{}, err
+	}
+	return astFrom{Type: typ, Name: value, Alias: alias, Offset: offset, SQL: sql}, nil
+}
+
+// encodeASTWheres Convert the where clauses to the query AST
+func encodeASTWheres(wheres []Where) ([]astWhere, error) {
+	if wheres == nil {
+		return nil, nil
+	}
+
+	var err error
+	values := []astWhere{}
+	for _, where := range wheres {
+		value := astWhere{Type: where.Type, SQL: where.SQL, Operator: where.Operator, Boolean: where.Boolean, Not: where.Not, Offset: where.Offset}
+		if value.Column, err = encodeASTValue(where.Column); err != nil {
+			return nil, err
+		}
+		if value.First, err = encodeASTValue(where.First); err != nil {
+			return nil, err
+		}
+		if value.Second, err = encodeASTValue(where.Second); err != nil {
+			return nil, err
+		}
+		if value.Wheres, err = encodeASTWheres(where.Wheres); err != nil {
+			return nil, err
+		}
+		if value.Query, err = encodeASTQuery(where.Query); err != nil {
+			return nil, err
+		}
+		if value.Value, err = encodeASTValue(where.Value); err != nil {
+			return nil, err
+		}
+		if value.Values, err = encodeASTValues(where.Values); err != nil {
+			return nil, err
+		}
+		if value.ValuesIn, err = encodeASTValue(where.ValuesIn); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// decodeASTWheres Convert the query AST to the where clauses
+func decodeASTWheres(values []astWhere) ([]Where, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	var err error
+	wheres := []Where{}
+	for _, value := range values {
+		where := Where{Type: value.Type, SQL: value.SQL, Operator: value.Operator, Boolean: value.Boolean, Not: value.Not, Offset: value.Offset}
+		if where.Column, err = value.Column.decode(); err != nil {
+			return nil, err
+		}
+		if where.First, err = value.First.decode(); err != nil {
+			return nil, err
+		}
+		if where.Second, err = value.Second.decode(); err != nil {
+			return nil, err
+		}
+		if where.Wheres, err = decodeASTWheres(value.Wheres); err != nil {
+			return nil, err
+		}
+		if where.Query, err = decodeASTQuery(value.Query); err != nil {
+			return nil, err
+		}
+		if where.Value, err = value.Value.decode(); err != nil {
+			return nil, err
+		}
+		if where.Values, err = decodeASTValues(value.Values); err != nil {
+			return nil, err
+		}
+		if where.ValuesIn, err = value.ValuesIn.decode(); err != nil {
+			return nil, err
+		}
+		wheres = append(wheres, where)
+	}
+	return wheres, nil
+}
+
+// encodeASTOrders Convert the orders to the query AST
+func encodeASTOrders(orders []Order) ([]astOrder, error) {
+	if orders == nil {
+		return nil, nil
+	}
+
+	values := []astOrder{}
+	for _, order := range orders {
+		column, err := encodeASTValue(order.Column)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, astOrder{Type: order.Type, Column: column, Direction: order.Direction, Offset: order.Offset, SQL: order.SQL})
+	}
+	return values, nil
+}
+
+// decodeASTOrders Convert the query AST to the orders
+func decodeASTOrders(values []astOrder) ([]Order, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	orders := []Order{}
+	for _, value := range values {
+		column, err := value.Column.decode()
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, Order{Type: value.Type, Column: column, Direction: value.Direction, Offset: value.Offset, SQL: value.SQL})
+	}
+	return orders, nil
+}
+
+// encodeASTWindow Convert the window to the query AST
+func encodeASTWindow(window Window) (astWindow, error) {
+	var err error
+	value := astWindow{Func: window.Func, Name: window.Name, Frame: window.Frame, Alias: window.Alias}
+	if value.Args, err = encodeASTValues(window.Args); err != nil {
+		return value, err
+	}
+	if value.Partitions, err = encodeASTValues(window.Partitions); err != nil {
+		return value, err
+	}
+	if value.Orders, err = encodeASTOrders(window.Orders); err != nil {
+		return value, err
+	}
+	return value, nil
+}
+
+// toWindow Convert the query AST to the window
+func (ast astWindow) toWindow() (Window, error) {
+	var err error
+	window := Window{Func: ast.Func, Name: ast.Name, Frame: ast.Frame, Alias: ast.Alias}
+	if window.Args, err = decodeASTValues(ast.Args); err != nil {
+		return window, err
+	}
+	if window.Partitions, err = decodeASTValues(ast.Partitions); err != nil {
+		return window, err
+	}
+	if window.Orders, err = decodeASTOrders(ast.Orders); err != nil {
+		return window, err
+	}
+	return window, nil
+}
+
+// encodeASTValues Convert the values to the query AST, returns nil if the values are nil
+func encodeASTValues(values []interface{}) ([]astValue, error) {
+	if values == nil {
+		return nil, nil
+	}
+
+	asts := []astValue{}
+	for _, value := range values {
+		ast, err := encodeASTValue(value)
+		if err != nil {
+			return nil, err
+		}
+		asts = append(asts, ast)
+	}
+	return asts, nil
+}
+
+// decodeASTValues Convert the query AST to the values, returns nil if the AST is nil
+func decodeASTValues(asts []astValue) ([]interface{}, error) {
+	if asts == nil {
+		return nil, nil
+	}
+
+	values := []interface{}{}
+	for _, ast := range asts {
+		value, err := ast.decode()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// encodeASTValue Convert the value to the query AST with the type of the value
+func encodeASTValue(value interface{}) (astValue, error) {
+	var data interface{} = value
+	typ := ""
+
+	switch v := value.(type) {
+	case nil:
+		return astValue{Type: "null"}, nil
+
+	case time.Time:
+		typ = "time"
+
+	case []byte:
+		typ = "bytes"
+
+	case Expression:
+		inner, err := encodeASTValue(v.Value)
+		if err != nil {
+			return astValue{}, err
+		}
+		typ, data = "expression", inner
+
+	case Name:
+		typ, data = "name", astName(v)
+
+	case Select:
+		from, err := encodeASTFrom(v.Type, v.Name, v.Alias, v.Offset, v.SQL)
+		if err != nil {
+			return astValue{}, err
+		}
+		typ, data = "select", from
+
+	case From:
+		from, err := encodeASTFrom(v.Type, v.Name, v.Alias, v.Offset, v.SQL)
+		if err != nil {
+			return astValue{}, err
+		}
+		typ, data = "from", from
+
+	case Window:
+		window, err := encodeASTWindow(v)
+		if err != nil {
+			return astValue{}, err
+		}
+		typ, data = "window", window
+
+	case *Window:
+		if v == nil {
+			return astValue{Type: "null"}, nil
+		}
+		return encodeASTValue(*v)
+
+	case FullText:
+		typ, data = "fulltext", astFullText(v)
+
+	case Lock:
+		typ, data = "lock", astLock(v)
+
+	case *Lock:
+		if v == nil {
+			return astValue{Type: "null"}, nil
+		}
+		return encodeASTValue(*v)
+
+	case *Query:
+		if v == nil {
+			return astValue{Type: "null"}, nil
+		}
+		query, err := v.toAST()
+		if err != nil {
+			return astValue{}, err
+		}
+		typ, data = "query", query
+
+	default:
+		reflectValue := reflect.ValueOf(value)
+		kind := reflectValue.Kind()
+		switch {
+		case kind == reflect.Ptr:
+			if reflectValue.IsNil() {
+				return astValue{Type: "null"}, nil
+			}
+			return encodeASTValue(reflectValue.Elem().Interface())
+
+		case kind == reflect.Slice || kind == reflect.Array:
+			items := []interface{}{}
+			for i := 0; i < reflectValue.Len(); i++ {
+				items = append(items, reflectValue.Index(i).Interface())
+			}
+			list, err := encodeASTValues(items)
+			if err != nil {
+				return astValue{}, err
+			}
+			typ, data = "list", list
+
+		case kind == reflect.Map && reflectValue.Type().Key().Kind() == reflect.String:
+			items := map[string]astValue{}
+			iter := reflectValue.MapRange()
+			for iter.Next() {
+				item, err := encodeASTValue(iter.Value().Interface())
+				if err != nil {
+					return astValue{}, err
+				}
+				items[iter.Key().String()] = item
+			}
+			typ, data = "map", items
+
+		default:
+			basic, has := astKinds[kind.String()]
+			if !has {
+				return astValue{}, fmt.Errorf("the %T value could not be encoded in the query AST", value)
+			}
+			typ, data = kind.String(), reflectValue.Convert(basic).Interface()
+		}
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return astValue{}, err
+	}
+	return astValue{Type: typ, Value: raw}, nil
+}
+
+// decode Convert the query AST to the value of the type
+func (ast astValue) decode() (interface{}, error) {
+	var err error
+	switch ast.Type {
+	case "", "null":
+		return nil, nil
+
+	case "time":
+		value := time.Time{}
+		err = json.Unmarshal(ast.Value, &value)
+		return value, err
+
+	case "bytes":
+		value := []byte{}
+		err = json.Unmarshal(ast.Value, &value)
+		return value, err
+
+	case "expression":
+		inner := astValue{}
+		if err = json.Unmarshal(ast.Value, &inner); err != nil {
+			return nil, err
+		}
+		value, err := inner.decode()
+		return Expression{Value: value}, err
+
+	case "name":
+		value := astName{}
+		err = json.Unmarshal(ast.Value, &value)
+		return Name(value), err
+
+	case "select", "from":
+		from := astFrom{}
+		if err = json.Unmarshal(ast.Value, &from); err != nil {
+			return nil, err
+		}
+		name, err := from.Name.decode()
+		if ast.Type == "select" {
+			return Select{Type: from.Type, Name: name, Alias: from.Alias, Offset: from.Offset, SQL: from.SQL}, err
+		}
+		return From{Type: from.Type, Name: name, Alias: from.Alias, Offset: from.Offset, SQL: from.SQL}, err
+
+	case "window":
+		window := astWindow{}
+		if err = json.Unmarshal(ast.Value, &window); err != nil {
+			return nil, err
+		}
+		return window.toWindow()
+
+	case "fulltext":
+		value := astFullText{}
+		err = json.Unmarshal(ast.Value, &value)
+		return FullText(value), err
+
+	case "lock":
+		value := astLock{}
+		err = json.Unmarshal(ast.Value, &value)
+		return Lock(value), err
+
+	case "query":
+		query := astQuery{}
+		if err = json.Unmarshal(ast.Value, &query); err != nil {
+			return nil, err
+		}
+		return query.toQuery()
+
+	case "list":
+		list := []astValue{}
+		if err = json.Unmarshal(ast.Value, &list); err != nil {
+			return nil, err
+		}
+		return decodeASTValues(list)
+
+	case "map":
+		items := map[string]astValue{}
+		if err = json.Unmarshal(ast.Value, &items); err != nil {
+			return nil, err
+		}
+		values := map[string]interface{}{}
+		for key, item := range items {
+			if values[key], err = item.decode(); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	}
+
+	basic, has := astKinds[ast.Type]
+	if !has {
+		return nil, fmt.Errorf("the %q type of the query AST is not supported", ast.Type)
+	}
+
+	value := reflect.New(basic)
+	err = json.Unmarshal(ast.Value, value.Interface())
+	return value.Elem().Interface(), err
+}
@@ -0,0 +1,116 @@
+package dbal
+
+import (
+	"container/list"
+	"time"
+)
+
+// NewLRUCache create a new in-memory cache store holding the given number of entries at most
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1024
+	}
+	return &LRUCache{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		tags:     map[string]map[string]bool{},
+		list:     list.New(),
+	}
+}
+
+// Get the cached value of the given key, returns false if the value is missing or expired
+func (cache *LRUCache) Get(key string) (interface{}, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	element, has := cache.entries[key]
+	if !has {
+		return nil, false
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		cache.remove(element)
+		return nil, false
+	}
+
+	cache.list.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set cache the value with the given key, the value never expires if the ttl is zero. The tags are used for flushing the entries.
+func (cache *LRUCache) Set(key string, value interface{}, ttl time.Duration, tags ...string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if element, has := cache.entries[key]; has {
+		cache.remove(element)
+	}
+
+	entry := &lruEntry{key: key, value: value, tags: tags}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+
+	cache.entries[key] = cache.list.PushFront(entry)
+	for _, tag := range tags {
+		if _, has := cache.tags[tag]; !has {
+			cache.tags[tag] = map[string]bool{}
+		}
+		cache.tags[tag][key] = true
+	}
+
+	for cache.list.Len() > cache.capacity {
+		cache.remove(cache.list.Back())
+	}
+}
+
+// Forget remove the cached value of the given key
+func (cache *LRUCache) Forget(key string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if element, has := cache.entries[key]; has {
+		cache.remove(element)
+	}
+}
+
+// Flush remove the entries having any of the given tags, all of the entries will be removed if the tags are not given
+func (cache *LRUCache) Flush(tags ...string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if len(tags) == 0 {
+		cache.entries = map[string]*list.Element{}
+		cache.tags = map[string]map[string]bool{}
+		cache.list.Init()
+		return
+	}
+
+	for _, tag := range tags {
+		for key := range cache.tags[tag] {
+			if element, has := cache.entries[key]; has {
+				cache.remove(element)
+			}
+		}
+	}
+}
+
+// Len get the number of the cached entries
+func (cache *LRUCache) Len() int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.list.Len()
+}
+
+// remove the entry from the list and the indexes
+func (cache *LRUCache) remove(element *list.Element) {
+	entry := element.Value.(*lruEntry)
+	cache.list.Remove(element)
+	delete(cache.entries, entry.key)
+	for _, tag := range entry.tags {
+		delete(cache.tags[tag], entry.key)
+		if len(cache.tags[tag]) == 0 {
+			delete(cache.tags, tag)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package dbal
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// contextExecutor the executor carries the context into every statement
+type contextExecutor struct {
+	Executor
+	ctx context.Context
+}
+
+// WithContext wrap the executor, the statements executed by the returned executor will carry the given context
+func WithContext(ctx context.Context, executor Executor) Executor {
+	if ctx == nil {
+		return executor
+	}
+	if ce, ok := executor.(*contextExecutor); ok {
+		executor = ce.Executor
+	}
+	return &contextExecutor{Executor: executor, ctx: ctx}
+}
+
+// Query executes a query that returns rows using the context
+func (executor *contextExecutor) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return executor.Executor.QueryContext(executor.ctx, query, args...)
+}
+
+// Queryx executes a query that returns *sqlx.Rows using the context
+func (executor *contextExecutor) Queryx(query string, args ...interface{}) (*sqlx.Rows, error) {
+	return executor.Executor.QueryxContext(executor.ctx, query, args...)
+}
+
+// QueryRowx executes a query that is expected to return at most one row using the context
+func (executor *contextExecutor) QueryRowx(query string, args ...interface{}) *sqlx.Row {
+	return executor.Executor.QueryRowxContext(executor.ctx, query, args...)
+}
+
+// Exec executes a query without returning any rows using the context
+func (executor *contextExecutor) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return executor.Executor.ExecContext(executor.ctx, query, args...)
+}
+
+// Prepare creates a prepared statement using the context
+func (executor *contextExecutor) Prepare(query string) (*sql.Stmt, error) {
+	return executor.Executor.PrepareContext(executor.ctx, query)
+}
+
+// Get executes a query and scan the first row into dest using the context
+func (executor *contextExecutor) Get(dest interface{}, query string, args ...interface{}) error {
+	return executor.Executor.GetContext(executor.ctx, dest, query, args...)
+}
+
+// Select executes a query and scan the rows into dest using the context
+func (executor *contextExecutor) Select(dest interface{}, query string, args ...interface{}) error {
+	return executor.Executor.SelectContext(executor.ctx, dest, query, args...)
+}
@@ -11,6 +11,7 @@ var Grammars = map[string]Grammar{}
 
 // BindingKeys the binding key orders
 var BindingKeys = []string{
+	"with",
 	"select", "from", "join", "where",
 	"groupBy", "having",
 	"order",
@@ -96,6 +97,7 @@ func NewQuery() *Query {
 		UnionOffset:        -1,
 		BindingOffset:      0,
 		Bindings: map[string][]interface{}{
+			"with":       {},
 			"select":     {},
 			"from":       {},
 			"join":       {},
@@ -118,6 +120,9 @@ func NewExpression(value interface{}) Expression {
 	}
 }
 
+// NamedParameter the placeholder of the named bindings in the raw SQL, it is replaced with the parameter of the database when the query is compiled.
+const NamedParameter = "\x00?"
+
 // Raw make a new expression instance
 func Raw(value interface{}) Expression {
 	return NewExpression(value)
@@ -281,6 +286,7 @@ func (query *Query) Clone() *Query {
 
 	new := Query{
 		UseWriteConnection: query.UseWriteConnection,    // Whether to use write connection for the select. default is false
+		CTEs:               query.CopyCTEs(),            // The common table expressions of the query.
 		Lock:               query.CopyLock(),            //  Indicates whether row locking is being used.
 		From:               query.CopyFrom(),            // The table which the query is targeting.
 		Columns:            query.CopyColumns(),         // The columns that should be returned. (Name or Expression)
@@ -296,11 +302,16 @@ func (query *Query) Clone() *Query {
 		Offset:             query.Offset,                // The number of records to skip.
 		Groups:             query.CopyGroups(),          // The groupings for the query.
 		Havings:            query.CopyHavings(),         // The having constraints for the query.
+		Windows:            query.CopyWindows(),         // The named window definitions for the query.
+		Returning:          query.CopyReturning(),       // The columns that should be returned by the write statements.
 		Bindings:           query.CopyBindings(),        // The current query value bindings.
 		Distinct:           query.Distinct,              // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct. default is false
 		DistinctColumns:    query.CopyDistinctColumns(), // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct.
 		IsJoinClause:       query.IsJoinClause,          // Determine if the query is a join clause.
 		BindingOffset:      query.BindingOffset,         // The Binding offset before select
+		Remember:           query.Remember,              // The caching option of the query results.
+		WithoutScopes:      query.CopyWithoutScopes(),   // The global scopes which are not applied to the query.
+		OnlyTrashed:        query.OnlyTrashed,           // Determine if only the soft deleted rows are selected.
 	}
 
 	// // new := NewQuery()
@@ -351,6 +362,22 @@ func (query *Query) CopyColumns() []interface{} {
 	return new
 }
 
+// CopyReturning copy Returning
+func (query *Query) CopyReturning() []interface{} {
+	if query.Returning == nil {
+		return nil
+	}
+	return append([]interface{}{}, query.Returning...)
+}
+
+// CopyWithoutScopes copy WithoutScopes
+func (query *Query) CopyWithoutScopes() []string {
+	if query.WithoutScopes == nil {
+		return nil
+	}
+	return append([]string{}, query.WithoutScopes...)
+}
+
 // CopyDistinctColumns copy DistinctColumns
 func (query *Query) CopyDistinctColumns() []interface{} {
 	new := []interface{}{}
@@ -387,6 +414,25 @@ func (query *Query) CopyUnions() []Union {
 	return new
 }
 
+// CopyCTEs copy CTEs
+func (query *Query) CopyCTEs() []CTE {
+	new := []CTE{}
+	for _, cte := range query.CTEs {
+		cte.Columns = append([]string{}, cte.Columns...)
+		new = append(new, cte)
+	}
+	return new
+}
+
+// CopyWindows copy Windows
+func (query *Query) CopyWindows() []Window {
+	new := []Window{}
+	for _, window := range query.Windows {
+		new = append(new, window.Copy())
+	}
+	return new
+}
+
 // CopyUnionOrders copy UnionOrders
 func (query *Query) CopyUnionOrders() []Order {
 	new := []Order{}
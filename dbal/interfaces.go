@@ -1,6 +1,9 @@
 package dbal
 
 import (
+	"context"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 )
 
@@ -13,6 +16,7 @@ type CreateTableOption struct {
 type Grammar interface {
 	NewWith(db *sqlx.DB, config *Config, option *Option) (Grammar, error)
 	NewWithRead(write *sqlx.DB, writeConfig *Config, read *sqlx.DB, readConfig *Config, option *Option) (Grammar, error)
+	WithExecutor(executor Executor) Grammar
 
 	Wrap(value interface{}) string
 	WrapTable(value interface{}) string
@@ -23,6 +27,9 @@ type Grammar interface {
 	GetDatabase() string
 	GetSchema() string
 	GetOperators() []string
+	SupportsWindowFunctions() bool
+	SupportsReturning(statement string) bool
+	GetMaxPlaceholders() int
 
 	// Grammar for migrating
 	GetTables() ([]string, error)
@@ -43,13 +50,37 @@ type Grammar interface {
 	CompileInsertUsing(query *Query, columns []interface{}, sql string) string
 	CompileUpsert(query *Query, columns []interface{}, values [][]interface{}, uniqueBy []interface{}, updateValues interface{}) (string, []interface{})
 	CompileUpdate(query *Query, values map[string]interface{}) (string, []interface{})
+	CompileUpdateBatch(query *Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{})
 	CompileDelete(query *Query) (string, []interface{})
 	CompileTruncate(query *Query) ([]string, [][]interface{})
 	CompileSelect(query *Query) string
 	CompileSelectOffset(query *Query, offset *int) string
 	CompileExists(query *Query) string
+	CompileReturning(query *Query, columns []interface{}) string
+	CompileExplain(sql string, option Explain) string
+	CompileRawSQL(sql string, bindings []interface{}) string
 
 	ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error)
+	ProcessExplain(rows []map[string]interface{}) (*Plan, error)
+
+	// Grammar for transactions
+	CompileSavepoint(name string) string
+	CompileSavepointRelease(name string) string
+	CompileSavepointRollBack(name string) string
+}
+
+// Executor the statements executor interface, implemented by both *sqlx.DB and *sqlx.Tx
+type Executor interface {
+	sqlx.Queryer
+	sqlx.QueryerContext
+	sqlx.Execer
+	sqlx.ExecerContext
+	sqlx.Preparer
+	sqlx.PreparerContext
+	Get(dest interface{}, query string, args ...interface{}) error
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	Select(dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 }
 
 // Quoter the database quoting query text intrface
@@ -60,8 +91,21 @@ type Quoter interface {
 	Wrap(value interface{}) string
 	WrapTable(value interface{}) string
 	WrapUnion(sql string) string
+	WrapJSONBooleanSelector(value string) string
+	WrapJSONBooleanValue(value string) string
+	WrapJSONSet(target string, path []string, parameter string) string
+	WrapFullText(fulltext FullText, table string, parameter string) string
+	WrapFullTextScore(fulltext FullText, table string, parameter string) string
 	IsExpression(value interface{}) bool
 	Parameter(value interface{}, num int) string
 	Parameterize(values []interface{}, offset int) string
 	Columnize(columns []interface{}) string
 }
+
+// Cache the cache store of the query results
+type Cache interface {
+	Get(key string) (interface{}, bool)
+	Set(key string, value interface{}, ttl time.Duration, tags ...string)
+	Forget(key string)
+	Flush(tags ...string)
+}
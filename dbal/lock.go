@@ -0,0 +1,25 @@
+package dbal
+
+// MakeLock make the lock of the given value, the string value is the lock mode. returns false if the value is not a lock or an empty string.
+func MakeLock(value interface{}) (Lock, bool) {
+	switch lock := value.(type) {
+	case Lock:
+		return lock, true
+	case *Lock:
+		if lock == nil {
+			return Lock{}, false
+		}
+		return *lock, true
+	case string:
+		if lock == "" {
+			return Lock{}, false
+		}
+		return Lock{Mode: lock, Tables: []string{}}, true
+	}
+	return Lock{}, false
+}
+
+// IsBasic Determine if the lock is a plain "share" or "update" lock without the waiting policy and the tables
+func (lock Lock) IsBasic() bool {
+	return (lock.Mode == "share" || lock.Mode == "update") && lock.Wait == "" && len(lock.Tables) == 0
+}
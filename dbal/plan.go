@@ -0,0 +1,38 @@
+package dbal
+
+// The normalized types of the plan nodes
+const (
+	PlanFullScan  = "full scan"
+	PlanIndexScan = "index scan"
+	PlanJoin      = "join"
+	PlanSort      = "sort"
+	PlanAggregate = "aggregate"
+	PlanOther     = "other"
+)
+
+// Walk Visit the node and the children of the node in depth-first order
+func (plan *Plan) Walk(visit func(node *Plan)) {
+	if plan == nil {
+		return
+	}
+	visit(plan)
+	for _, child := range plan.Children {
+		child.Walk(visit)
+	}
+}
+
+// FullScans Get the nodes which scan the full table
+func (plan *Plan) FullScans() []*Plan {
+	nodes := []*Plan{}
+	plan.Walk(func(node *Plan) {
+		if node.Type == PlanFullScan {
+			nodes = append(nodes, node)
+		}
+	})
+	return nodes
+}
+
+// HasFullScan Determine if the plan scans a full table
+func (plan *Plan) HasFullScan() bool {
+	return len(plan.FullScans()) > 0
+}
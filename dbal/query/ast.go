@@ -0,0 +1,37 @@
+package query
+
+import (
+	"encoding/json"
+
+	"github.com/yaoapp/xun/utils"
+)
+
+// FromAST Create a new query builder using the given connection, and rebuild the query from the query AST made by ToAST.
+// The query AST could be replayed against any registered grammar, but the sub-queries compiled into SQL are kept as they are.
+func FromAST(conn *Connection, ast []byte) (Query, error) {
+	builder := useBuilder(conn)
+	err := json.Unmarshal(ast, builder.Query)
+	if err != nil {
+		return nil, err
+	}
+	return builder, nil
+}
+
+// MustFromAST Create a new query builder using the given connection, and rebuild the query from the query AST made by ToAST.
+func MustFromAST(conn *Connection, ast []byte) Query {
+	qb, err := FromAST(conn, ast)
+	utils.PanicIF(err)
+	return qb
+}
+
+// ToAST Get the versioned JSON representation of the query, the query could be rebuilt by FromAST.
+func (builder *Builder) ToAST() ([]byte, error) {
+	return json.Marshal(builder.Query)
+}
+
+// MustToAST Get the versioned JSON representation of the query.
+func (builder *Builder) MustToAST() []byte {
+	ast, err := builder.ToAST()
+	utils.PanicIF(err)
+	return ast
+}
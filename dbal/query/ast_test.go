@@ -0,0 +1,105 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+)
+
+func TestASTRoundTrip(t *testing.T) {
+	qb := getTestBuilder()
+	created := time.Date(2021, 3, 25, 8, 30, 15, 0, time.UTC)
+	qb.Table("table_test_paginate as t1").
+		Select("t1.id", "t1.name", dbal.Raw("count(*) as total")).
+		SelectAppend(dbal.Over("row_number").PartitionBy("t1.status").OrderBy("t1.vote", "desc").As("rank")).
+		Join("table_test_paginate_t2 as t2", "t2.t1_id", "=", "t1.id").
+		Where("t1.vote", ">", 5).
+		Where(func(qb Query) {
+			qb.Where("t1.status", "DONE").OrWhereNull("t1.deleted_at")
+		}).
+		WhereIn("t1.id", func(qb Query) {
+			qb.Select("t1_id").From("table_test_paginate_t2").Where("status", "WAITING")
+		}).
+		WhereBetween("t1.created_at", []interface{}{created, created.Add(time.Hour)}).
+		WhereRaw("t1.score > ?", 10.5).
+		GroupBy("t1.id", "t1.name").
+		Having("total", ">", int64(1)).
+		Union(func(qb Query) {
+			qb.Select("id", "name", dbal.Raw("0 as total"), dbal.Raw("0 as rank")).From("table_test_paginate").Where("email", []byte("ben@yao.run"))
+		}).
+		OrderBy("t1.id").
+		Limit(10).
+		Offset(5).
+		LockForUpdate()
+
+	sql := qb.ToSQL()
+	bindings := qb.GetBindings()
+	ast, err := qb.ToAST()
+	assert.Nil(t, err)
+
+	replayed, err := FromAST(qb.Builder().Conn, ast)
+	assert.Nil(t, err)
+	assert.Equal(t, sql, replayed.ToSQL())
+	assert.Equal(t, bindings, replayed.GetBindings())
+	assert.Equal(t, ast, replayed.MustToAST(), "the query AST should be stable")
+}
+
+func TestASTRoundTripFullText(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_fulltext").
+		Select("id").
+		SelectFullTextScore("title", "database", "score").
+		WhereFullText("title,content", "database").
+		OrderByFullTextScore("title", "database")
+
+	ast := qb.MustToAST()
+	replayed := MustFromAST(qb.Builder().Conn, ast)
+	assert.Equal(t, qb.ToSQL(), replayed.ToSQL())
+	assert.Equal(t, qb.GetBindings(), replayed.GetBindings())
+}
+
+func TestASTRoundTripJoins(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate as t1").
+		Select("t1.id", "t2.status").
+		Join("table_test_paginate_t2 as t2", func(join Query) {
+			join.On("t2.t1_id", "=", "t1.id").Where("t2.status", "WAITING")
+		}).
+		JoinSub(func(qb Query) {
+			qb.Select("t1_id").From("table_test_paginate_t2").Where("id", ">", 1)
+		}, "t3", "t3.t1_id", "=", "t1.id").
+		LeftJoinSub(func(qb Query) {
+			qb.Select("t1_id").From("table_test_paginate_t2").Where("status", "DONE")
+		}, "t4", "t4.t1_id", "=", "t1.id").
+		CrossJoinSub(func(qb Query) {
+			qb.SelectRaw("max(vote) as top").From("table_test_paginate")
+		}, "t5").
+		Where("t1.vote", ">", 5)
+
+	ast, err := qb.ToAST()
+	assert.Nil(t, err)
+
+	replayed, err := FromAST(qb.Builder().Conn, ast)
+	assert.Nil(t, err)
+	assert.Equal(t, qb.ToSQL(), replayed.ToSQL())
+	assert.Equal(t, qb.GetBindings(), replayed.GetBindings())
+	assert.Equal(t, ast, replayed.MustToAST(), "the query AST should be stable")
+
+	rows, err := replayed.Get()
+	assert.Nil(t, err)
+	expected, err := qb.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, expected, rows)
+}
+
+func TestASTVersion(t *testing.T) {
+	qb := getTestBuilder()
+	_, err := FromAST(qb.Builder().Conn, []byte(`{"version":0,"from":{"type":"basic"}}`))
+	assert.Error(t, err)
+
+	_, err = qb.Table("table_test_paginate").Where("id", struct{ ID int }{1}).ToAST()
+	assert.Error(t, err, "the struct value could not be encoded")
+}
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/dbal/schema"
 	"github.com/yaoapp/xun/unit"
 )
@@ -32,6 +33,21 @@ func getTestBuilderInstance() *Builder {
 	return testQueryBuilderInstance
 }
 
+// getGrammarTestBuilder get a query builder of the given grammar without the connection, for checking the compiled SQL and bindings
+func getGrammarTestBuilder(driver string) Query {
+	return &Builder{
+		Mode:    "production",
+		Conn:    &Connection{Option: &dbal.Option{}, Scopes: NewScopes()},
+		Grammar: dbal.Grammars[driver],
+		Query:   dbal.NewQuery(),
+	}
+}
+
+// getPostgresTestBuilder get a query builder of the PostgreSQL grammar without the connection, for checking the numbered placeholders
+func getPostgresTestBuilder() Query {
+	return getGrammarTestBuilder("postgres")
+}
+
 func getTestSchemaBuilder() schema.Schema {
 	defer unit.Catch()
 	unit.SetLogger()
@@ -0,0 +1,213 @@
+package query
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+)
+
+// DefaultCache the cache store of the query results used by the connections without a cache store
+var DefaultCache dbal.Cache = dbal.NewLRUCache(1024)
+
+// cacheAnyTable the tag of the cached results which the tables could not be determined, flushed by the writes of any table
+const cacheAnyTable = "*"
+
+// Remember Cache the results of the query for the given duration. The cached results of a table are flushed
+// by the Insert, Update, Delete, Upsert and Truncate methods of the builders using the same cache store.
+// The queries within a transaction and the results bound to a struct are not cached.
+// Remember(5 * time.Minute).Count()
+func (builder *Builder) Remember(ttl time.Duration) Query {
+	builder.Query.Remember = &dbal.Remember{TTL: ttl}
+	return builder
+}
+
+// RememberForever Cache the results of the query until the table is changed, the results could be flushed by the key.
+// RememberForever("dashboard").Sum("amount")
+func (builder *Builder) RememberForever(key string) Query {
+	builder.Query.Remember = &dbal.Remember{Key: key}
+	return builder
+}
+
+// UseCache Set the cache store of the query results for the connection.
+func (builder *Builder) UseCache(store dbal.Cache) Query {
+	builder.Conn.Cache = store
+	return builder
+}
+
+// FlushCache Flush the cached results of the given tables, all of the cached results are flushed if the tables are not given.
+func (builder *Builder) FlushCache(tables ...string) {
+	if len(tables) == 0 {
+		builder.cache().Flush()
+		return
+	}
+
+	tags := []string{cacheAnyTable}
+	for _, table := range tables {
+		tags = append(tags, dbal.NewName(table, builder.Conn.Option.Prefix).Fullname())
+	}
+	builder.cache().Flush(tags...)
+}
+
+// cache Get the cache store of the connection
+func (builder *Builder) cache() dbal.Cache {
+	if builder.Conn.Cache != nil {
+		return builder.Conn.Cache
+	}
+	return DefaultCache
+}
+
+// remembered Determine if the results of the query should be cached
+func (builder *Builder) remembered(v ...interface{}) bool {
+	return builder.Query.Remember != nil && builder.Tx == nil && (len(v) == 0 || v[0] == nil)
+}
+
+// getRemembered Get the cached results of the query, the query will be executed and cached if the results are missing.
+func (builder *Builder) getRemembered() ([]xun.R, error) {
+	remember := builder.Query.Remember
+	key := builder.cacheKey()
+	if value, has := builder.cache().Get(key); has {
+		return copyRows(value.([]xun.R)), nil
+	}
+
+	qb := builder.clone()
+	qb.Query.Remember = nil
+	rows, err := qb.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	tags := cacheTables(builder.Query)
+	if remember.Key != "" {
+		tags = append(tags, remember.Key)
+	}
+	builder.cache().Set(key, copyRows(rows), remember.TTL, tags...)
+	return rows, nil
+}
+
+// cacheKey Get the cache key of the query, the statement and the bindings of the query on the connection.
+func (builder *Builder) cacheKey() string {
+	hash := sha1.New()
+	hash.Write([]byte(fmt.Sprintf("%p\n%s\n%#v", builder.Conn, builder.ToSQL(), builder.GetBindings())))
+	key := hex.EncodeToString(hash.Sum(nil))
+	if builder.Query.Remember.Key != "" {
+		return fmt.Sprintf("%s:%s", builder.Query.Remember.Key, key)
+	}
+	return key
+}
+
+// flushCache Flush the cached results of the table which the write statement is targeting.
+// The results are flushed after the transaction is committed if the builder was bound to a transaction,
+// otherwise the stale rows could be cached again by the readers before the commit.
+func (builder *Builder) flushCache() {
+	tags := []string{}
+	if name, ok := builder.Query.From.Name.(dbal.Name); ok && builder.Query.From.Type == "basic" {
+		tags = append(tags, name.Fullname(), cacheAnyTable)
+	}
+
+	cache := builder.cache()
+	if builder.Tx != nil {
+		builder.Tx.AfterCommit(func() { cache.Flush(tags...) })
+		return
+	}
+	cache.Flush(tags...)
+}
+
+// cacheTables Get the tables of the query as the tags of the cached results.
+// The query is tagged with "*" if the tables of the sub-queries or the raw expressions could not be determined.
+func cacheTables(query *dbal.Query) []string {
+	tables := []string{}
+	opaque := len(query.CTEs) > 0
+
+	if name, ok := query.From.Name.(dbal.Name); ok && query.From.Type == "basic" {
+		tables = append(tables, name.Fullname())
+	} else if sub, ok := query.From.Name.(*dbal.Query); ok {
+		tables = append(tables, cacheTables(sub)...)
+	} else {
+		opaque = true
+	}
+
+	for _, join := range query.Joins {
+		if sub, ok := join.Name.(*dbal.Query); ok {
+			tables = append(tables, cacheTables(sub)...)
+			continue
+		}
+		name, ok := join.Name.(dbal.Name)
+		if !ok {
+			opaque = true
+			continue
+		}
+		tables = append(tables, name.Fullname())
+	}
+
+	for _, union := range query.Unions {
+		tables = append(tables, cacheTables(union.Query)...)
+	}
+
+	for _, column := range query.Columns {
+		if dbal.IsExpression(column) {
+			opaque = true
+		} else if sub, ok := column.(dbal.Select); ok {
+			tables = append(tables, cacheSubTables(sub.Name)...)
+		}
+	}
+
+	for _, order := range query.Orders {
+		tables = append(tables, cacheSubTables(order.Column)...)
+	}
+
+	wheres, whereOpaque := cacheWhereTables(query.Wheres)
+	tables = append(tables, wheres...)
+	if opaque || whereOpaque {
+		tables = append(tables, cacheAnyTable)
+	}
+	return tables
+}
+
+// cacheWhereTables Get the tables of the sub-queries in the where clauses, returns true if the where clauses have the raw expressions.
+func cacheWhereTables(wheres []dbal.Where) ([]string, bool) {
+	tables := []string{}
+	opaque := false
+	for _, where := range wheres {
+		switch where.Type {
+		case "raw":
+			opaque = true
+		case "sub", "exists":
+			tables = append(tables, cacheTables(where.Query)...)
+		case "nested":
+			nested, nestedOpaque := cacheWhereTables(where.Query.Wheres)
+			tables = append(tables, nested...)
+			opaque = opaque || nestedOpaque
+		case "in":
+			opaque = opaque || dbal.IsExpression(where.ValuesIn)
+			tables = append(tables, cacheSubTables(where.ValuesIn)...)
+		case "basic":
+			tables = append(tables, cacheSubTables(where.Column)...)
+		}
+	}
+	return tables, opaque
+}
+
+// cacheSubTables Get the tables of the value if it is a sub-query
+func cacheSubTables(value interface{}) []string {
+	if sub, ok := value.(*dbal.Query); ok {
+		return cacheTables(sub)
+	}
+	return nil
+}
+
+// copyRows Copy the rows, so the cached rows would not be changed by the callers
+func copyRows(rows []xun.R) []xun.R {
+	new := make([]xun.R, 0, len(rows))
+	for _, row := range rows {
+		copied := xun.R{}
+		for key, value := range row {
+			copied[key] = value
+		}
+		new = append(new, copied)
+	}
+	return new
+}
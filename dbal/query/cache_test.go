@@ -0,0 +1,172 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+)
+
+func TestCacheRemember(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	store := dbal.NewLRUCache(16)
+	qb.UseCache(store)
+	defer qb.UseCache(nil)
+
+	count := qb.Table("table_test_paginate").Remember(time.Minute).MustCount()
+	assert.Equal(t, int64(4), count)
+
+	// The changes out of the builder are not seen until the cache is flushed
+	qb.DB().MustExec("delete from table_test_paginate where name = 'Ben'")
+	count = qb.Table("table_test_paginate").Remember(time.Minute).MustCount()
+	assert.Equal(t, int64(4), count)
+	assert.Equal(t, int64(3), qb.Table("table_test_paginate").MustCount())
+
+	// The count query of the paginator is the same as the cached one
+	paginator := qb.Table("table_test_paginate").Remember(time.Minute).OrderBy("id").MustPaginate(2, 1)
+	assert.Equal(t, 4, paginator.Total)
+	assert.Equal(t, 2, store.Len())
+
+	// The writes of the builder flush the cached results of the table
+	qb.Table("table_test_paginate").MustInsert(xun.R{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27})
+	assert.Equal(t, 0, store.Len())
+	count = qb.Table("table_test_paginate").Remember(time.Minute).MustCount()
+	assert.Equal(t, int64(4), count)
+
+	qb.Table("table_test_paginate").Where("name", "Ben").MustUpdate(xun.R{"vote": 7})
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestCacheRememberForever(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	store := dbal.NewLRUCache(16)
+	qb.UseCache(store)
+	defer qb.UseCache(nil)
+
+	sum := qb.Table("table_test_paginate").RememberForever("dashboard").MustSum("vote")
+	assert.Equal(t, 146, sum.MustInt())
+
+	rows := qb.Table("table_test_paginate").RememberForever("dashboard").Where("vote", ">", 6).OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows))
+	rows[0]["name"] = "Changed"
+	rows = qb.Table("table_test_paginate").RememberForever("dashboard").Where("vote", ">", 6).OrderBy("id").MustGet()
+	assert.Equal(t, "John", rows[0].Get("name"), "the cached rows should not be changed by the callers")
+	assert.Equal(t, 2, store.Len())
+
+	// The results are flushed by the key, the other tables and the transactions are not cached
+	qb.Table("table_test_paginate_t2").MustDelete()
+	assert.Equal(t, 2, store.Len())
+	store.Flush("dashboard")
+	assert.Equal(t, 0, store.Len())
+
+	qb.Transaction(func(qb Query) error {
+		qb.Table("table_test_paginate").RememberForever("dashboard").MustCount()
+		return nil
+	})
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestCacheRememberRaw(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	store := dbal.NewLRUCache(16)
+	qb.UseCache(store)
+	defer qb.UseCache(nil)
+
+	qb.Table("table_test_paginate").Remember(time.Minute).WhereRaw("vote > 6").MustGet()
+	qb.Table("table_test_paginate").Remember(time.Minute).MustGet()
+	assert.Equal(t, 2, store.Len())
+
+	// The results of the raw expressions are flushed by the writes of any table
+	qb.Table("table_test_paginate_t2").MustDelete()
+	assert.Equal(t, 1, store.Len())
+
+	qb.FlushCache("table_test_paginate")
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestCacheRememberTransaction(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	store := dbal.NewLRUCache(16)
+	qb.UseCache(store)
+	defer qb.UseCache(nil)
+
+	qb.Table("table_test_paginate").Remember(time.Minute).MustCount()
+	assert.Equal(t, 1, store.Len())
+
+	// The cached results are flushed after the outermost transaction is committed
+	tx := qb.MustBegin()
+	nested := tx.MustBegin()
+	nested.Table("table_test_paginate").Where("name", "Ben").MustUpdate(xun.R{"vote": 7})
+	nested.MustCommit()
+	assert.Equal(t, 1, store.Len())
+	tx.MustCommit()
+	assert.Equal(t, 0, store.Len())
+
+	// The cached results are kept if the transaction is rolled back
+	qb.Table("table_test_paginate").Remember(time.Minute).MustCount()
+	qb.Transaction(func(qb Query) error {
+		qb.Table("table_test_paginate").MustDelete()
+		return fmt.Errorf("rollback")
+	})
+	assert.Equal(t, 1, store.Len())
+	assert.Equal(t, int64(4), qb.Table("table_test_paginate").MustCount())
+}
+
+func TestCacheRememberSubQuery(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	store := dbal.NewLRUCache(16)
+	qb.UseCache(store)
+	defer qb.UseCache(nil)
+
+	get := func() int64 {
+		row := qb.Table("table_test_paginate").Remember(time.Minute).
+			Select("id").
+			SelectSub(func(sub Query) { sub.From("table_test_paginate_t2").SelectRaw("count(*)") }, "total").
+			OrderBy("id").
+			MustFirst()
+		return xun.MakeN(row["total"]).MustInt64()
+	}
+
+	total := get()
+	assert.Equal(t, 1, store.Len())
+
+	// The writes of the tables of the sub-queries flush the cached results
+	qb.Table("table_test_paginate_t2").MustInsert(xun.R{"t1_id": 1, "name": "sub"})
+	assert.Equal(t, 0, store.Len())
+	assert.Equal(t, total+1, get())
+}
+
+func TestCacheLRU(t *testing.T) {
+	store := dbal.NewLRUCache(2)
+	store.Set("a", 1, 0, "users")
+	store.Set("b", 2, 0, "users")
+	store.Get("a")
+	store.Set("c", 3, 0, "posts")
+
+	_, has := store.Get("b")
+	assert.False(t, has, "the least recently used entry should be evicted")
+	value, has := store.Get("a")
+	assert.True(t, has)
+	assert.Equal(t, 1, value)
+
+	store.Flush("users")
+	_, has = store.Get("a")
+	assert.False(t, has)
+	assert.Equal(t, 1, store.Len())
+
+	store.Set("d", 4, time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, has = store.Get("d")
+	assert.False(t, has, "the entry should be expired")
+
+	store.Forget("c")
+	assert.Equal(t, 0, store.Len())
+}
@@ -1,6 +1,9 @@
 package query
 
-import "github.com/jmoiron/sqlx"
+import (
+	"github.com/jmoiron/sqlx"
+	"github.com/yaoapp/xun/dbal"
+)
 
 // DB Get the sqlx.DB pointer instance
 func (builder *Builder) DB(usewrite ...bool) *sqlx.DB {
@@ -10,6 +13,14 @@ func (builder *Builder) DB(usewrite ...bool) *sqlx.DB {
 	return builder.Conn.Read
 }
 
+// executor Get the statements executor, the transaction will be used if the builder was bound to a transaction.
+func (builder *Builder) executor(usewrite ...bool) dbal.Executor {
+	if builder.Tx != nil {
+		return builder.Tx.Tx
+	}
+	return builder.DB(usewrite...)
+}
+
 // UseWrite Use the write connection for query.
 func (builder *Builder) UseWrite() Query {
 	builder.Query.UseWriteConnection = true
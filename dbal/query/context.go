@@ -0,0 +1,23 @@
+package query
+
+import (
+	"context"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// WithContext Create a new query builder bound to the given context, every statement executed by the builder and the builders derived from it will carry the context.
+func (builder *Builder) WithContext(ctx context.Context) Query {
+	new := builder.clone()
+	new.Context = ctx
+	new.Grammar = builder.Grammar.WithExecutor(dbal.WithContext(ctx, builder.executor(true)))
+	return new
+}
+
+// ctx Get the context of the builder, returns the background context if the context was not set.
+func (builder *Builder) ctx() context.Context {
+	if builder.Context == nil {
+		return context.Background()
+	}
+	return builder.Context
+}
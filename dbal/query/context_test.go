@@ -0,0 +1,70 @@
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+)
+
+func TestContextWithContext(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	ctx := context.Background()
+	id, err := qb.WithContext(ctx).Table("table_test_transaction").InsertGetID(xun.R{"email": "john@yao.run", "vote": 10})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), id)
+
+	rows, err := qb.WithContext(ctx).Table("table_test_transaction").Where("id", id).Get()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(rows))
+	assert.Nil(t, qb.Builder().Context, "The context should not be set to the origin builder")
+}
+
+func TestContextCanceled(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ctxqb := qb.WithContext(ctx)
+	_, err := ctxqb.Table("table_test_transaction").Get()
+	assert.Equal(t, context.Canceled, err)
+
+	err = ctxqb.Table("table_test_transaction").Insert(xun.R{"email": "john@yao.run", "vote": 10})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = ctxqb.Table("table_test_transaction").InsertGetID(xun.R{"email": "john@yao.run", "vote": 10})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = ctxqb.Table("table_test_transaction").Where("id", 1).Update(xun.R{"vote": 5})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = ctxqb.Table("table_test_transaction").Where("id", 1).Delete()
+	assert.Equal(t, context.Canceled, err)
+
+	err = ctxqb.Table("table_test_transaction").OrderBy("id").Chunk(10, func(items []interface{}, page int) error {
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	_, err = ctxqb.Begin()
+	assert.Equal(t, context.Canceled, err)
+
+	assert.Equal(t, int64(0), qb.Table("table_test_transaction").MustCount())
+}
+
+func TestContextTransaction(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := qb.WithContext(ctx).Transaction(func(tx Query) error {
+		_, err := tx.Table("table_test_transaction").InsertGetID(xun.R{"email": "john@yao.run", "vote": 10})
+		return err
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), qb.Table("table_test_transaction").MustCount())
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Cursor Execute the query as a "select" statement and return a cursor to iterate the results one row at a time.
+func (builder *Builder) Cursor() (*Cursor, error) {
+	sql := builder.ToSQL()
+	bindings := builder.GetBindings()
+	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
+
+	rows, err := builder.executor().QueryContext(builder.ctx(), sql, bindings...)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+
+	return &Cursor{
+		builder: builder,
+		rows:    rows,
+		columns: columns,
+		values:  builder.makeMapValues(len(columns)),
+	}, nil
+}
+
+// MustCursor Execute the query as a "select" statement and return a cursor to iterate the results one row at a time.
+func (builder *Builder) MustCursor() *Cursor {
+	cursor, err := builder.Cursor()
+	utils.PanicIF(err)
+	return cursor
+}
+
+// Next Prepare the next row for reading with the Scan method. It returns false when there are no more rows or an error occurred.
+func (cursor *Cursor) Next() bool {
+	if cursor.err != nil {
+		return false
+	}
+	return cursor.rows.Next()
+}
+
+// Scan Copy the columns of the current row into the given pointer (*xun.R, struct pointer or a single value pointer).
+func (cursor *Cursor) Scan(v interface{}) error {
+	err := cursor.scan(v)
+	if err != nil {
+		cursor.err = err
+	}
+	return err
+}
+
+// MustScan Copy the columns of the current row into the given pointer (*xun.R, struct pointer or a single value pointer).
+func (cursor *Cursor) MustScan(v interface{}) {
+	err := cursor.Scan(v)
+	utils.PanicIF(err)
+}
+
+// Columns Get the column names of the results.
+func (cursor *Cursor) Columns() []string {
+	return cursor.columns
+}
+
+// Err Get the error, if any, that was encountered during iteration.
+func (cursor *Cursor) Err() error {
+	if cursor.err != nil {
+		return cursor.err
+	}
+	return cursor.rows.Err()
+}
+
+// Close Close the cursor and release the connection, it is safe to call Close more than once.
+func (cursor *Cursor) Close() error {
+	return cursor.rows.Close()
+}
+
+// scan the current row into the given pointer
+func (cursor *Cursor) scan(v interface{}) error {
+	switch dest := v.(type) {
+	case *xun.R:
+		row, err := cursor.builder.mapScanRow(cursor.rows, cursor.columns, cursor.values)
+		if err != nil {
+			return err
+		}
+		*dest = row
+		return nil
+	case *map[string]interface{}:
+		row, err := cursor.builder.mapScanRow(cursor.rows, cursor.columns, cursor.values)
+		if err != nil {
+			return err
+		}
+		*dest = row
+		return nil
+	}
+
+	structType, isStruct, err := cursor.builder.getStructType(v)
+	if err != nil {
+		return err
+	}
+
+	if reflect.Indirect(reflect.ValueOf(v)).Kind() == reflect.Slice {
+		return fmt.Errorf("scan: the dest type is slice, the cursor scans one row at a time")
+	}
+
+	if !isStruct {
+		if len(cursor.columns) != 1 {
+			return fmt.Errorf("scan: the dest type is %s, but the results have %d columns", structType.Kind().String(), len(cursor.columns))
+		}
+		return cursor.rows.Scan(v)
+	}
+
+	fieldMap, err := cursor.builder.getFieldMap(structType)
+	if err != nil {
+		return err
+	}
+
+	values, err := cursor.builder.makeStructValues(reflect.ValueOf(v), fieldMap, cursor.columns, nil)
+	if err != nil {
+		return err
+	}
+	return cursor.rows.Scan(values...)
+}
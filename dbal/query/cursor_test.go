@@ -0,0 +1,91 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+)
+
+func TestCursorMap(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_paginate").
+		Select("id", "name", "email").
+		OrderBy("id").
+		MustCursor()
+	defer cursor.Close()
+
+	IDs := []int64{}
+	for cursor.Next() {
+		row := xun.R{}
+		cursor.MustScan(&row)
+		IDs = append(IDs, row.Get("id").(int64))
+	}
+	assert.Nil(t, cursor.Err())
+	assert.Equal(t, []int64{1, 2, 3, 4}, IDs, "The ids should be []int64{1,2,3,4}")
+	assert.Equal(t, []string{"id", "name", "email"}, cursor.Columns())
+}
+
+func TestCursorStruct(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_paginate").
+		Select("id", "email", "vote", "status").
+		OrderByDesc("id").
+		MustCursor()
+	defer cursor.Close()
+
+	type Item struct {
+		ID            int64
+		Email         string
+		Vote          int
+		PaymentStatus string `json:"status"`
+	}
+
+	items := []Item{}
+	for cursor.Next() {
+		item := Item{}
+		cursor.MustScan(&item)
+		items = append(items, item)
+	}
+	assert.Nil(t, cursor.Err())
+	assert.Equal(t, 4, len(items))
+	if len(items) == 4 {
+		assert.Equal(t, int64(4), items[0].ID)
+		assert.Equal(t, "ben@yao.run", items[0].Email)
+		assert.Equal(t, "DONE", items[0].PaymentStatus)
+	}
+}
+
+func TestCursorValue(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_paginate").Select("id").OrderBy("id").MustCursor()
+	defer cursor.Close()
+
+	sum := int64(0)
+	for cursor.Next() {
+		var id int64
+		cursor.MustScan(&id)
+		sum = sum + id
+	}
+	assert.Equal(t, int64(10), sum)
+}
+
+func TestCursorScanError(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	cursor := qb.Table("table_test_paginate").Select("id", "email").OrderBy("id").MustCursor()
+	defer cursor.Close()
+
+	type Item struct {
+		ID int64
+	}
+
+	assert.True(t, cursor.Next())
+	err := cursor.Scan(&Item{})
+	assert.NotNil(t, err, "The missing field should return an error")
+	assert.Equal(t, err, cursor.Err())
+	assert.False(t, cursor.Next(), "The cursor should stop when an error occurred")
+}
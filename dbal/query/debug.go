@@ -4,26 +4,135 @@ import (
 	"fmt"
 	"os"
 	"runtime/debug"
+	"strings"
 
 	"github.com/fatih/color"
 	"github.com/yaoapp/xun/utils"
 )
 
-// DD Die and dump the current SQL and bindings.
-func (builder *Builder) DD() {
+// prettyKeywords the keywords which start a new line of the pretty printed SQL, the longer ones go first.
+var prettyKeywords = []string{
+	"on duplicate key update", "union all", "inner join", "left join", "right join", "cross join", "group by", "order by", "on conflict",
+	"select", "from", "where", "having", "window", "limit", "offset", "union", "join", "values", "set", "returning", "and", "or",
+}
+
+// DD Die and dump the current SQL with the bindings inlined. The SQL is pretty printed if pretty is true.
+// DD()
+// DD(true)
+func (builder *Builder) DD(pretty ...bool) {
 	defer os.Exit(0)
-	builder.Dump()
+	builder.Dump(pretty...)
 	os.Exit(0)
 }
 
-// Dump Dump the current SQL and bindings.
-func (builder *Builder) Dump() {
+// Dump Dump the current SQL with the bindings inlined, the bindings and the results. The SQL is pretty printed if pretty is true.
+// Dump()
+// Dump(true)
+func (builder *Builder) Dump(pretty ...bool) {
 	defer catch()
-	fmt.Println(builder.ToSQL())
+	sql := builder.ToRawSQL()
+	if len(pretty) > 0 && pretty[0] {
+		sql = prettySQL(sql)
+	}
+	fmt.Println(sql)
 	utils.Println(builder.GetBindings())
 	utils.Println(builder.MustGet())
 }
 
+// ToRawSQL Get the SQL of the query with the bindings inlined, the values are escaped by the quoter of the grammar.
+// For debugging only, the SQL should not be executed.
+func (builder *Builder) ToRawSQL() string {
+	return builder.Grammar.CompileRawSQL(builder.ToSQL(), builder.GetBindings())
+}
+
+// prettySQL Break the SQL into lines before the clause keywords, the "and" and "or" of the conditions are indented.
+// The clauses of the sub-queries are indented by the depth, the quoted strings and identifiers are not changed.
+func prettySQL(sql string) string {
+	var pretty strings.Builder
+	subqueries := []bool{true}
+	between := false
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch c {
+		case '\'', '"', '`':
+			end := i + 1
+			for end < len(sql) && sql[end] != c {
+				if sql[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			pretty.WriteString(sql[i : end+1])
+			i = end
+			continue
+
+		case '(':
+			word := strings.ToLower(strings.TrimLeft(sql[i+1:], " "))
+			subqueries = append(subqueries, strings.HasPrefix(word, "select ") || strings.HasPrefix(word, "with "))
+			pretty.WriteByte(c)
+			continue
+
+		case ')':
+			if len(subqueries) > 1 {
+				subqueries = subqueries[:len(subqueries)-1]
+			}
+			pretty.WriteByte(c)
+			continue
+		}
+
+		keyword := ""
+		if i == 0 || sql[i-1] == ' ' {
+			keyword = prettyKeyword(sql[i:])
+			if len(sql) > i+8 && strings.EqualFold(sql[i:i+8], "between ") {
+				between = true
+			}
+		}
+		if keyword == "" || !subqueries[len(subqueries)-1] {
+			pretty.WriteByte(c)
+			continue
+		}
+
+		// The "and" of the between condition is not a new condition
+		name := strings.ToLower(keyword)
+		if name == "and" && between {
+			between = false
+			pretty.WriteString(keyword)
+			i = i + len(keyword) - 1
+			continue
+		}
+
+		if i > 0 {
+			text := strings.TrimRight(pretty.String(), " ")
+			pretty.Reset()
+			pretty.WriteString(text)
+			pretty.WriteString("\n")
+			pretty.WriteString(strings.Repeat("  ", len(subqueries)-1))
+			if name == "and" || name == "or" {
+				pretty.WriteString("  ")
+			}
+		}
+		pretty.WriteString(keyword)
+		i = i + len(keyword) - 1
+	}
+	return pretty.String()
+}
+
+// prettyKeyword Get the keyword at the start of the SQL, returns an empty string if the SQL does not start with a keyword.
+func prettyKeyword(sql string) string {
+	for _, keyword := range prettyKeywords {
+		if len(sql) < len(keyword) || !strings.EqualFold(sql[:len(keyword)], keyword) {
+			continue
+		}
+		if len(sql) == len(keyword) || sql[len(keyword)] == ' ' || sql[len(keyword)] == '(' {
+			return sql[:len(keyword)]
+		}
+	}
+	return ""
+}
+
 // catch and out
 func catch() {
 	if r := recover(); r != nil {
@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun"
@@ -79,3 +80,43 @@ func NewTableForDebugTest() {
 		{"email": "ben@yao.run", "name": "Ben", "vote": 6, "score": 48.12, "score_grade": 99.27, "status": "DONE", "created_at": "2021-03-25 18:15:29"},
 	})
 }
+
+func TestDebugToRawSQL(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_debug").
+		Where("name", "it's").
+		Where("vote", ">", 5).
+		Where("score", 96.5).
+		WhereNotNull("email").
+		WhereRaw("status <> ? and email like '%?%'", "DONE").
+		Where("created_at", time.Date(2021, 3, 25, 8, 30, 15, 0, time.UTC)).
+		Where("deleted", false)
+
+	sql := qb.ToRawSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_debug" where "name" = 'it''s' and "vote" > 5 and "score" = 96.5 and "email" is not null and status <> 'DONE' and email like '%?%' and "created_at" = '2021-03-25 08:30:15+00:00' and "deleted" = false`, sql)
+	} else if unit.DriverIs("mysql") {
+		assert.Equal(t, "select * from `table_test_debug` where `name` = 'it\\'s' and `vote` > 5 and `score` = 96.5 and `email` is not null and status <> 'DONE' and email like '%?%' and `created_at` = '2021-03-25 08:30:15' and `deleted` = false", sql)
+	} else {
+		assert.Equal(t, "select * from `table_test_debug` where `name` = 'it''s' and `vote` > 5 and `score` = 96.5 and `email` is not null and status <> 'DONE' and email like '%?%' and `created_at` = '2021-03-25 08:30:15+00:00' and `deleted` = false", sql)
+	}
+
+	// The bindings of the query are not changed
+	assert.Equal(t, 6, len(qb.GetBindings()))
+}
+
+func TestDebugPrettySQL(t *testing.T) {
+	sql := "select `name` from `users` as `u` left join `orders` as `o` on `o`.`user_id` = `u`.`id` and `o`.`amount` > 100 " +
+		"where `vote` between 1 and 5 or `id` in (select `user_id` from `admins` where `level` > 1) and `note` = 'from where' order by `id` desc limit 10"
+	assert.Equal(t, "select `name`\n"+
+		"from `users` as `u`\n"+
+		"left join `orders` as `o` on `o`.`user_id` = `u`.`id`\n"+
+		"  and `o`.`amount` > 100\n"+
+		"where `vote` between 1 and 5\n"+
+		"  or `id` in (select `user_id`\n"+
+		"  from `admins`\n"+
+		"  where `level` > 1)\n"+
+		"  and `note` = 'from where'\n"+
+		"order by `id` desc\n"+
+		"limit 10", prettySQL(sql))
+}
@@ -5,12 +5,25 @@ import (
 	"github.com/yaoapp/xun/utils"
 )
 
-// Delete Delete records from the database.
+// Delete Delete records from the database. The records are soft deleted if the table supports soft deletes.
 func (builder *Builder) Delete() (int64, error) {
-	sql, bindings := builder.Grammar.CompileDelete(builder.Query)
+	if err := builder.checkReturning("Delete"); err != nil {
+		return 0, err
+	}
+	if column := builder.trashedColumn(); column != "" {
+		return builder.Update(builder.trashedValues(column))
+	}
+	return builder.delete()
+}
+
+// delete Execute the delete statement
+func (builder *Builder) delete() (int64, error) {
+	defer builder.flushCache()
+	sql, bindings := builder.Grammar.CompileDelete(builder.scoped().Query)
 	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
 
-	res, err := builder.UseWrite().DB().Exec(sql, bindings...)
+	builder.UseWrite()
+	res, err := builder.executor().ExecContext(builder.ctx(), sql, bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -27,10 +40,12 @@ func (builder *Builder) MustDelete() int64 {
 
 // Truncate Run a truncate statement on the table.
 func (builder *Builder) Truncate() error {
+	defer builder.flushCache()
 	sqls, bindings := builder.Grammar.CompileTruncate(builder.Query)
 	for i, sql := range sqls {
 		defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
-		_, err := builder.UseWrite().DB().Exec(sql, bindings[i]...)
+		builder.UseWrite()
+		_, err := builder.executor().ExecContext(builder.ctx(), sql, bindings[i]...)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,309 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/utils"
+)
+
+// dslOperators the operators of the declarative JSON query which are not the comparison operators
+var dslOperators = []string{"in", "not in", "between", "not between", "null", "not null"}
+
+// Parse Parse the declarative JSON query and check it with the policy, the identifiers out of the policy are rejected.
+// The operators are checked against the grammar when the query is applied to a builder.
+//
+//	dsl, err := query.Parse([]byte(`{"table":"users", "wheres":[{"column":"vote", "op":">", "value":5}]}`), query.Policy{
+//		Tables:  []string{"users"},
+//		Columns: []string{"id", "name", "vote"},
+//	})
+//	qb, err := dsl.Apply(qb)
+func Parse(dsl []byte, policy Policy) (*DSL, error) {
+	parsed := &DSL{}
+	decoder := json.NewDecoder(bytes.NewReader(dsl))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("the query DSL is invalid. %s", err)
+	}
+
+	parsed.policy = policy
+	err = parsed.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
+// MustParse Parse the declarative JSON query and check it with the policy.
+func MustParse(dsl []byte, policy Policy) *DSL {
+	parsed, err := Parse(dsl, policy)
+	utils.PanicIF(err)
+	return parsed
+}
+
+// Validate Check the query with the policy, the values are normalized.
+func (dsl *DSL) Validate() error {
+	if !utils.StringHave(dsl.policy.Tables, dsl.Table) {
+		return fmt.Errorf("the table %q is not allowed", dsl.Table)
+	}
+
+	for _, column := range dsl.Select {
+		if err := dsl.validateColumn(column); err != nil {
+			return err
+		}
+	}
+
+	for _, column := range dsl.Groups {
+		if err := dsl.validateColumn(column); err != nil {
+			return err
+		}
+	}
+
+	for i, order := range dsl.Orders {
+		if err := dsl.validateColumn(order.Column); err != nil {
+			return err
+		}
+		direction := strings.ToLower(strings.TrimSpace(order.Direction))
+		if direction == "" {
+			direction = "asc"
+		}
+		if direction != "asc" && direction != "desc" {
+			return fmt.Errorf("the direction %q of the %q column should be asc or desc", order.Direction, order.Column)
+		}
+		dsl.Orders[i].Direction = direction
+	}
+
+	if err := dsl.validateWheres(dsl.Wheres); err != nil {
+		return err
+	}
+
+	if dsl.Page < 0 || dsl.PageSize < 0 || dsl.Limit < 0 || dsl.Offset < 0 {
+		return fmt.Errorf("the page, pagesize, limit and offset should not be negative")
+	}
+
+	max := dsl.policy.MaxPageSize
+	if max > 0 && (dsl.PageSize > max || dsl.Limit > max) {
+		return fmt.Errorf("the pagesize and limit should not be greater than %d", max)
+	}
+	return nil
+}
+
+// Apply Apply the query to the given builder, the table of the builder is replaced.
+func (dsl *DSL) Apply(qb Query) (Query, error) {
+	builder := qb.Builder()
+	operators := builder.Grammar.GetOperators()
+	for _, operator := range dsl.policy.Operators {
+		operator = strings.ToLower(strings.TrimSpace(operator))
+		if !utils.StringHave(operators, operator) && !utils.StringHave(dslOperators, operator) {
+			return nil, fmt.Errorf("the operator %q of the policy is not supported by the grammar", operator)
+		}
+	}
+
+	if err := dsl.checkOperators(builder, dsl.Wheres); err != nil {
+		return nil, err
+	}
+
+	qb.Table(dsl.Table)
+	columns := dsl.Select
+	if len(columns) == 0 {
+		columns = dsl.policy.Columns
+	}
+	if len(columns) > 0 {
+		qb.Select(utils.Flatten(columns)...)
+	}
+
+	dsl.applyWheres(qb, dsl.Wheres)
+
+	if len(dsl.Groups) > 0 {
+		qb.GroupBy(utils.Flatten(dsl.Groups)...)
+	}
+
+	for _, order := range dsl.Orders {
+		qb.OrderBy(order.Column, order.Direction)
+	}
+
+	pageSize := dsl.PageSize
+	if pageSize == 0 {
+		pageSize = dsl.policy.MaxPageSize
+	}
+
+	switch {
+	case dsl.Page > 0 && pageSize > 0:
+		qb.Offset((dsl.Page - 1) * pageSize).Limit(pageSize)
+	case dsl.Limit > 0 || dsl.Offset > 0:
+		limit := dsl.Limit
+		if limit == 0 {
+			limit = dsl.policy.MaxPageSize
+		}
+		if limit > 0 {
+			qb.Limit(limit)
+		}
+		if dsl.Offset > 0 {
+			qb.Offset(dsl.Offset)
+		}
+	case dsl.policy.MaxPageSize > 0:
+		qb.Limit(dsl.policy.MaxPageSize)
+	}
+
+	return qb, nil
+}
+
+// MustApply Apply the query to the given builder, the table of the builder is replaced.
+func (dsl *DSL) MustApply(qb Query) Query {
+	qb, err := dsl.Apply(qb)
+	utils.PanicIF(err)
+	return qb
+}
+
+// validateColumn Check the column with the policy
+func (dsl *DSL) validateColumn(column string) error {
+	if !utils.StringHave(dsl.policy.Columns, column) {
+		return fmt.Errorf("the column %q is not allowed", column)
+	}
+	return nil
+}
+
+// validateWheres Check the filters with the policy, the operators and the values are normalized.
+func (dsl *DSL) validateWheres(wheres []DSLWhere) error {
+	for i := range wheres {
+		where := &wheres[i]
+		where.Boolean = strings.ToLower(strings.TrimSpace(where.Boolean))
+		if where.Boolean == "" {
+			where.Boolean = "and"
+		}
+		if where.Boolean != "and" && where.Boolean != "or" {
+			return fmt.Errorf("the boolean %q of the filter should be and or or", where.Boolean)
+		}
+
+		// The nested group of the filters
+		if len(where.Wheres) > 0 {
+			if where.Column != "" || where.Op != "" || where.Value != nil {
+				return fmt.Errorf("the nested filters should not have the column, the operator and the value")
+			}
+			if err := dsl.validateWheres(where.Wheres); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := dsl.validateColumn(where.Column); err != nil {
+			return err
+		}
+
+		where.Op = strings.ToLower(strings.Join(strings.Fields(where.Op), " "))
+		if where.Op == "" {
+			where.Op = "="
+		}
+
+		if len(dsl.policy.Operators) > 0 && !utils.StringHave(dsl.policy.Operators, where.Op) {
+			return fmt.Errorf("the operator %q is not allowed", where.Op)
+		}
+
+		value, err := dslValue(where.Value)
+		if err != nil {
+			return fmt.Errorf("the value of the %q column is invalid. %s", where.Column, err)
+		}
+		where.Value = value
+
+		values, isList := value.([]interface{})
+		switch where.Op {
+		case "null", "not null":
+			if value != nil {
+				return fmt.Errorf("the %q operator of the %q column should not have a value", where.Op, where.Column)
+			}
+		case "in", "not in":
+			if !isList || len(values) == 0 {
+				return fmt.Errorf("the value of the %q operator of the %q column should be a non-empty array", where.Op, where.Column)
+			}
+		case "between", "not between":
+			if !isList || len(values) != 2 {
+				return fmt.Errorf("the value of the %q operator of the %q column should be an array of the min and max values", where.Op, where.Column)
+			}
+		default:
+			if isList || value == nil {
+				return fmt.Errorf("the value of the %q operator of the %q column should be a string, number or boolean", where.Op, where.Column)
+			}
+		}
+	}
+	return nil
+}
+
+// checkOperators Check the comparison operators of the filters against the grammar
+func (dsl *DSL) checkOperators(builder *Builder, wheres []DSLWhere) error {
+	for _, where := range wheres {
+		if len(where.Wheres) > 0 {
+			if err := dsl.checkOperators(builder, where.Wheres); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !utils.StringHave(dslOperators, where.Op) && builder.invalidOperator(where.Op) {
+			return fmt.Errorf("the operator %q is not supported by the grammar", where.Op)
+		}
+	}
+	return nil
+}
+
+// applyWheres Add the filters to the builder
+func (dsl *DSL) applyWheres(qb Query, wheres []DSLWhere) {
+	for _, where := range wheres {
+		where := where
+		if len(where.Wheres) > 0 {
+			nested := func(qb Query) { dsl.applyWheres(qb, where.Wheres) }
+			if where.Boolean == "or" {
+				qb.OrWhere(nested)
+			} else {
+				qb.Where(nested)
+			}
+			continue
+		}
+
+		builder := qb.Builder()
+		switch where.Op {
+		case "null", "not null":
+			builder.WhereNull(where.Column, where.Boolean, where.Op == "not null")
+		case "in", "not in":
+			builder.whereIn(where.Column, where.Value, where.Boolean, where.Op == "not in")
+		case "between", "not between":
+			builder.whereBetween(where.Column, where.Value, where.Boolean, where.Op == "not between")
+		default:
+			if where.Boolean == "or" {
+				builder.OrWhere(where.Column, where.Op, where.Value)
+			} else {
+				builder.Where(where.Column, where.Op, where.Value)
+			}
+		}
+	}
+}
+
+// dslValue Normalize the value of the filter, the numbers are converted to int64 or float64. the objects are not allowed.
+func dslValue(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case nil, string, bool, int64, float64:
+		return v, nil
+	case json.Number:
+		if number, err := v.Int64(); err == nil {
+			return number, nil
+		}
+		return v.Float64()
+	case []interface{}:
+		values := []interface{}{}
+		for _, item := range v {
+			item, err := dslValue(item)
+			if err != nil {
+				return nil, err
+			}
+			if _, isList := item.([]interface{}); isList || item == nil {
+				return nil, fmt.Errorf("the items of the array should be a string, number or boolean")
+			}
+			values = append(values, item)
+		}
+		return values, nil
+	}
+	return nil, fmt.Errorf("the %T value is not allowed", value)
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+)
+
+var testDSLPolicy = Policy{
+	Tables:      []string{"table_test_paginate"},
+	Columns:     []string{"id", "name", "vote", "status", "deleted_at"},
+	MaxPageSize: 50,
+}
+
+func TestDSLParse(t *testing.T) {
+	NewTableForPaginateTest()
+	dsl, err := Parse([]byte(`{
+		"table": "table_test_paginate",
+		"select": ["id", "name"],
+		"wheres": [
+			{"column": "vote", "op": ">", "value": 5},
+			{"wheres": [
+				{"column": "status", "op": "in", "value": ["DONE", "PENDING"]},
+				{"column": "deleted_at", "op": "not null", "boolean": "or"}
+			]},
+			{"column": "vote", "op": "not between", "value": [100, 200]}
+		],
+		"orders": [{"column": "vote", "direction": "DESC"}],
+		"page": 1,
+		"pagesize": 2
+	}`), testDSLPolicy)
+	assert.Nil(t, err)
+
+	qb := dsl.MustApply(getTestBuilder())
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "name" from "table_test_paginate" where "vote" > $1 and ("status" in ($2,$3) or "deleted_at" is not null) and "vote" not between $4 and $5 order by "vote" desc limit 2 offset 0`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `name` from `table_test_paginate` where `vote` > ? and (`status` in (?,?) or `deleted_at` is not null) and `vote` not between ? and ? order by `vote` desc limit 2 offset 0", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{int64(5), "DONE", "PENDING", int64(100), int64(200)}, qb.GetBindings())
+
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Ben", rows[0].Get("name"))
+}
+
+func TestDSLDefaults(t *testing.T) {
+	dsl := MustParse([]byte(`{"table": "table_test_paginate", "wheres": [{"column": "name", "value": "Ken"}, {"column": "deleted_at", "op": "null", "boolean": "or"}]}`), testDSLPolicy)
+	qb := dsl.MustApply(getTestBuilder())
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "name", "vote", "status", "deleted_at" from "table_test_paginate" where "name" = $1 or "deleted_at" is null limit 50`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `name`, `vote`, `status`, `deleted_at` from `table_test_paginate` where `name` = ? or `deleted_at` is null limit 50", sql, "the query sql not equal")
+	}
+}
+
+func TestDSLPolicy(t *testing.T) {
+	errors := map[string]string{
+		`{"table": "users"}`: `the table "users" is not allowed`,
+		`{"table": "table_test_paginate", "select": ["email"]}`:                                                     `the column "email" is not allowed`,
+		`{"table": "table_test_paginate", "select": ["id as x"]}`:                                                   `the column "id as x" is not allowed`,
+		`{"table": "table_test_paginate", "orders": [{"column": "id", "direction": "desc; drop table"}]}`:           `the direction "desc; drop table" of the "id" column should be asc or desc`,
+		`{"table": "table_test_paginate", "wheres": [{"column": "vote", "op": "in", "value": 1}]}`:                  `the value of the "in" operator of the "vote" column should be a non-empty array`,
+		`{"table": "table_test_paginate", "wheres": [{"column": "vote", "op": "between", "value": [1]}]}`:           `the value of the "between" operator of the "vote" column should be an array of the min and max values`,
+		`{"table": "table_test_paginate", "wheres": [{"column": "vote", "op": "=", "value": {"a": 1}}]}`:            `the value of the "vote" column is invalid. the map[string]interface {} value is not allowed`,
+		`{"table": "table_test_paginate", "wheres": [{"column": "vote", "op": "=", "value": 1, "boolean": "xor"}]}`: `the boolean "xor" of the filter should be and or or`,
+		`{"table": "table_test_paginate", "pagesize": 100}`:                                                         `the pagesize and limit should not be greater than 50`,
+	}
+
+	for dsl, message := range errors {
+		_, err := Parse([]byte(dsl), testDSLPolicy)
+		if assert.Error(t, err, dsl) {
+			assert.Equal(t, message, err.Error())
+		}
+	}
+
+	_, err := Parse([]byte(`{"table": "table_test_paginate", "unknown": 1}`), testDSLPolicy)
+	assert.Error(t, err)
+
+	policy := testDSLPolicy
+	policy.Operators = []string{"=", "in"}
+	_, err = Parse([]byte(`{"table": "table_test_paginate", "wheres": [{"column": "vote", "op": ">", "value": 1}]}`), policy)
+	assert.Equal(t, `the operator ">" is not allowed`, err.Error())
+}
+
+func TestDSLOperators(t *testing.T) {
+	dsl := MustParse([]byte(`{"table": "table_test_paginate", "wheres": [{"column": "vote", "op": "@@", "value": "1"}]}`), testDSLPolicy)
+	_, err := dsl.Apply(getTestBuilder())
+	if unit.DriverIs("postgres") {
+		assert.Nil(t, err)
+	} else {
+		assert.Equal(t, `the operator "@@" is not supported by the grammar`, err.Error())
+	}
+
+	policy := testDSLPolicy
+	policy.Operators = []string{"=", "is distinct from"}
+	dsl = MustParse([]byte(`{"table": "table_test_paginate"}`), policy)
+	_, err = dsl.Apply(getTestBuilder())
+	if unit.DriverIs("postgres") {
+		assert.Nil(t, err)
+	} else {
+		assert.Equal(t, `the operator "is distinct from" of the policy is not supported by the grammar`, err.Error())
+	}
+}
@@ -4,20 +4,20 @@ import "database/sql"
 
 // Exec Use the current connection to execute the sql, return the result
 func (builder *Builder) Exec(sql string, bindings ...interface{}) (sql.Result, error) {
-	stmt, err := builder.DB().Prepare(sql)
+	stmt, err := builder.executor().PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	return stmt.Exec(bindings...)
+	return stmt.ExecContext(builder.ctx(), bindings...)
 }
 
 // ExecWrite Use the write connection to execute the sql, return the result
 func (builder *Builder) ExecWrite(sql string, bindings ...interface{}) (sql.Result, error) {
-	stmt, err := builder.DB(true).Prepare(sql)
+	stmt, err := builder.executor(true).PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return nil, err
 	}
 	defer stmt.Close()
-	return stmt.Exec(bindings...)
+	return stmt.ExecContext(builder.ctx(), bindings...)
 }
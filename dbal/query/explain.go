@@ -0,0 +1,45 @@
+package query
+
+import (
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Explain Get the query plan of the query, the plan nodes are normalized across the databases.
+// The query is executed by PostgreSQL if the analyze option is given, the option is ignored by the others.
+// Explain()
+// Explain(dbal.Explain{Analyze: true})
+func (builder *Builder) Explain(option ...dbal.Explain) (*dbal.Plan, error) {
+	explain := dbal.Explain{}
+	if len(option) > 0 {
+		explain = option[0]
+	}
+
+	sql := builder.Grammar.CompileExplain(builder.ToSQL(), explain)
+	bindings := builder.GetBindings()
+	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
+
+	rows, err := builder.executor().QueryContext(builder.ctx(), sql, bindings...)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := builder.mapScan(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []map[string]interface{}{}
+	for _, row := range res {
+		values = append(values, row.ToMap())
+	}
+	return builder.Grammar.ProcessExplain(values)
+}
+
+// MustExplain Get the query plan of the query, the plan nodes are normalized across the databases.
+func (builder *Builder) MustExplain(option ...dbal.Explain) *dbal.Plan {
+	plan, err := builder.Explain(option...)
+	utils.PanicIF(err)
+	return plan
+}
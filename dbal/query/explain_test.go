@@ -0,0 +1,30 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestExplainFullScan(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	plan := qb.Table("table_test_scope").Where("name", "John").MustExplain()
+	assert.True(t, plan.HasFullScan())
+	if unit.DriverNot("mysql") {
+		assert.Equal(t, "table_test_scope", plan.FullScans()[0].Table)
+	}
+
+	plan = qb.Table("table_test_scope").Where("id", 1).MustExplain()
+	assert.False(t, plan.HasFullScan())
+
+	nodes := 0
+	plan.Walk(func(node *dbal.Plan) {
+		if node.Type == dbal.PlanIndexScan {
+			nodes++
+		}
+	})
+	assert.Equal(t, 1, nodes)
+}
@@ -31,13 +31,12 @@ func (builder *Builder) FromRaw(sql string, bindings ...interface{}) Query {
 
 // FromSub Makes "from" fetch from a subquery.
 func (builder *Builder) FromSub(qb interface{}, as string) Query {
-	sub, bindings, fromOffset := builder.createSub(qb)
-	segment := builder.parseSub(sub)
-	builder.Query.From = dbal.From{
-		Type:   "sub",
-		Alias:  as,
-		SQL:    fmt.Sprintf("(%s)", segment),
-		Offset: fromOffset,
+	sub, bindings, _ := builder.createSub(qb)
+	builder.Query.From = dbal.From{Type: "sub", Alias: as}
+	if query, ok := sub.(*dbal.Query); ok {
+		builder.Query.From.Name = query
+	} else {
+		builder.Query.From.SQL = fmt.Sprintf("(%s)", sub)
 	}
 	builder.Query.AddBinding("from", bindings)
 	return builder
@@ -74,6 +74,22 @@ func TestFromFromSub(t *testing.T) {
 	}
 }
 
+func TestFromFromSubBindings(t *testing.T) {
+	sqls := map[string]string{
+		"mysql":    "select * from (select * from `users` where `vote` > ? and `status` in (?,?)) as `u` where `u`.`score` > ?",
+		"postgres": `select * from (select * from "users" where "vote" > $1 and "status" in ($2,$3)) as "u" where "u"."score" > $4`,
+		"sqlite3":  "select * from (select * from `users` where `vote` > ? and `status` in (?,?)) as `u` where `u`.`score` > ?",
+	}
+	for driver, sql := range sqls {
+		qb := getGrammarTestBuilder(driver)
+		qb.FromSub(func(sub Query) {
+			sub.From("users").Where("vote", ">", 1).WhereIn("status", []string{"on", "off"})
+		}, "u").Where("u.score", ">", 2)
+		assert.Equal(t, sql, qb.ToSQL(), driver)
+		assert.Equal(t, []interface{}{1, "on", "off", 2}, qb.GetBindings(), driver)
+	}
+}
+
 // clean the test data
 func TestFromClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -0,0 +1,94 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// WhereFullText Add a "where full-text" clause to the query. the columns could be a string separated by commas or a []string.
+// WhereFullText("title,content", "database", dbal.FullText{Mode: "boolean"})
+func (builder *Builder) WhereFullText(columns interface{}, search string, options ...dbal.FullText) Query {
+	return builder.whereFullText(columns, search, "and", options...)
+}
+
+// OrWhereFullText Add an "or where full-text" clause to the query.
+func (builder *Builder) OrWhereFullText(columns interface{}, search string, options ...dbal.FullText) Query {
+	return builder.whereFullText(columns, search, "or", options...)
+}
+
+// SelectFullTextScore Add the relevance score of the full-text search to the query as a column.
+func (builder *Builder) SelectFullTextScore(columns interface{}, search string, alias string, options ...dbal.FullText) Query {
+	fulltext := builder.fullText(columns, search, options...)
+	fulltext.Alias = alias
+	builder.addSelect(fulltext)
+	builder.Query.AddBinding("select", search)
+	return builder
+}
+
+// OrderByFullTextScore Add a descending "order by" clause of the relevance score of the full-text search to the query.
+func (builder *Builder) OrderByFullTextScore(columns interface{}, search string, options ...dbal.FullText) Query {
+	order := dbal.Order{
+		Type:      "basic",
+		Column:    builder.fullText(columns, search, options...),
+		Direction: "desc",
+	}
+
+	if len(builder.Query.Unions) > 0 {
+		builder.Query.UnionOrders = append(builder.Query.UnionOrders, order)
+		builder.Query.AddBinding("unionOrder", search)
+		return builder
+	}
+
+	builder.Query.Orders = append(builder.Query.Orders, order)
+	builder.Query.AddBinding("order", search)
+	return builder
+}
+
+// whereFullText Add a "where full-text" clause with the given boolean to the query.
+func (builder *Builder) whereFullText(columns interface{}, search string, boolean string, options ...dbal.FullText) Query {
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:    "fulltext",
+		Column:  builder.fullText(columns, search, options...),
+		Value:   search,
+		Boolean: boolean,
+		Offset:  1,
+	})
+	builder.Query.AddBinding("where", search)
+	return builder
+}
+
+// fullText Make the full-text search of the given columns, the FTS5 table of SQLite will be prefixed.
+func (builder *Builder) fullText(columns interface{}, search string, options ...dbal.FullText) dbal.FullText {
+	fulltext := dbal.FullText{}
+	if len(options) > 0 {
+		fulltext = options[0]
+	}
+
+	fulltext.Columns = []string{}
+	switch values := columns.(type) {
+	case string:
+		for _, column := range strings.Split(values, ",") {
+			fulltext.Columns = append(fulltext.Columns, strings.TrimSpace(column))
+		}
+	case []string:
+		fulltext.Columns = append(fulltext.Columns, values...)
+	default:
+		panic(fmt.Errorf("the columns of the full-text search should be a string or []string"))
+	}
+
+	fulltext.Mode = strings.ToLower(fulltext.Mode)
+	if fulltext.Mode == "" {
+		fulltext.Mode = "natural"
+	}
+	if fulltext.Mode != "natural" && fulltext.Mode != "boolean" {
+		panic(fmt.Errorf(`the mode of the full-text search must be "natural" or "boolean"`))
+	}
+
+	if fulltext.Table != "" {
+		fulltext.Table = builder.Conn.Option.Prefix + fulltext.Table
+	}
+	fulltext.Search = search
+	return fulltext
+}
@@ -0,0 +1,131 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestFullTextWhereFullTextSQL(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_fulltext").
+		Select("id").
+		WhereFullText("title,content", "database").
+		OrWhereFullText([]string{"title"}, "+go -java", dbal.FullText{Mode: "boolean", Language: "simple", Table: "table_test_fulltext_fts"})
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id" from "table_test_fulltext" where (to_tsvector('english', "title") || to_tsvector('english', "content")) @@ websearch_to_tsquery('english', $1) or to_tsvector('simple', "title") @@ to_tsquery('simple', $2)`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select `id` from `table_test_fulltext` where `table_test_fulltext`.`table_test_fulltext` match '{title content} : (' || ? || ')' or `table_test_fulltext`.rowid in (select rowid from `table_test_fulltext_fts` where `table_test_fulltext_fts` match '{title} : (' || ? || ')')", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id` from `table_test_fulltext` where match (`title`, `content`) against (? in natural language mode) or match (`title`) against (? in boolean mode)", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{"database", "+go -java"}, qb.GetBindings())
+}
+
+func TestFullTextScoreSQL(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_fulltext").
+		Select("id").
+		SelectFullTextScore("title", "database", "score").
+		Where("id", ">", 0).
+		OrderByFullTextScore("title", "database")
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", ts_rank(to_tsvector('english', "title"), websearch_to_tsquery('english', $1)) as "score" from "table_test_fulltext" where "id" > $2 order by ts_rank(to_tsvector('english', "title"), websearch_to_tsquery('english', $3)) desc`, sql, "the query sql not equal")
+	} else if unit.DriverIs("sqlite3") {
+		score := "(select -bm25(`fts`.`table_test_fulltext`) from `table_test_fulltext` as `fts` where `fts`.`table_test_fulltext` match '{title} : (' || ? || ')' and `fts`.rowid = `table_test_fulltext`.rowid)"
+		assert.Equal(t, "select `id`, "+score+" as `score` from `table_test_fulltext` where `id` > ? order by "+score+" desc", sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, match (`title`) against (? in natural language mode) as `score` from `table_test_fulltext` where `id` > ? order by match (`title`) against (? in natural language mode) desc", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{"database", 0, "database"}, qb.GetBindings())
+}
+
+func TestFullTextWhereFullText(t *testing.T) {
+	if !NewTableForFullTextTest() {
+		return
+	}
+
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_fulltext").
+		Select("title").
+		WhereFullText("title,content", "database", fullTextOption()).
+		OrderBy("id").
+		MustGet()
+
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Database systems", rows[0].Get("title"))
+	assert.Equal(t, "Query builders", rows[1].Get("title"))
+}
+
+func TestFullTextOrderByScore(t *testing.T) {
+	if !NewTableForFullTextTest() {
+		return
+	}
+
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_fulltext").
+		Select("title").
+		SelectFullTextScore("title,content", "database", "score", fullTextOption()).
+		WhereFullText("title,content", "database", fullTextOption()).
+		OrderByFullTextScore("title,content", "database", fullTextOption()).
+		MustGet()
+
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Database systems", rows[0].Get("title"))
+	assert.True(t, rows[0].Get("score").(float64) > rows[1].Get("score").(float64))
+}
+
+// fullTextOption the full-text search option of the testing table, SQLite searches the FTS5 table.
+func fullTextOption() dbal.FullText {
+	if unit.DriverIs("sqlite3") {
+		return dbal.FullText{Table: "table_test_fulltext_fts"}
+	}
+	return dbal.FullText{}
+}
+
+// NewTableForFullTextTest create the full-text testing table, returns false if the full-text search is not supported.
+func NewTableForFullTextTest() bool {
+	defer unit.Catch()
+	qb := getTestBuilder()
+	if unit.DriverIs("sqlite3") {
+		var fts5 int
+		qb.DB().Get(&fts5, "select sqlite_compileoption_used('ENABLE_FTS5')")
+		if fts5 == 0 {
+			return false
+		}
+	}
+
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_fulltext")
+	builder.MustCreateTable("table_test_fulltext", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("title")
+		table.Text("content")
+	})
+
+	rows := []xun.R{
+		{"title": "Database systems", "content": "A database stores the data, the database engine runs the queries"},
+		{"title": "Query builders", "content": "The query builder makes the database queries"},
+		{"title": "Cooking", "content": "The recipes of the dinner"},
+		{"title": "Gardening", "content": "The flowers and the trees"},
+	}
+	qb.Table("table_test_fulltext").MustInsert(rows)
+
+	switch {
+	case unit.DriverIs("sqlite3"):
+		qb.DB().MustExec("drop table if exists `table_test_fulltext_fts`")
+		qb.DB().MustExec("create virtual table `table_test_fulltext_fts` using fts5(title, content)")
+		qb.DB().MustExec("insert into `table_test_fulltext_fts` (rowid, title, content) select id, title, content from `table_test_fulltext`")
+	case unit.DriverIs("mysql"):
+		qb.DB().MustExec("alter table `table_test_fulltext` add fulltext `table_test_fulltext_search` (`title`, `content`)")
+	}
+	return true
+}
@@ -127,6 +127,7 @@ func (builder *Builder) OrHavingBetween(column interface{}, values interface{},
 
 // HavingRaw Add a raw having clause to the query.
 func (builder *Builder) HavingRaw(sql string, bindings ...interface{}) Query {
+	sql, bindings = builder.prepareNamedBindings(sql, bindings)
 	builder.Query.Havings = append(builder.Query.Havings, dbal.Having{
 		Type:    "raw",
 		SQL:     sql,
@@ -141,6 +142,7 @@ func (builder *Builder) HavingRaw(sql string, bindings ...interface{}) Query {
 
 // OrHavingRaw Add a raw or having clause to the query.
 func (builder *Builder) OrHavingRaw(sql string, bindings ...interface{}) Query {
+	sql, bindings = builder.prepareNamedBindings(sql, bindings)
 	builder.Query.Havings = append(builder.Query.Havings, dbal.Having{
 		Type:    "raw",
 		SQL:     sql,
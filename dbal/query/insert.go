@@ -7,19 +7,37 @@ import (
 	"github.com/yaoapp/xun/utils"
 )
 
-// Insert Insert new records into the database.
+// Insert Insert new records into the database. The rows are inserted with one statement for each group of the same columns.
 func (builder *Builder) Insert(v interface{}, columns ...interface{}) error {
-	columns, values := builder.prepareInsertValues(v, columns...)
+	if err := builder.checkReturning("Insert"); err != nil {
+		return err
+	}
+
+	groups, err := builder.prepareInsertValues(v, columns...)
+	if err != nil {
+		return err
+	}
+
+	_, err = builder.insertGroups(groups, func(qb *Builder, group insertGroup) (int64, error) {
+		return 0, qb.insert(group.columns, group.values)
+	})
+	return err
+}
+
+// insert Execute the insert statement
+func (builder *Builder) insert(columns []interface{}, values [][]interface{}) error {
+	defer builder.flushCache()
 	sql, bindings := builder.Grammar.CompileInsert(builder.Query, columns, values)
 	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return err
 	}
 	defer stmt.Close()
 
-	_, err = stmt.Exec(bindings...)
+	_, err = stmt.ExecContext(builder.ctx(), bindings...)
 	return err
 }
 
@@ -31,17 +49,34 @@ func (builder *Builder) MustInsert(v interface{}, columns ...interface{}) {
 
 // InsertOrIgnore Insert new records into the database while ignoring errors.
 func (builder *Builder) InsertOrIgnore(v interface{}, columns ...interface{}) (int64, error) {
-	columns, values := builder.prepareInsertValues(v, columns...)
+	if err := builder.checkReturning("InsertOrIgnore"); err != nil {
+		return 0, err
+	}
+
+	groups, err := builder.prepareInsertValues(v, columns...)
+	if err != nil {
+		return 0, err
+	}
+
+	return builder.insertGroups(groups, func(qb *Builder, group insertGroup) (int64, error) {
+		return qb.insertOrIgnore(group.columns, group.values)
+	})
+}
+
+// insertOrIgnore Execute the insert statement which ignores the errors
+func (builder *Builder) insertOrIgnore(columns []interface{}, values [][]interface{}) (int64, error) {
+	defer builder.flushCache()
 	sql, bindings := builder.Grammar.CompileInsertOrIgnore(builder.Query, columns, values)
 	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.ctx(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -57,6 +92,10 @@ func (builder *Builder) MustInsertOrIgnore(v interface{}, columns ...interface{}
 
 // InsertGetID Insert a new record and get the value of the primary key.
 func (builder *Builder) InsertGetID(v interface{}, args ...interface{}) (int64, error) {
+	if err := builder.checkReturning("InsertGetID"); err != nil {
+		return 0, err
+	}
+	defer builder.flushCache()
 	seq := "id"
 	columns := []interface{}{}
 
@@ -66,8 +105,16 @@ func (builder *Builder) InsertGetID(v interface{}, args ...interface{}) (int64,
 		columns = args[1:]
 	}
 
-	columns, values := builder.prepareInsertValues(v, columns...)
-	sql, bindings := builder.Grammar.CompileInsertGetID(builder.Query, columns, values, seq)
+	groups, err := builder.prepareInsertValues(v, columns...)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(groups) > 1 {
+		return 0, fmt.Errorf("the rows of InsertGetID should have the same columns")
+	}
+
+	sql, bindings := builder.Grammar.CompileInsertGetID(builder.Query, groups[0].columns, groups[0].values, seq)
 	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
 	return builder.Grammar.ProcessInsertGetID(sql, bindings, seq)
 }
@@ -81,19 +128,24 @@ func (builder *Builder) MustInsertGetID(v interface{}, args ...interface{}) int6
 
 // InsertUsing Insert new records into the table using a subquery.
 func (builder *Builder) InsertUsing(qb interface{}, columns ...interface{}) (int64, error) {
+	if err := builder.checkReturning("InsertUsing"); err != nil {
+		return 0, err
+	}
+	defer builder.flushCache()
 
 	columns = builder.prepareColumns(columns...)
 	sub, bindings, _ := builder.createSub(qb)
 	sql := builder.parseSub(sub)
 	sql = builder.Grammar.CompileInsertUsing(builder.Query, columns, sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.ctx(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -313,10 +314,123 @@ func TestInsertMustInsertUsingWithArray(t *testing.T) {
 
 }
 
+type insertTagUser struct {
+	ID     int64                  `db:"id,auto"`
+	Email  string                 `db:"email"`
+	Name   string                 `db:"name,omitempty"`
+	Vote   int                    `db:",omitempty"`
+	Meta   map[string]interface{} `db:"meta,json"`
+	Secret string                 `db:"-"`
+}
+
+func TestInsertMustInsertStructTags(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_insert_tag").MustInsert([]insertTagUser{
+		{Email: "john@yao.run", Name: "John", Vote: 3, Meta: map[string]interface{}{"level": 1}, Secret: "secret"},
+		{Email: "ken@yao.run", Name: "Ken", Vote: 5},
+	})
+	qb.Table("table_test_insert_tag").MustInsert(insertTagUser{Email: "lee@yao.run"})
+
+	rows := qb.Table("table_test_insert_tag").OrderBy("id").MustGet()
+	assert.Equal(t, 3, len(rows), "The return rows should be 3")
+	if len(rows) == 3 {
+		assert.Equal(t, "John", rows[0].Get("name"), "The name of the first row should be John")
+		assert.Equal(t, int64(3), rows[0].Get("vote"), "The vote of the first row should be 3")
+		assert.Equal(t, `{"level":1}`, fmt.Sprintf("%s", rows[0].Get("meta")), "The meta of the first row should be the JSON text")
+		assert.Nil(t, rows[1].Get("meta"), "The nil meta of the second row should be null")
+		assert.Nil(t, rows[2].Get("name"), "The omitted name of the third row should be null")
+		assert.Equal(t, int64(0), rows[2].Get("vote"), "The omitted vote of the third row should be the default")
+	}
+}
+
+func TestInsertMixedColumns(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_insert_tag").MustInsert([]insertTagUser{
+		{Email: "john@yao.run", Name: "John", Vote: 3},
+		{Email: "lee@yao.run"},
+		{Email: "ken@yao.run", Name: "Ken", Vote: 5},
+	})
+
+	qb.Table("table_test_insert_tag").MustInsert([]xun.R{
+		{"email": "ben@yao.run", "vote": 7},
+		{"email": "ava@yao.run", "name": "Ava"},
+	})
+
+	rows := qb.Table("table_test_insert_tag").OrderBy("email").MustGet()
+	assert.Equal(t, 5, len(rows), "The return rows should be 5")
+	votes := map[interface{}]interface{}{}
+	names := map[interface{}]interface{}{}
+	for _, row := range rows {
+		votes[row.Get("email")] = row.Get("vote")
+		names[row.Get("email")] = row.Get("name")
+	}
+	assert.Equal(t, map[interface{}]interface{}{"john@yao.run": int64(3), "lee@yao.run": int64(0), "ken@yao.run": int64(5), "ben@yao.run": int64(7), "ava@yao.run": int64(0)}, votes, "The omitted votes should be the default")
+	assert.Nil(t, names["lee@yao.run"], "The omitted name should be null")
+	assert.Nil(t, names["ben@yao.run"], "The omitted name should be null")
+
+	// The rows are inserted within a transaction
+	err := qb.Table("table_test_insert_tag").Insert([]xun.R{
+		{"email": "max@yao.run", "vote": 1},
+		{"email": "john@yao.run", "name": "John"},
+	})
+	assert.Error(t, err, "the duplicate email should return an error")
+	assert.False(t, qb.Table("table_test_insert_tag").Where("email", "max@yao.run").MustExists(), "the inserted rows should be rolled back")
+
+	// The upserts and the returning inserts are grouped by the columns as well
+	qb.Table("table_test_insert_tag").MustUpsert([]xun.R{
+		{"email": "john@yao.run", "vote": 10},
+		{"email": "max@yao.run", "name": "Max"},
+	}, "email", []string{"vote", "name"})
+	assert.Equal(t, int64(10), qb.Table("table_test_insert_tag").Where("email", "john@yao.run").MustValue("vote"))
+	assert.Equal(t, int64(0), qb.Table("table_test_insert_tag").Where("email", "max@yao.run").MustValue("vote"))
+
+	returned := qb.Table("table_test_insert_tag").Returning("email", "vote").MustInsertReturning([]xun.R{
+		{"email": "kim@yao.run", "vote": 2},
+		{"email": "amy@yao.run", "name": "Amy"},
+	})
+	assert.Equal(t, 2, len(returned))
+	if len(returned) == 2 {
+		assert.Equal(t, "kim@yao.run", returned[0].Get("email"))
+		assert.Equal(t, int64(0), xun.MakeN(returned[1].Get("vote")).MustInt64())
+	}
+}
+
+func TestInsertStructTagsGetAs(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_insert_tag").MustInsert(insertTagUser{Email: "john@yao.run", Name: "John", Vote: 3, Meta: map[string]interface{}{"level": 1, "tags": []interface{}{"a"}}, Secret: "secret"})
+	qb.Table("table_test_insert_tag").MustInsert(insertTagUser{Email: "lee@yao.run", Name: "Lee"})
+
+	users, err := GetAs[insertTagUser](qb.Table("table_test_insert_tag").Select("id", "email", "name", "vote", "meta").OrderBy("id"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(users), "The return users should be 2")
+	if len(users) == 2 {
+		assert.Greater(t, users[0].ID, int64(0), "The id should be filled by the database")
+		assert.Equal(t, "John", users[0].Name)
+		assert.Equal(t, 3, users[0].Vote)
+		assert.Equal(t, map[string]interface{}{"level": float64(1), "tags": []interface{}{"a"}}, users[0].Meta, "The meta should be unmarshalled from the JSON text")
+		assert.Equal(t, "", users[0].Secret)
+		assert.Nil(t, users[1].Meta, "The null meta should be nil")
+	}
+}
+
+func TestInsertMustInsertGetIDStructTags(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	id := qb.Table("table_test_insert_tag").MustInsertGetID(insertTagUser{Email: "john@yao.run", Name: "John"})
+	assert.Greater(t, id, int64(0), "The zero id should be filled by the database")
+
+	id = qb.Table("table_test_insert_tag").MustInsertGetID(&insertTagUser{ID: 99, Email: "lee@yao.run"})
+	assert.Equal(t, int64(99), id, "The given id should be inserted")
+}
+
 // clean the test data
 func TestInsertClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_insert")
+	builder.DropTableIfExists("table_test_insert_tag")
 }
 
 func NewTableForInsertTest() {
@@ -330,6 +444,19 @@ func NewTableForInsertTest() {
 	})
 }
 
+func NewTableForInsertTagTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_insert_tag")
+	builder.MustCreateTable("table_test_insert_tag", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.String("name").Null()
+		table.Integer("vote").SetDefault(0)
+		table.Text("meta").Null()
+	})
+}
+
 func checkInsertWithColumns(t *testing.T, qb Query) {
 	users := qb.Select("email", "vote").OrderBy("vote").MustGet()
 	assert.Equal(t, 2, len(users), "The return users should be 2")
@@ -1,10 +1,13 @@
 package query
 
 import (
+	"context"
 	"database/sql"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
 )
 
 // Query The database Query interface
@@ -34,11 +37,54 @@ type Query interface {
 	ToSQL() string
 	GetBindings() []interface{}
 
+	// defined in the ast.go file
+	ToAST() ([]byte, error)
+	MustToAST() []byte
+
+	// defined in the cache.go file
+	Remember(ttl time.Duration) Query
+	RememberForever(key string) Query
+	UseCache(store dbal.Cache) Query
+	FlushCache(tables ...string)
+
+	// defined in the scope.go file
+	RegisterScope(table string, name string, apply func(qb Query)) Query
+	WithoutScope(names ...string) Query
+	WithoutScopes() Query
+
+	// defined in the timestamps.go file
+	UseTimestamps(option ...*Timestamps) Query
+
+	// defined in the softdelete.go file
+	RegisterSoftDeletes(table string, column ...string) Query
+	DetectSoftDeletes(tables ...string) error
+	MustDetectSoftDeletes(tables ...string) Query
+	WithTrashed() Query
+	OnlyTrashed() Query
+	Restore() (int64, error)
+	MustRestore() int64
+	ForceDelete() (int64, error)
+	MustForceDelete() int64
+
+	// defined in the cursor.go file
+	Cursor() (*Cursor, error)
+	MustCursor() *Cursor
+
 	// defined in the paginate.go file
 	Paginate(perpage int, page int, v ...interface{}) (xun.P, error)
 	MustPaginate(perpage int, page int, v ...interface{}) xun.P
+	SimplePaginate(perpage int, page int, v ...interface{}) (xun.SP, error)
+	MustSimplePaginate(perpage int, page int, v ...interface{}) xun.SP
+	SimplePaginateWithTotal(perpage int, page int, v ...interface{}) (xun.P, error)
+	MustSimplePaginateWithTotal(perpage int, page int, v ...interface{}) xun.P
+	CursorPaginate(perpage int, cursor string, v ...interface{}) (xun.CP, error)
+	MustCursorPaginate(perpage int, cursor string, v ...interface{}) xun.CP
 	Chunk(size int, callback func(items []interface{}, page int) error, v ...interface{}) error
 	MustChunk(size int, callback func(items []interface{}, page int) error, v ...interface{})
+	ChunkByID(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{}) error
+	MustChunkByID(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{})
+	ChunkByIDDesc(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{}) error
+	MustChunkByIDDesc(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{})
 
 	// defined in the connection.go file
 	DB(usewrite ...bool) *sqlx.DB
@@ -47,6 +93,20 @@ type Query interface {
 	UseWrite() Query
 	IsWrite() bool
 
+	// defined in the context.go file
+	WithContext(ctx context.Context) Query
+
+	// defined in the transaction.go file
+	Begin() (Query, error)
+	MustBegin() Query
+	Commit() error
+	MustCommit()
+	Rollback() error
+	MustRollback()
+	Transaction(callback func(qb Query) error) error
+	MustTransaction(callback func(qb Query) error)
+	InTransaction() bool
+
 	// defined in the aggregate.go file
 	Count(columns ...interface{}) (int64, error)
 	MustCount(columns ...interface{}) int64
@@ -67,6 +127,11 @@ type Query interface {
 	SelectSub(qb interface{}, alias string) Query
 	Distinct(args ...interface{}) Query
 
+	// defined in the with.go file
+	With(name string, qb interface{}, columns ...string) Query
+	WithRecursive(name string, qb interface{}, columns ...string) Query
+	WithMaterialized(name string, qb interface{}, columns ...string) Query
+
 	// defined in the from.go file
 	From(name string) Query
 	FromRaw(sql string, bindings ...interface{}) Query
@@ -128,6 +193,14 @@ type Query interface {
 	OrWhereJSONContains(column interface{}, value interface{}) Query
 	WhereJSONDoesntContain(column interface{}, value interface{}) Query
 	OrWhereJSONDoesntContain(column interface{}, value interface{}) Query
+	WhereJSONLength(column interface{}, args ...interface{}) Query
+	OrWhereJSONLength(column interface{}, args ...interface{}) Query
+
+	// defined in the fulltext.go file
+	WhereFullText(columns interface{}, search string, options ...dbal.FullText) Query
+	OrWhereFullText(columns interface{}, search string, options ...dbal.FullText) Query
+	SelectFullTextScore(columns interface{}, search string, alias string, options ...dbal.FullText) Query
+	OrderByFullTextScore(columns interface{}, search string, options ...dbal.FullText) Query
 
 	// defined in the group.go file
 	GroupBy(groups ...interface{}) Query
@@ -139,6 +212,9 @@ type Query interface {
 	HavingRaw(sql string, bindings ...interface{}) Query
 	OrHavingRaw(sql string, bindings ...interface{}) Query
 
+	// defined in the window.go file
+	Window(name string, window *dbal.Window) Query
+
 	// defined in the order.go file
 	OrderBy(column interface{}, args ...string) Query
 	OrderByDesc(column interface{}) Query
@@ -153,6 +229,11 @@ type Query interface {
 	// defined in the lock.go file
 	SharedLock() Query
 	LockForUpdate() Query
+	LockForNoKeyUpdate() Query
+	LockForKeyShare() Query
+	SkipLocked() Query
+	NoWait() Query
+	LockOf(tables ...string) Query
 
 	// defined in the insert.go file
 	Insert(v interface{}, columns ...interface{}) error
@@ -171,6 +252,8 @@ type Query interface {
 	MustUpdateOrInsert(attributes interface{}, values ...interface{}) bool
 	Update(v interface{}) (int64, error)
 	MustUpdate(v interface{}) int64
+	UpdateBatch(v interface{}, key string) (int64, error)
+	MustUpdateBatch(v interface{}, key string) int64
 	Increment(column interface{}, amount interface{}, extra ...interface{}) (int64, error)
 	MustIncrement(column interface{}, amount interface{}, extra ...interface{}) int64
 	Decrement(column interface{}, amount interface{}, extra ...interface{}) (int64, error)
@@ -182,13 +265,29 @@ type Query interface {
 	Truncate() error
 	MustTruncate()
 
+	// defined in the returning.go file
+	Returning(columns ...interface{}) Query
+	InsertReturning(v interface{}, columns ...interface{}) ([]xun.R, error)
+	MustInsertReturning(v interface{}, columns ...interface{}) []xun.R
+	UpdateReturning(v interface{}) ([]xun.R, error)
+	MustUpdateReturning(v interface{}) []xun.R
+	DeleteReturning() ([]xun.R, error)
+	MustDeleteReturning() []xun.R
+	UpsertReturning(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) ([]xun.R, error)
+	MustUpsertReturning(values interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) []xun.R
+
 	// defined in the exec.go file
 	Exec(sql string, bindings ...interface{}) (sql.Result, error)
 	ExecWrite(sql string, bindings ...interface{}) (sql.Result, error)
 
+	// defined in the explain.go file
+	Explain(option ...dbal.Explain) (*dbal.Plan, error)
+	MustExplain(option ...dbal.Explain) *dbal.Plan
+
 	// defined in the debug.go file
-	DD()
-	Dump()
+	DD(pretty ...bool)
+	Dump(pretty ...bool)
+	ToRawSQL() string
 }
 
 // @todo
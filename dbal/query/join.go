@@ -60,16 +60,14 @@ func (builder *Builder) CrossJoinSub(qb interface{}, alias string) Query {
 // On Add an "on" clause to the join.
 func (builder *Builder) On(first interface{}, args ...interface{}) Query {
 	operator, second := builder.joinPrepare(args...)
-	join := builder.joinOn(first, operator, second, "and", 0)
-	builder.Query.Joins = append(builder.Query.Joins, join)
+	builder.joinOn(first, operator, second, "and", 0)
 	return builder
 }
 
 // OrOn Add an "or on" clause to the join.
 func (builder *Builder) OrOn(first interface{}, args ...interface{}) Query {
 	operator, second := builder.joinPrepare(args...)
-	join := builder.joinOn(first, operator, second, "or", 0)
-	builder.Query.Joins = append(builder.Query.Joins, join)
+	builder.joinOn(first, operator, second, "or", 0)
 	return builder
 }
 
@@ -130,9 +128,11 @@ func (builder *Builder) join(table interface{}, alias string, first interface{},
 
 // joinRaw Add a sql join clause to the query.
 func (builder *Builder) joinRaw(sql string, bindings []interface{}) Query {
+	sql, bindings = builder.prepareNamedBindings(sql, bindings)
 	builder.Query.Joins = append(builder.Query.Joins, dbal.Join{
-		Type: "raw",
-		SQL:  sql,
+		Type:   "raw",
+		SQL:    sql,
+		Offset: len(bindings),
 	})
 	builder.Query.AddBinding("join", bindings)
 	return builder
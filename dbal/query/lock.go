@@ -1,5 +1,11 @@
 package query
 
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
 // SharedLock Share lock the selected rows in the table.
 func (builder *Builder) SharedLock() Query {
 	return builder.Lock("share")
@@ -10,6 +16,16 @@ func (builder *Builder) LockForUpdate() Query {
 	return builder.Lock("update")
 }
 
+// LockForNoKeyUpdate Lock the selected rows in the table for updating the columns except the keys. (PostgreSQL only)
+func (builder *Builder) LockForNoKeyUpdate() Query {
+	return builder.Lock(dbal.Lock{Mode: "no key update", Tables: []string{}})
+}
+
+// LockForKeyShare Share lock the keys of the selected rows in the table. (PostgreSQL only)
+func (builder *Builder) LockForKeyShare() Query {
+	return builder.Lock(dbal.Lock{Mode: "key share", Tables: []string{}})
+}
+
 // Lock Lock the selected rows in the table.
 func (builder *Builder) Lock(value interface{}) Query {
 	builder.Query.Lock = value
@@ -18,3 +34,36 @@ func (builder *Builder) Lock(value interface{}) Query {
 	}
 	return builder
 }
+
+// SkipLocked Skip the rows that are locked by the other transactions instead of waiting.
+// LockForUpdate().SkipLocked()
+func (builder *Builder) SkipLocked() Query {
+	lock := builder.lockValue("SkipLocked")
+	lock.Wait = "skip locked"
+	return builder.Lock(lock)
+}
+
+// NoWait Report an error if the selected rows are locked by the other transactions instead of waiting.
+// LockForUpdate().NoWait()
+func (builder *Builder) NoWait() Query {
+	lock := builder.lockValue("NoWait")
+	lock.Wait = "nowait"
+	return builder.Lock(lock)
+}
+
+// LockOf Lock the selected rows of the given tables only, the name or the alias of the tables in the query.
+// Join("orders as o", "o.user_id", "=", "users.id").LockForUpdate().LockOf("o")
+func (builder *Builder) LockOf(tables ...string) Query {
+	lock := builder.lockValue("LockOf")
+	lock.Tables = append(append([]string{}, lock.Tables...), tables...)
+	return builder.Lock(lock)
+}
+
+// lockValue Get the lock of the query, the lock should be set before setting the options.
+func (builder *Builder) lockValue(option string) dbal.Lock {
+	lock, ok := dbal.MakeLock(builder.Query.Lock)
+	if !ok {
+		panic(fmt.Errorf("%s should be called after SharedLock, LockForUpdate, LockForNoKeyUpdate or LockForKeyShare", option))
+	}
+	return lock
+}
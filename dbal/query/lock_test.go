@@ -68,6 +68,76 @@ func TestLockLockForUpdate(t *testing.T) {
 	assert.Equal(t, 4, len(rows), "the return value should be have 4 items")
 }
 
+func TestLockSkipLocked(t *testing.T) {
+	NewTableForLockTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_lock").
+		Select("id", "vote").
+		Where("status", "WAITING").
+		LockForUpdate().
+		SkipLocked()
+
+	if unit.DriverIs("sqlite3") {
+		assert.Panics(t, func() { qb.ToSQL() })
+		return
+	}
+
+	// checking sql
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "id", "vote" from "table_test_lock" where "status" = $1 for update skip locked`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `id`, `vote` from `table_test_lock` where `status` = ? for update skip locked", sql, "the query sql not equal")
+	}
+
+	// checking result
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows), "the return value should be have 1 items")
+}
+
+func TestLockNoWaitOf(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_lock as t").
+		Select("t.id").
+		Join("table_test_lock as u", "u.id", "=", "t.id").
+		LockForUpdate().
+		LockOf("t").
+		NoWait()
+
+	if unit.DriverIs("sqlite3") {
+		assert.Panics(t, func() { qb.ToSQL() })
+		return
+	}
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select "t"."id" from "table_test_lock" as "t" inner join "table_test_lock" as "u" on "u"."id" = "t"."id" for update of "t" nowait`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select `t`.`id` from `table_test_lock` as `t` inner join `table_test_lock` as `u` on `u`.`id` = `t`.`id` for update of `t` nowait", sql, "the query sql not equal")
+	}
+}
+
+func TestLockKeyShare(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_lock").Select("id").LockForKeyShare()
+	if !unit.DriverIs("postgres") {
+		assert.Panics(t, func() { qb.ToSQL() })
+		return
+	}
+	assert.Equal(t, `select "id" from "table_test_lock" for key share`, qb.ToSQL(), "the query sql not equal")
+
+	qb = getTestBuilder()
+	qb.Table("table_test_lock").Select("id").LockForNoKeyUpdate()
+	assert.Equal(t, `select "id" from "table_test_lock" for no key update`, qb.ToSQL(), "the query sql not equal")
+}
+
+func TestLockOptionsWithoutLock(t *testing.T) {
+	qb := getTestBuilder()
+	assert.Panics(t, func() { qb.Table("table_test_lock").SkipLocked() })
+	assert.Panics(t, func() { qb.Table("table_test_lock").NoWait() })
+	assert.Panics(t, func() { qb.Table("table_test_lock").LockOf("table_test_lock") })
+}
+
 // clean the test data
 func TestLockClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -0,0 +1,123 @@
+package query
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+)
+
+// prepareNamedBindings Rewrite the ":name" placeholders of the raw SQL into the positional placeholders if the bindings are
+// given as a map or a struct, the bindings are returned in the order of the placeholders. The name could be used more than once.
+// The expressions are inlined, the placeholders in the quoted strings and the casts (::) are kept.
+// WhereRaw("vote > :min and vote < :max", map[string]interface{}{"min": 1, "max": 10})
+func (builder *Builder) prepareNamedBindings(sql string, bindings []interface{}) (string, []interface{}) {
+	if len(bindings) != 1 {
+		return sql, bindings
+	}
+
+	values, ok := namedValues(bindings[0])
+	if !ok {
+		return sql, bindings
+	}
+
+	named := []interface{}{}
+	var raw strings.Builder
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := strings.IndexByte(sql[i+1:], c)
+			if end < 0 {
+				end = len(sql) - i - 2
+			}
+			raw.WriteString(sql[i : i+end+2])
+			i = i + end + 1
+
+		case c == ':' && i+1 < len(sql) && sql[i+1] == ':':
+			raw.WriteString("::")
+			i++
+
+		case c == ':' && i+1 < len(sql) && isNameStart(sql[i+1]):
+			end := i + 1
+			for end < len(sql) && (isNameStart(sql[end]) || (sql[end] >= '0' && sql[end] <= '9')) {
+				end++
+			}
+
+			name := sql[i+1 : end]
+			value, has := values[name]
+			if !has {
+				panic(fmt.Errorf("the named binding %s is not given", name))
+			}
+
+			if expression, ok := value.(dbal.Expression); ok {
+				raw.WriteString(expression.GetValue())
+			} else {
+				raw.WriteString(dbal.NamedParameter)
+				named = append(named, value)
+			}
+			i = end - 1
+
+		default:
+			raw.WriteByte(c)
+		}
+	}
+	return raw.String(), named
+}
+
+// namedValues Get the values of the named bindings, the keys of the map or the fields of the struct are the names.
+// The name of the field is the db tag, the json tag, or the field name in snake case. returns false if the value is not named bindings.
+func namedValues(value interface{}) (map[string]interface{}, bool) {
+	switch value.(type) {
+	case time.Time, driver.Valuer, dbal.Expression:
+		return nil, false
+	}
+
+	reflectValue := reflect.Indirect(reflect.ValueOf(value))
+	switch reflectValue.Kind() {
+	case reflect.Map:
+		if reflectValue.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		values := map[string]interface{}{}
+		for _, key := range reflectValue.MapKeys() {
+			values[key.String()] = reflectValue.MapIndex(key).Interface()
+		}
+		return values, true
+
+	case reflect.Struct:
+		values := map[string]interface{}{}
+		reflectType := reflectValue.Type()
+		for i := 0; i < reflectValue.NumField(); i++ {
+			field := reflectType.Field(i)
+			if !reflectValue.Field(i).CanInterface() {
+				continue
+			}
+			if tag, has := xun.GetDBTag(field); has {
+				if !tag.Skip {
+					values[tag.Name] = tag.Value(reflectValue.Field(i).Interface())
+				}
+				continue
+			}
+			name := strings.Split(xun.GetTagName(field, "json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = xun.ToSnakeCase(field.Name)
+			}
+			values[name] = reflectValue.Field(i).Interface()
+		}
+		return values, true
+	}
+	return nil, false
+}
+
+// isNameStart Determine if the character could start the name of the named binding
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
@@ -0,0 +1,104 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestNamedBindingsSQL(t *testing.T) {
+	qb := getTestBuilder()
+	qb.Table("table_test_named as n").
+		SelectRaw("vote * :rate as weighted", map[string]interface{}{"rate": 2}).
+		WhereIn("id", func(sub Query) {
+			sub.Select("id").From("table_test_named").WhereRaw("vote > :min", map[string]interface{}{"min": 1})
+		}).
+		WhereRaw("(name = :name or status = :name) and note = ':name'", map[string]interface{}{"name": "John"}).
+		OrderByRaw("vote = :vote desc", map[string]interface{}{"vote": 3})
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select vote * $1 as weighted from "table_test_named" as "n" where "id" in (select "id" from "table_test_named" where vote > $2) and (name = $3 or status = $4) and note = ':name' order by vote = $5 desc`, sql)
+	} else {
+		assert.Equal(t, "select vote * ? as weighted from `table_test_named` as `n` where `id` in (select `id` from `table_test_named` where vote > ?) and (name = ? or status = ?) and note = ':name' order by vote = ? desc", sql)
+	}
+	assert.Equal(t, []interface{}{2, 1, "John", "John", 3}, qb.GetBindings())
+}
+
+func TestNamedBindingsSubqueryPostgres(t *testing.T) {
+	named := map[string]interface{}{"x": 1}
+	qb := getPostgresTestBuilder()
+	qb.Table("t").
+		Where("z", 9).
+		WhereIn("id", func(sub Query) { sub.Select("uid").From("p").WhereRaw("k = :x", named) }).
+		Where("w", 3)
+	assert.Equal(t, `select * from "t" where "z" = $1 and "id" in (select "uid" from "p" where k = $2) and "w" = $3`, qb.ToSQL())
+	assert.Equal(t, []interface{}{9, 1, 3}, qb.GetBindings())
+
+	qb = getPostgresTestBuilder()
+	qb.Table("t").
+		Where("z", 9).
+		WhereExists(func(sub Query) { sub.From("p").WhereRaw("k = :x and j = :x", named) }).
+		Where("w", 3)
+	assert.Equal(t, `select * from "t" where "z" = $1 and exists (select * from "p" where k = $2 and j = $3) and "w" = $4`, qb.ToSQL())
+	assert.Equal(t, []interface{}{9, 1, 1, 3}, qb.GetBindings())
+}
+
+func TestNamedBindingsStruct(t *testing.T) {
+	qb := getTestBuilder()
+	filter := struct {
+		MinVote int    `json:"min_vote"`
+		Status  string `json:"status,omitempty"`
+		Secret  string `json:"-"`
+	}{MinVote: 3, Status: "active"}
+
+	qb.Table("table_test_named").
+		JoinRaw("inner join table_test_named as p on p.id = table_test_named.id and p.vote >= :min_vote", filter).
+		WhereRaw("table_test_named.status = :status and table_test_named.created_at > :now::date", map[string]interface{}{"status": "active", "now": dbal.Raw("CURRENT_DATE")}).
+		HavingRaw("count(*) > :min_vote", filter)
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_named" inner join table_test_named as p on p.id = table_test_named.id and p.vote >= $1 where table_test_named.status = $2 and table_test_named.created_at > CURRENT_DATE::date having count(*) > $3`, sql)
+	} else {
+		assert.Equal(t, "select * from `table_test_named` inner join table_test_named as p on p.id = table_test_named.id and p.vote >= ? where table_test_named.status = ? and table_test_named.created_at > CURRENT_DATE::date having count(*) > ?", sql)
+	}
+	assert.Equal(t, []interface{}{3, "active", 3}, qb.GetBindings())
+
+	assert.PanicsWithError(t, "the named binding secret is not given", func() {
+		qb.Table("table_test_named").WhereRaw("secret = :secret", filter)
+	})
+
+	// The db tag is used before the json tag
+	tagged := struct {
+		Vote   int      `db:"vote" json:"min_vote"`
+		Tags   []string `db:"tags,json"`
+		Secret string   `db:"-" json:"secret"`
+	}{Vote: 5, Tags: []string{"vip"}, Secret: "s"}
+	qb.Table("table_test_named").WhereRaw("vote > :vote and tags = :tags", tagged)
+	assert.Equal(t, []interface{}{5, `["vip"]`}, qb.GetBindings())
+
+	assert.PanicsWithError(t, "the named binding min_vote is not given", func() {
+		qb.Table("table_test_named").WhereRaw("vote > :min_vote", tagged)
+	})
+	assert.PanicsWithError(t, "the named binding secret is not given", func() {
+		qb.Table("table_test_named").WhereRaw("secret = :secret", tagged)
+	})
+}
+
+func TestNamedBindingsGet(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_scope").
+		WhereRaw("vote >= :vote and status = :status", map[string]interface{}{"vote": 3, "status": "active"}).
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "John", rows[0].Get("name"))
+
+	sql := "select name from table_test_scope where name = :name or (vote > :vote and status = :status)"
+	rows = qb.New().SQL(sql, map[string]interface{}{"name": "Ben", "vote": 6, "status": "active"}).MustGet()
+	assert.Equal(t, 2, len(rows))
+}
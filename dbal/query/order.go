@@ -10,7 +10,6 @@ import (
 
 // OrderBy Add an "order by" clause to the query.
 func (builder *Builder) OrderBy(column interface{}, args ...string) Query {
-	offset := 0
 	direction := "asc"
 	orderName := "order"
 
@@ -26,18 +25,15 @@ func (builder *Builder) OrderBy(column interface{}, args ...string) Query {
 	}
 
 	if builder.isQueryable(column) {
-		sub, bindings, subQueryOffset := builder.createSub(column)
-		sql := builder.parseSub(sub)
-		offset = subQueryOffset
-		column = dbal.Raw(fmt.Sprintf("(%s)", sql))
+		sub, bindings, _ := builder.createSub(column)
+		column = sub
 		builder.Query.AddBinding(orderName, bindings)
 	}
 
 	order := dbal.Order{
 		Type:      "basic",
-		Column:    column,
+		Column:    builder.windowValue(column),
 		Direction: direction,
-		Offset:    offset,
 	}
 
 	if orderName == "unionOrder" {
@@ -55,6 +51,7 @@ func (builder *Builder) OrderByDesc(column interface{}) Query {
 
 // OrderByRaw Add a raw "order by" clause to the query.
 func (builder *Builder) OrderByRaw(sql string, bindings ...interface{}) Query {
+	sql, bindings = builder.prepareNamedBindings(sql, bindings)
 	order := dbal.Order{
 		Type: "raw",
 		SQL:  sql,
@@ -110,6 +110,22 @@ func TestOrderOrderByRawUnion(t *testing.T) {
 	checkOrderOrderByUnion(t, qb)
 }
 
+func TestOrderOrderBySubBindings(t *testing.T) {
+	sqls := map[string]string{
+		"mysql":    "select * from `users` where `vote` > ? order by (select `score` from `ranks` where `ranks`.`uid` = `users`.`id` and `season` = ? limit 1) desc limit 2",
+		"postgres": `select * from "users" where "vote" > $1 order by (select "score" from "ranks" where "ranks"."uid" = "users"."id" and "season" = $2 limit 1) desc limit 2`,
+		"sqlite3":  "select * from `users` where `vote` > ? order by (select `score` from `ranks` where `ranks`.`uid` = `users`.`id` and `season` = ? limit 1) desc limit 2",
+	}
+	for driver, sql := range sqls {
+		qb := getGrammarTestBuilder(driver)
+		qb.Table("users").Where("vote", ">", 1).OrderBy(func(sub Query) {
+			sub.From("ranks").Select("score").WhereColumn("ranks.uid", "users.id").Where("season", 7).Limit(1)
+		}, "desc").Limit(2)
+		assert.Equal(t, sql, qb.ToSQL(), driver)
+		assert.Equal(t, []interface{}{1, 7}, qb.GetBindings(), driver)
+	}
+}
+
 // clean the test data
 func TestOrderClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -1,9 +1,13 @@
 package query
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 
+	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
@@ -20,43 +24,18 @@ func (builder *Builder) Chunk(size int, callback func(items []interface{}, page
 
 	for {
 
-		var results []interface{} = nil
-		var countResults int
+		if err := builder.ctx().Err(); err != nil {
+			return err
+		}
 
 		// We'll execute the query for the given page and get the results. If there are
 		// no results we can just break and return from here. When there are results
 		// we will call the callback with the current chunk of these results here.
-		if len(v) > 0 {
-
-			reflectValuesPtr := reflect.ValueOf(v[0])
-			reflectValues := reflect.Indirect(reflectValuesPtr)
-			if reflectValues.Kind() != reflect.Slice {
-				return fmt.Errorf("The given binding var shoule be a slice pointer")
-			}
-
-			reflectValuesType := reflectValues.Type()
-			reflectValuesPtr.Elem().Set(reflect.New(reflectValuesType).Elem())
-
-			_, err := builder.forPage(page, size).Get(v...)
-			if err != nil {
-				return err
-			}
-
-			countResults = reflectValues.Len()
-			for i := 0; i < countResults; i++ {
-				results = append(results, reflectValues.Index(i).Interface())
-			}
-		} else {
-			rows, err := builder.forPage(page, size).Get()
-			if err != nil {
-				return err
-			}
-
-			countResults = len(rows)
-			for _, row := range rows {
-				results = append(results, row)
-			}
+		results, err := builder.forPage(page, size).Builder().getChunkResults(v...)
+		if err != nil {
+			return err
 		}
+		countResults := len(results)
 
 		// log.Trace("Chunk: countResults: %d size: %d page: %d", countResults, size, page)
 		if err := callback(results, page); err != nil {
@@ -80,11 +59,210 @@ func (builder *Builder) MustChunk(size int, callback func(items []interface{}, p
 	utils.PanicIF(err)
 }
 
-// ChunkByID chunk the results of a query by comparing IDs.
-func (builder *Builder) ChunkByID() {}
+// ChunkByID Chunk the results of a query by comparing IDs in ascending order. The alias is the column name in the results, the column name will be used if it is empty.
+func (builder *Builder) ChunkByID(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{}) error {
+	return builder.chunkByID(size, column, alias, false, callback, v...)
+}
+
+// MustChunkByID Chunk the results of a query by comparing IDs in ascending order.
+func (builder *Builder) MustChunkByID(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{}) {
+	err := builder.ChunkByID(size, column, alias, callback, v...)
+	utils.PanicIF(err)
+}
+
+// ChunkByIDDesc Chunk the results of a query by comparing IDs in descending order. The alias is the column name in the results, the column name will be used if it is empty.
+func (builder *Builder) ChunkByIDDesc(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{}) error {
+	return builder.chunkByID(size, column, alias, true, callback, v...)
+}
+
+// MustChunkByIDDesc Chunk the results of a query by comparing IDs in descending order.
+func (builder *Builder) MustChunkByIDDesc(size int, column string, alias string, callback func(items []interface{}, page int) error, v ...interface{}) {
+	err := builder.ChunkByIDDesc(size, column, alias, callback, v...)
+	utils.PanicIF(err)
+}
+
+// chunkByID Chunk the results of a query by comparing IDs.
+func (builder *Builder) chunkByID(size int, column string, alias string, desc bool, callback func(items []interface{}, page int) error, v ...interface{}) error {
+
+	if size < 1 {
+		size = 50
+	}
+
+	if alias == "" {
+		alias = column
+		if pos := strings.LastIndex(column, "."); pos >= 0 {
+			alias = column[pos+1:]
+		}
+	}
+
+	page := 1
+	var lastID interface{} = nil
+	for {
+
+		if err := builder.ctx().Err(); err != nil {
+			return err
+		}
+
+		// We'll execute the query for the given page and get the results. The last ID of
+		// the chunk will be used to constrain the next chunk, so the rows updated by the
+		// callback will never be skipped or repeated.
+		clone := builder.clone()
+		if desc {
+			clone.forPageBeforeID(size, lastID, column)
+		} else {
+			clone.forPageAfterID(size, lastID, column)
+		}
+
+		results, err := clone.getChunkResults(v...)
+		if err != nil {
+			return err
+		}
+
+		countResults := len(results)
+		if countResults == 0 {
+			break
+		}
+
+		if err := callback(results, page); err != nil {
+			return err
+		}
+
+		lastID = builder.getItemValue(results[countResults-1], alias)
+		if lastID == nil {
+			return fmt.Errorf("The chunkByID operation was aborted because the [%s] column is not present in the query result", alias)
+		}
+
+		if countResults != size {
+			break
+		}
+
+		page++
+	}
+
+	return nil
+}
+
+// getChunkResults Execute the query and return the results as a slice of items (xun.R or the given binding type)
+func (builder *Builder) getChunkResults(v ...interface{}) ([]interface{}, error) {
+	var results []interface{} = nil
+	if len(v) > 0 {
+
+		reflectValuesPtr := reflect.ValueOf(v[0])
+		reflectValues := reflect.Indirect(reflectValuesPtr)
+		if reflectValues.Kind() != reflect.Slice {
+			return nil, fmt.Errorf("The given binding var shoule be a slice pointer")
+		}
+
+		reflectValuesType := reflectValues.Type()
+		reflectValuesPtr.Elem().Set(reflect.New(reflectValuesType).Elem())
+
+		_, err := builder.Get(v...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < reflectValues.Len(); i++ {
+			results = append(results, reflectValues.Index(i).Interface())
+		}
+		return results, nil
+	}
+
+	rows, err := builder.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		results = append(results, row)
+	}
+	return results, nil
+}
+
+// resizeChunkResults Trim the given binding slice to the size, and reverse it if required, so it keeps the same rows as the items.
+func (builder *Builder) resizeChunkResults(size int, reverse bool, v ...interface{}) {
+	if len(v) == 0 {
+		return
+	}
+
+	reflectValues := reflect.Indirect(reflect.ValueOf(v[0]))
+	if reflectValues.Kind() != reflect.Slice {
+		return
+	}
+
+	if reflectValues.Len() > size {
+		reflectValues.Set(reflectValues.Slice(0, size))
+	}
+
+	if reverse {
+		swap := reflect.Swapper(reflectValues.Interface())
+		for i, j := 0, reflectValues.Len()-1; i < j; i, j = i+1, j-1 {
+			swap(i, j)
+		}
+	}
+}
+
+// getWindowResults Execute the query and bind the rows to the given struct slice, the COUNT(*) OVER() column is scanned into the total.
+func (builder *Builder) getWindowResults(total *int64, v interface{}) ([]interface{}, error) {
+	db := builder.executor()
+	sql := builder.ToSQL()
+	stmt, err := db.PrepareContext(builder.ctx(), sql)
+	if err != nil {
+		defer log.With(log.F{"bindings": builder.GetBindings()}).Error("%s", sql)
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(builder.ctx(), builder.GetBindings()...)
+	if err != nil {
+		return nil, err
+	}
+
+	reflectValues := reflect.Indirect(reflect.ValueOf(v))
+	reflectValues.Set(reflect.New(reflectValues.Type()).Elem())
+	err = builder.structScan(rows, v, map[string]interface{}{windowTotalColumn: total})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []interface{}{}
+	for i := 0; i < reflectValues.Len(); i++ {
+		results = append(results, reflectValues.Index(i).Interface())
+	}
+	return results, nil
+}
+
+// isStructSlicePtr Determine if the given binding var is a pointer of a struct slice
+func isStructSlicePtr(v interface{}) bool {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.Elem().Kind() != reflect.Slice {
+		return false
+	}
+	return value.Elem().Type().Elem().Kind() == reflect.Struct
+}
+
+// getItemValue Get the value of the given column from the item (xun.R or struct)
+func (builder *Builder) getItemValue(item interface{}, column string) interface{} {
+	if row, ok := item.(xun.R); ok {
+		return row.Get(column)
+	}
 
-// MustChunkByID chunk the results of a query by comparing IDs.
-func (builder *Builder) MustChunkByID() {}
+	value := reflect.Indirect(reflect.ValueOf(item))
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fieldMap, err := builder.getFieldMap(value.Type())
+	if err != nil {
+		return nil
+	}
+
+	field, has := fieldMap[column]
+	if !has {
+		return nil
+	}
+	return value.FieldByName(field.Name).Interface()
+}
 
 // Paginate paginate the given query into a simple paginator.
 func (builder *Builder) Paginate(pageSize int, page int, v ...interface{}) (xun.P, error) {
@@ -132,28 +310,317 @@ func (builder *Builder) MustPaginate(pageSize int, page int, v ...interface{}) x
 	return res
 }
 
+// SimplePaginate Paginate the given query into a simple paginator without counting the total records. It fetches one more row to determine if there is a next page.
+func (builder *Builder) SimplePaginate(pageSize int, page int, v ...interface{}) (xun.SP, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	items, err := builder.clone().Offset((page - 1) * pageSize).Limit(pageSize + 1).Builder().getChunkResults(v...)
+	if err != nil {
+		return xun.MakeSP(pageSize, page, false), err
+	}
+
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+	builder.resizeChunkResults(pageSize, false, v...)
+
+	return xun.MakeSP(pageSize, page, hasMore, items...), nil
+}
+
+// MustSimplePaginate Paginate the given query into a simple paginator without counting the total records.
+func (builder *Builder) MustSimplePaginate(pageSize int, page int, v ...interface{}) xun.SP {
+	res, err := builder.SimplePaginate(pageSize, page, v...)
+	utils.PanicIF(err)
+	return res
+}
+
+// SimplePaginateWithTotal Paginate the given query into a paginator, the total records is computed in the same query using COUNT(*) OVER(). The results could bind to a struct slice. It falls back to Paginate when the grammar does not support window functions, the query has unions or distinct, or the results bind to a slice of other types.
+func (builder *Builder) SimplePaginateWithTotal(pageSize int, page int, v ...interface{}) (xun.P, error) {
+	if page < 1 {
+		page = 1
+	}
+
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	bind := len(v) > 0 && v[0] != nil
+	if (bind && !isStructSlicePtr(v[0])) || len(builder.Query.Unions) > 0 || builder.Query.Distinct || !builder.Grammar.SupportsWindowFunctions() {
+		return builder.Paginate(pageSize, page, v...)
+	}
+
+	clone := builder.clone()
+	if len(clone.Query.Columns) == 0 {
+		clone.Select("*")
+	}
+
+	clone.SelectAppend(dbal.Raw(fmt.Sprintf("COUNT(*) OVER() AS %s", builder.Grammar.Wrap(windowTotalColumn))))
+	clone.forPage(page, pageSize)
+
+	items := []interface{}{}
+	total := 0
+	if bind {
+		var count int64
+		results, err := clone.getWindowResults(&count, v[0])
+		if err != nil {
+			return xun.MakePaginator(0, pageSize, page), err
+		}
+		items = append(items, results...)
+		total = int(count)
+
+	} else {
+		rows, err := clone.Get()
+		if err != nil {
+			return xun.MakePaginator(0, pageSize, page), err
+		}
+
+		if len(rows) > 0 {
+			total, err = xun.MakeN(rows[0][windowTotalColumn]).Int()
+			if err != nil {
+				return xun.MakePaginator(0, pageSize, page), err
+			}
+		}
+
+		for _, row := range rows {
+			delete(row, windowTotalColumn)
+			items = append(items, row)
+		}
+	}
+
+	// The page is out of range, count the total records with the count query.
+	if len(items) == 0 {
+		total, err := builder.getCountForPagination([]interface{}{"*"})
+		if err != nil {
+			return xun.MakePaginator(0, pageSize, page), err
+		}
+		return xun.MakePaginator(total, pageSize, page), nil
+	}
+
+	return xun.MakePaginator(total, pageSize, page, items...), nil
+}
+
+// MustSimplePaginateWithTotal Paginate the given query into a paginator, the total records is computed in the same query using COUNT(*) OVER().
+func (builder *Builder) MustSimplePaginateWithTotal(pageSize int, page int, v ...interface{}) xun.P {
+	res, err := builder.SimplePaginateWithTotal(pageSize, page, v...)
+	utils.PanicIF(err)
+	return res
+}
+
+// CursorPaginate Paginate the given query into a cursor paginator. The keyset predicates are derived from the "order by" clauses of the query, the cursor is the next or previous cursor returned by the last call, an empty cursor means the first page.
+func (builder *Builder) CursorPaginate(pageSize int, cursor string, v ...interface{}) (xun.CP, error) {
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	orders, err := builder.getCursorOrders()
+	if err != nil {
+		return xun.MakeCP(pageSize, "", ""), err
+	}
+
+	token, err := decodeCursor(cursor)
+	if err != nil {
+		return xun.MakeCP(pageSize, "", ""), err
+	}
+
+	clone := builder.clone()
+	if token != nil {
+		clone.whereCursor(orders, token)
+
+		// Reverse the orders to fetch the previous page, the items will be reversed back later.
+		if !token.Next {
+			clone.Query.Orders = []dbal.Order{}
+			for _, order := range orders {
+				order.Direction = map[string]string{"asc": "desc", "desc": "asc"}[order.Direction]
+				clone.Query.Orders = append(clone.Query.Orders, order)
+			}
+		}
+	}
+
+	items, err := clone.Limit(pageSize + 1).Builder().getChunkResults(v...)
+	if err != nil {
+		return xun.MakeCP(pageSize, "", ""), err
+	}
+
+	hasMore := len(items) > pageSize
+	if hasMore {
+		items = items[:pageSize]
+	}
+
+	backward := token != nil && !token.Next
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	builder.resizeChunkResults(pageSize, backward, v...)
+
+	if len(items) == 0 {
+		return xun.MakeCP(pageSize, "", ""), nil
+	}
+
+	// There is a next page if there are more rows after this page, or we came back from it.
+	// There is a previous page if we came forward from it, or there are more rows before this page.
+	next := ""
+	if hasMore || backward {
+		next, err = builder.encodeCursor(orders, items[len(items)-1], true)
+		if err != nil {
+			return xun.MakeCP(pageSize, "", ""), err
+		}
+	}
+
+	prev := ""
+	if (token != nil && token.Next) || (backward && hasMore) {
+		prev, err = builder.encodeCursor(orders, items[0], false)
+		if err != nil {
+			return xun.MakeCP(pageSize, "", ""), err
+		}
+	}
+
+	return xun.MakeCP(pageSize, next, prev, items...), nil
+}
+
+// MustCursorPaginate Paginate the given query into a cursor paginator.
+func (builder *Builder) MustCursorPaginate(pageSize int, cursor string, v ...interface{}) xun.CP {
+	res, err := builder.CursorPaginate(pageSize, cursor, v...)
+	utils.PanicIF(err)
+	return res
+}
+
+// windowTotalColumn the column name of the total records computed by COUNT(*) OVER()
+const windowTotalColumn = "__xun_total"
+
+// cursorToken the decoded cursor of the cursor paginator
+type cursorToken struct {
+	Next   bool                   `json:"next"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// getCursorOrders Get the orders used by the cursor paginator, only the basic orders with a column name are supported.
+func (builder *Builder) getCursorOrders() ([]dbal.Order, error) {
+	if len(builder.Query.Unions) > 0 {
+		return nil, fmt.Errorf("The cursor paginator does not support union queries")
+	}
+
+	if len(builder.Query.Orders) == 0 {
+		return nil, fmt.Errorf("You must specify an orderBy clause when using the cursor paginator")
+	}
+
+	for _, order := range builder.Query.Orders {
+		if _, ok := order.Column.(string); !ok || order.Type != "basic" {
+			return nil, fmt.Errorf("The cursor paginator only supports the orderBy clauses with a column name")
+		}
+	}
+	return builder.Query.Orders, nil
+}
+
+// whereCursor Add the keyset constraints for the given cursor,  (c1 > v1) or (c1 = v1 and c2 > v2) ...
+func (builder *Builder) whereCursor(orders []dbal.Order, token *cursorToken) {
+	builder.Where(func(qb Query) {
+		for i := range orders {
+			qb.OrWhere(func(qb Query) {
+				for _, order := range orders[:i] {
+					column := order.Column.(string)
+					qb.Where(column, "=", token.Params[cursorKey(column)])
+				}
+
+				column := orders[i].Column.(string)
+				operator := ">"
+				if (orders[i].Direction == "desc") == token.Next {
+					operator = "<"
+				}
+				qb.Where(column, operator, token.Params[cursorKey(column)])
+			})
+		}
+	})
+}
+
+// encodeCursor Encode the cursor using the order columns values of the given item
+func (builder *Builder) encodeCursor(orders []dbal.Order, item interface{}, next bool) (string, error) {
+	token := cursorToken{Next: next, Params: map[string]interface{}{}}
+	for _, order := range orders {
+		key := cursorKey(order.Column.(string))
+		value := builder.getItemValue(item, key)
+		if value == nil {
+			return "", fmt.Errorf("The cursor paginator was aborted because the [%s] column is not present in the query result or it is null", key)
+		}
+		token.Params[key] = value
+	}
+
+	bytes, err := json.Marshal(token)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// decodeCursor Decode the given cursor, returns nil if the cursor is empty
+func decodeCursor(cursor string) (*cursorToken, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	bytes, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("The cursor is invalid. %s", err)
+	}
+
+	token := cursorToken{}
+	decoder := json.NewDecoder(strings.NewReader(string(bytes)))
+	decoder.UseNumber()
+	err = decoder.Decode(&token)
+	if err != nil {
+		return nil, fmt.Errorf("The cursor is invalid. %s", err)
+	}
+
+	for key, value := range token.Params {
+		if number, ok := value.(json.Number); ok {
+			if v, err := number.Int64(); err == nil {
+				token.Params[key] = v
+			} else if v, err := number.Float64(); err == nil {
+				token.Params[key] = v
+			}
+		}
+	}
+	return &token, nil
+}
+
+// cursorKey Get the result key of the given order column
+func cursorKey(column string) string {
+	if pos := strings.LastIndex(column, "."); pos >= 0 {
+		return column[pos+1:]
+	}
+	return column
+}
+
 // Set the limit and offset for a given page.
 func (builder *Builder) forPage(page int, pageSize int) Query {
 	return builder.Offset((page - 1) * pageSize).Limit(pageSize)
 }
 
-// @todo: forPageBeforeID  Constrain the query to the previous "page" of results before a given ID.
-// func (builder *Builder) forPageBeforeID(pageSize int, lastID int, column string) Query {
-// 	builder.Query.Orders = builder.removeExistingOrdersFor(column)
-// 	if lastID != 0 {
-// 		builder.Where(column, "<", lastID)
-// 	}
-// 	return builder.OrderBy(column, "desc").Limit(pageSize)
-// }
-
-// @todo: forPageAfterID  Constrain the query to the next "page" of results after a given ID.
-// func (builder *Builder) forPageAfterID(pageSize int, lastID int, column string) Query {
-// 	builder.Query.Orders = builder.removeExistingOrdersFor(column)
-// 	if lastID != 0 {
-// 		builder.Where(column, ">", lastID)
-// 	}
-// 	return builder.OrderBy(column, "asc").Limit(pageSize)
-// }
+// forPageBeforeID  Constrain the query to the previous "page" of results before a given ID.
+func (builder *Builder) forPageBeforeID(pageSize int, lastID interface{}, column string) Query {
+	builder.Query.Orders = builder.removeExistingOrdersFor(column)
+	if lastID != nil {
+		builder.Where(column, "<", lastID)
+	}
+	return builder.OrderBy(column, "desc").Limit(pageSize)
+}
+
+// forPageAfterID  Constrain the query to the next "page" of results after a given ID.
+func (builder *Builder) forPageAfterID(pageSize int, lastID interface{}, column string) Query {
+	builder.Query.Orders = builder.removeExistingOrdersFor(column)
+	if lastID != nil {
+		builder.Where(column, ">", lastID)
+	}
+	return builder.OrderBy(column, "asc").Limit(pageSize)
+}
 
 // getCountForPagination  Get the count of the total records for the paginator.
 func (builder *Builder) getCountForPagination(columns []interface{}) (int, error) {
@@ -1,6 +1,7 @@
 package query
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -293,6 +294,65 @@ func TestPaginateChunkWithBindError(t *testing.T) {
 	})
 }
 
+func TestPaginateChunkByID(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("email", "like", "%@yao.run").
+		Select("id", "name", "email", "vote", "score", "status")
+
+	pages := []int{}
+	IDs := []int64{}
+	qb.MustChunkByID(3, "id", "", func(items []interface{}, page int) error {
+		pages = append(pages, page)
+		for _, item := range items {
+			id := item.(xun.R).Get("id").(int64)
+			IDs = append(IDs, id)
+			// update the chunking rows should not skip or repeat any row
+			qb.New().Table("table_test_paginate").Where("id", id).MustUpdate(xun.R{"email": fmt.Sprintf("%d@yao.run", id*10)})
+		}
+		return nil
+	})
+	assert.Equal(t, []int{1, 2}, pages, "The pages should be []int{1, 2}")
+	assert.Equal(t, []int64{1, 2, 3, 4}, IDs, "The chunk id of items ids should be []int64{1,2,3,4}")
+
+	IDs = []int64{}
+	qb.MustChunkByIDDesc(2, "table_test_paginate.id", "id", func(items []interface{}, page int) error {
+		for _, item := range items {
+			IDs = append(IDs, item.(xun.R).Get("id").(int64))
+		}
+		return nil
+	})
+	assert.Equal(t, []int64{4, 3, 2, 1}, IDs, "The chunk id of items ids should be []int64{4,3,2,1}")
+}
+
+func TestPaginateChunkByIDWithBind(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("email", "like", "%@yao.run").
+		Select("id", "email", "vote")
+
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	items := []Item{}
+	IDs := []int64{}
+	qb.MustChunkByID(3, "id", "id", func(items []interface{}, page int) error {
+		for _, item := range items {
+			IDs = append(IDs, item.(Item).ID)
+		}
+		return nil
+	}, &items)
+	assert.Equal(t, []int64{1, 2, 3, 4}, IDs, "The chunk id of items ids should be []int64{1,2,3,4}")
+
+	err := qb.ChunkByID(3, "id", "missing", func(items []interface{}, page int) error { return nil }, &items)
+	assert.NotNil(t, err, "The missing alias should return an error")
+}
+
 // clean the test data
 func TestPaginateClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
@@ -340,3 +400,214 @@ func NewTableForPaginateTest() {
 		{"t1_id": 4, "name": "Elizabeth", "status": "DONE", "created_at": "2021-03-27 14:00:22"},
 	})
 }
+
+func TestPaginateCursorPaginate(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "name", "email", "vote", "status").
+		OrderBy("status", "desc").
+		OrderBy("id")
+
+	// WAITING(1), PENDING(2), DONE(3), DONE(4)
+	page := qb.MustCursorPaginate(3, "")
+	assert.Equal(t, 3, len(page.Items), "The items count should be 3")
+	assert.Equal(t, 3, page.PageSize, "The page size should be 3")
+	assert.Equal(t, "", page.PreviousCursor, "The first page should not have a previous cursor")
+	assert.NotEqual(t, "", page.NextCursor, "The first page should have a next cursor")
+	assert.Equal(t, []int64{1, 2, 3}, cursorPaginateIDs(page.Items))
+
+	page = qb.MustCursorPaginate(3, page.NextCursor)
+	assert.Equal(t, []int64{4}, cursorPaginateIDs(page.Items))
+	assert.Equal(t, "", page.NextCursor, "The last page should not have a next cursor")
+	assert.NotEqual(t, "", page.PreviousCursor, "The last page should have a previous cursor")
+
+	page = qb.MustCursorPaginate(3, page.PreviousCursor)
+	assert.Equal(t, []int64{1, 2, 3}, cursorPaginateIDs(page.Items))
+	assert.Equal(t, "", page.PreviousCursor, "The first page should not have a previous cursor")
+	assert.NotEqual(t, "", page.NextCursor, "The first page should have a next cursor")
+}
+
+func TestPaginateCursorPaginateDescWithBind(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "email", "vote").
+		OrderByDesc("vote")
+
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	items := []Item{}
+	page := qb.MustCursorPaginate(2, "", &items)
+	assert.Equal(t, 2, len(page.Items), "The items count should be 2")
+	if len(page.Items) == 2 {
+		assert.Equal(t, 125, page.Items[0].(Item).Vote)
+		assert.Equal(t, 10, page.Items[1].(Item).Vote)
+	}
+
+	page = qb.MustCursorPaginate(2, page.NextCursor, &items)
+	if len(page.Items) == 2 {
+		assert.Equal(t, 6, page.Items[0].(Item).Vote)
+		assert.Equal(t, 5, page.Items[1].(Item).Vote)
+	}
+	assert.Equal(t, "", page.NextCursor, "The last page should not have a next cursor")
+}
+
+func TestPaginateCursorPaginateBackwardWithBind(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Select("id", "email", "vote").
+		OrderBy("id")
+
+	type Item struct {
+		ID    int64
+		Email string
+		Vote  int
+	}
+
+	items := []Item{}
+	page := qb.MustCursorPaginate(2, "", &items)
+	assert.Equal(t, 2, len(items), "The binding should have 2 rows")
+
+	page = qb.MustCursorPaginate(2, page.NextCursor, &items)
+	assert.Equal(t, 2, len(items), "The binding should have 2 rows")
+
+	page = qb.MustCursorPaginate(1, page.PreviousCursor, &items)
+	assert.Equal(t, 1, len(page.Items), "The items count should be 1")
+	assert.Equal(t, 1, len(items), "The binding should have 1 row")
+	if len(items) == 1 {
+		assert.Equal(t, int64(2), items[0].ID)
+		assert.Equal(t, page.Items[0].(Item).ID, items[0].ID)
+	}
+
+	page = qb.MustCursorPaginate(2, page.NextCursor, &items)
+	page = qb.MustCursorPaginate(2, page.PreviousCursor, &items)
+	assert.Equal(t, 2, len(items), "The binding should have 2 rows")
+	if len(items) == 2 && len(page.Items) == 2 {
+		assert.Equal(t, []int64{1, 2}, []int64{items[0].ID, items[1].ID})
+		assert.Equal(t, page.Items[0].(Item).ID, items[0].ID)
+		assert.Equal(t, page.Items[1].(Item).ID, items[1].ID)
+	}
+}
+
+func TestPaginateCursorPaginateError(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	_, err := qb.Table("table_test_paginate").CursorPaginate(2, "")
+	assert.NotNil(t, err, "The query without orderBy clause should return an error")
+
+	_, err = qb.Table("table_test_paginate").OrderBy("id").CursorPaginate(2, "invalid cursor")
+	assert.NotNil(t, err, "The invalid cursor should return an error")
+
+	_, err = qb.Table("table_test_paginate").Select("email").OrderBy("id").CursorPaginate(2, "")
+	assert.NotNil(t, err, "The order column which is not selected should return an error")
+}
+
+func cursorPaginateIDs(items []interface{}) []int64 {
+	ids := []int64{}
+	for _, item := range items {
+		ids = append(ids, item.(xun.R).Get("id").(int64))
+	}
+	return ids
+}
+
+func TestPaginateSimplePaginate(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("email", "like", "%@yao.run").
+		Select("id", "name", "email").
+		OrderBy("id")
+
+	paginator := qb.MustSimplePaginate(3, 1)
+	assert.Equal(t, 3, len(paginator.Items), "The items count should be 3")
+	assert.Equal(t, 1, paginator.CurrentPage, "The current page should be 1")
+	assert.Equal(t, 2, paginator.NextPage, "The next page should be 2")
+	assert.Equal(t, -1, paginator.PreviousPage, "The previous page should be -1")
+
+	paginator = qb.MustSimplePaginate(3, 2)
+	assert.Equal(t, 1, len(paginator.Items), "The items count should be 1")
+	assert.Equal(t, -1, paginator.NextPage, "The next page should be -1")
+	assert.Equal(t, 1, paginator.PreviousPage, "The previous page should be 1")
+	if len(paginator.Items) == 1 {
+		assert.Equal(t, int64(4), paginator.Items[0].(xun.R).Get("id"), "The item id should be 4")
+	}
+
+	type Item struct {
+		ID    int64
+		Name  string
+		Email string
+	}
+	items := []Item{}
+	paginator = qb.MustSimplePaginate(2, 2, &items)
+	assert.Equal(t, 2, len(paginator.Items), "The items count should be 2")
+	assert.Equal(t, -1, paginator.NextPage, "The next page should be -1")
+
+	paginator = qb.MustSimplePaginate(3, 1, &items)
+	assert.Equal(t, 2, paginator.NextPage, "The next page should be 2")
+	assert.Equal(t, 3, len(items), "The binding should have 3 rows")
+	if len(items) == 3 {
+		assert.Equal(t, int64(3), items[2].ID, "The last bound id should be 3")
+	}
+}
+
+func TestPaginateSimplePaginateWithTotal(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("email", "like", "%@yao.run").
+		OrderBy("id")
+
+	paginator := qb.MustSimplePaginateWithTotal(3, 1)
+	assert.Equal(t, 4, paginator.Total, "The records total should be 4")
+	assert.Equal(t, 2, paginator.TotalPages, "The total pages should be 2")
+	assert.Equal(t, 3, len(paginator.Items), "The items count should be 3")
+	if len(paginator.Items) == 3 {
+		assert.Nil(t, paginator.Items[0].(xun.R).Get("__xun_total"), "The total column should be removed")
+		assert.Equal(t, "john@yao.run", paginator.Items[0].(xun.R).Get("email"))
+	}
+
+	paginator = qb.MustSimplePaginateWithTotal(3, 3)
+	assert.Equal(t, 4, paginator.Total, "The records total should be 4")
+	assert.Equal(t, 0, len(paginator.Items), "The items count should be 0")
+}
+
+func TestPaginateSimplePaginateWithTotalBind(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_paginate").
+		Where("email", "like", "%@yao.run").
+		Select("id", "name", "email").
+		OrderBy("id")
+
+	type Item struct {
+		ID    int64
+		Name  string
+		Email string
+	}
+
+	items := []Item{}
+	paginator := qb.MustSimplePaginateWithTotal(3, 2, &items)
+	assert.Equal(t, 4, paginator.Total, "The records total should be 4")
+	assert.Equal(t, 1, len(paginator.Items), "The items count should be 1")
+	assert.Equal(t, 1, len(items), "The binding should have 1 row")
+	if len(items) == 1 {
+		assert.Equal(t, int64(4), items[0].ID, "The item id should be 4")
+		assert.Equal(t, items[0], paginator.Items[0].(Item))
+	}
+
+	paginator = qb.MustSimplePaginateWithTotal(3, 3, &items)
+	assert.Equal(t, 4, paginator.Total, "The records total should be 4")
+	assert.Equal(t, 0, len(items), "The binding should be empty")
+
+	// The other slices are bound by Paginate
+	ids := []int64{}
+	paginator = qb.Select("id").MustSimplePaginateWithTotal(3, 1, &ids)
+	assert.Equal(t, 4, paginator.Total, "The records total should be 4")
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+}
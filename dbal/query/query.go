@@ -19,9 +19,13 @@ func (builder *Builder) Table(name string) Query {
 
 // Get Execute the query as a "select" statement.
 func (builder *Builder) Get(v ...interface{}) ([]xun.R, error) {
-	db := builder.DB()
+	if builder.remembered(v...) {
+		return builder.getRemembered()
+	}
+
+	db := builder.executor()
 	sql := builder.ToSQL()
-	stmt, err := db.Prepare(sql)
+	stmt, err := db.PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		defer log.With(log.F{"bindings": builder.GetBindings()}).Error("%s", sql)
 		return nil, err
@@ -29,7 +33,7 @@ func (builder *Builder) Get(v ...interface{}) ([]xun.R, error) {
 
 	defer stmt.Close()
 
-	rows, err := stmt.Query(builder.GetBindings()...)
+	rows, err := stmt.QueryContext(builder.ctx(), builder.GetBindings()...)
 	if err != nil {
 		return nil, err
 	}
@@ -38,7 +42,7 @@ func (builder *Builder) Get(v ...interface{}) ([]xun.R, error) {
 		if reflect.TypeOf(v[0]).Kind() != reflect.Ptr {
 			return nil, fmt.Errorf("The input param is %s, it should be a pointer", reflect.TypeOf(v[0]).Kind().String())
 		}
-		err := builder.structScan(rows, v[0])
+		err := builder.structScan(rows, v[0], nil)
 		if err != nil {
 			return nil, err
 		}
@@ -76,20 +80,22 @@ func (builder *Builder) MustFirst(v ...interface{}) xun.R {
 
 // ToSQL Get the SQL representation of the query.
 func (builder *Builder) ToSQL() string {
-	return builder.Grammar.CompileSelect(builder.Query)
+	qb := builder.scoped()
+	return qb.Grammar.CompileSelect(qb.Query)
 }
 
 // GetBindings Get the current query value bindings in a flattened array.
 func (builder *Builder) GetBindings() []interface{} {
-	return builder.Query.GetBindings()
+	return builder.scoped().Query.GetBindings()
 }
 
 // Exists Determine if any rows exist for the current query.
 func (builder *Builder) Exists() (bool, error) {
-	sql := builder.Grammar.CompileExists(builder.Query)
+	qb := builder.scoped()
+	sql := qb.Grammar.CompileExists(qb.Query)
 
-	db := builder.DB()
-	rows, err := db.Query(sql, builder.GetBindings()...)
+	db := builder.executor()
+	rows, err := db.QueryContext(builder.ctx(), sql, qb.Query.GetBindings()...)
 	if err != nil {
 		return false, err
 	}
@@ -0,0 +1,365 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/kun/log"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Returning Set the columns returned by InsertReturning, UpdateReturning, DeleteReturning and UpsertReturning. all of the columns are returned if not set.
+// The other write methods (Insert, Update, Delete, Upsert ...) return an error if the returning columns are set.
+// Returning("id", "name")
+// Returning("id,name")
+func (builder *Builder) Returning(columns ...interface{}) Query {
+	builder.Query.Returning = builder.prepareColumns(columns...)
+	return builder
+}
+
+// InsertReturning Insert new records into the database and get the inserted rows.
+// MySQL selects the rows after the insertion inside a transaction, the table should have a single column primary key.
+// The rows are inserted one by one if the values of the primary key are not given, the primary key should be auto-increment then.
+func (builder *Builder) InsertReturning(v interface{}, columns ...interface{}) ([]xun.R, error) {
+	groups, err := builder.prepareInsertValues(v, columns...)
+	if err != nil {
+		return nil, err
+	}
+
+	if builder.Grammar.SupportsReturning("insert") {
+		return builder.queryReturningGroups(groups, func(qb *Builder, group insertGroup) (string, []interface{}) {
+			return qb.Grammar.CompileInsert(qb.Query, group.columns, group.values)
+		})
+	}
+
+	return builder.transactionReturning(func(tx *Builder) ([]xun.R, error) {
+		primary, err := tx.returningKey()
+		if err != nil {
+			return nil, err
+		}
+
+		key := primary.Name
+		defer tx.flushCache()
+		insert := func(columns []interface{}, values [][]interface{}) (int64, error) {
+			sql, bindings := tx.Grammar.CompileInsert(tx.Query, columns, values)
+			defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
+			res, err := tx.executor().ExecContext(tx.ctx(), sql, bindings...)
+			if err != nil {
+				return 0, err
+			}
+			return res.LastInsertId()
+		}
+
+		keys := []interface{}{}
+		for _, group := range groups {
+			position := -1
+			for i, column := range group.columns {
+				if fmt.Sprintf("%v", column) == key {
+					position = i
+				}
+			}
+
+			// The values of the primary key were given
+			if position >= 0 {
+				for _, value := range group.values {
+					keys = append(keys, value[position])
+				}
+				if _, err := insert(group.columns, group.values); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			// The ids of the rows could not be known without the auto-increment primary key (e.g. UUID keys)
+			if utils.StringVal(primary.Extra) != "AutoIncrement" {
+				return nil, fmt.Errorf("the values of the primary key %s should be given to return the rows, it is not auto-increment", key)
+			}
+
+			// The auto-increment ids of a multiple-row insert are not always consecutive (auto_increment_increment,
+			// the interleaved lock mode), so the rows are inserted one by one to get their ids.
+			for _, value := range group.values {
+				id, err := insert(group.columns, [][]interface{}{value})
+				if err != nil {
+					return nil, err
+				}
+				if id == 0 {
+					return nil, fmt.Errorf("the inserted id of the primary key %s is unusable to return the rows", key)
+				}
+				keys = append(keys, id)
+			}
+		}
+		return tx.selectReturning(key, keys)
+	})
+}
+
+// MustInsertReturning Insert new records into the database and get the inserted rows.
+func (builder *Builder) MustInsertReturning(v interface{}, columns ...interface{}) []xun.R {
+	rows, err := builder.InsertReturning(v, columns...)
+	utils.PanicIF(err)
+	return rows
+}
+
+// UpdateReturning Update records in the database and get the updated rows.
+// MySQL selects the rows after the update inside a transaction, the table should have a single column primary key which is not updated.
+func (builder *Builder) UpdateReturning(v interface{}) ([]xun.R, error) {
+	if builder.Grammar.SupportsReturning("update") {
+		values := builder.touchUpdate(builder.prepareUpdateValues(v))
+		sql, bindings := builder.Grammar.CompileUpdate(builder.scoped().Query, values)
+		return builder.queryReturning(sql, bindings)
+	}
+
+	return builder.transactionReturning(func(tx *Builder) ([]xun.R, error) {
+		primary, err := tx.returningKey()
+		if err != nil {
+			return nil, err
+		}
+
+		keys, err := tx.lockedKeys(primary.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.update(v)
+		if err != nil {
+			return nil, err
+		}
+		return tx.selectReturning(primary.Name, keys)
+	})
+}
+
+// MustUpdateReturning Update records in the database and get the updated rows.
+func (builder *Builder) MustUpdateReturning(v interface{}) []xun.R {
+	rows, err := builder.UpdateReturning(v)
+	utils.PanicIF(err)
+	return rows
+}
+
+// DeleteReturning Delete records from the database and get the deleted rows.
+// MySQL selects the rows before the deletion inside a transaction. The rows are soft deleted if the table supports soft deletes.
+func (builder *Builder) DeleteReturning() ([]xun.R, error) {
+	if column := builder.trashedColumn(); column != "" {
+		return builder.UpdateReturning(builder.trashedValues(column))
+	}
+
+	if builder.Grammar.SupportsReturning("delete") {
+		sql, bindings := builder.Grammar.CompileDelete(builder.scoped().Query)
+		return builder.queryReturning(sql, bindings)
+	}
+
+	return builder.transactionReturning(func(tx *Builder) ([]xun.R, error) {
+		qb := tx.clone()
+		qb.Query.Columns = builder.returningColumns()
+		qb.Query.Lock = "update"
+		rows, err := qb.Get()
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.delete()
+		if err != nil {
+			return nil, err
+		}
+		return rows, nil
+	})
+}
+
+// MustDeleteReturning Delete records from the database and get the deleted rows.
+func (builder *Builder) MustDeleteReturning() []xun.R {
+	rows, err := builder.DeleteReturning()
+	utils.PanicIF(err)
+	return rows
+}
+
+// UpsertReturning Upsert new records or update the existing ones, and get the inserted or updated rows.
+// MySQL selects the rows by the unique columns after the upsert inside a transaction.
+func (builder *Builder) UpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) ([]xun.R, error) {
+	if builder.Grammar.SupportsReturning("upsert") {
+		groups, err := builder.prepareInsertValues(v, columns...)
+		if err != nil {
+			return nil, err
+		}
+		return builder.queryReturningGroups(groups, func(qb *Builder, group insertGroup) (string, []interface{}) {
+			return qb.Grammar.CompileUpsert(qb.Query, group.columns, group.values, utils.Flatten(uniqueBy), qb.touchUpsert(qb.prepareUpsertValues(update)))
+		})
+	}
+
+	return builder.transactionReturning(func(tx *Builder) ([]xun.R, error) {
+		_, err := tx.upsert(v, uniqueBy, update, columns...)
+		if err != nil {
+			return nil, err
+		}
+
+		groups, err := tx.prepareInsertValues(v, columns...)
+		if err != nil {
+			return nil, err
+		}
+
+		qb := tx.new()
+		qb.Query.From = tx.Query.CopyFrom()
+		qb.Query.Columns = builder.returningColumns()
+		qb.WithoutScopes()
+		qb.Where(func(qb Query) {
+			for _, group := range groups {
+				positions := map[string]int{}
+				for i, column := range group.columns {
+					positions[fmt.Sprintf("%v", column)] = i
+				}
+				for _, value := range group.values {
+					attributes := map[string]interface{}{}
+					for _, unique := range utils.Flatten(uniqueBy) {
+						name := fmt.Sprintf("%v", unique)
+						if i, has := positions[name]; has {
+							attributes[name] = value[i]
+						}
+					}
+					qb.OrWhere(attributes)
+				}
+			}
+		})
+		return qb.Get()
+	})
+}
+
+// MustUpsertReturning Upsert new records or update the existing ones, and get the inserted or updated rows.
+func (builder *Builder) MustUpsertReturning(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) []xun.R {
+	rows, err := builder.UpsertReturning(v, uniqueBy, update, columns...)
+	utils.PanicIF(err)
+	return rows
+}
+
+// queryReturning Execute the write statement with the "returning" clause and get the returned rows.
+func (builder *Builder) queryReturning(sql string, bindings []interface{}) ([]xun.R, error) {
+	defer builder.flushCache()
+	sql = fmt.Sprintf("%s %s", strings.TrimSpace(sql), builder.Grammar.CompileReturning(builder.Query, builder.Query.Returning))
+	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
+
+	builder.UseWrite()
+	rows, err := builder.executor().QueryContext(builder.ctx(), sql, bindings...)
+	if err != nil {
+		return nil, err
+	}
+	return builder.mapScan(rows)
+}
+
+// queryReturningGroups Execute the write statement with the "returning" clause of each group of the rows which have the same columns,
+// the statements are executed within a transaction if there are more than one group.
+func (builder *Builder) queryReturningGroups(groups []insertGroup, compile func(qb *Builder, group insertGroup) (string, []interface{})) ([]xun.R, error) {
+	if len(groups) == 1 {
+		sql, bindings := compile(builder, groups[0])
+		return builder.queryReturning(sql, bindings)
+	}
+
+	return builder.transactionReturning(func(tx *Builder) ([]xun.R, error) {
+		rows := []xun.R{}
+		for _, group := range groups {
+			sql, bindings := compile(tx, group)
+			res, err := tx.queryReturning(sql, bindings)
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, res...)
+		}
+		return rows, nil
+	})
+}
+
+// transactionReturning Execute the callback within a transaction, for the databases which do not support the "returning" clause.
+func (builder *Builder) transactionReturning(callback func(tx *Builder) ([]xun.R, error)) ([]xun.R, error) {
+	qb, err := builder.Begin()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := qb.(*Builder)
+	rows, err := callback(tx)
+	if err != nil {
+		if rerr := tx.Rollback(); rerr != nil {
+			return nil, fmt.Errorf("%s (rollback error: %s)", err, rerr)
+		}
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// returningKey Get the primary key column of the table, the returned rows are selected by the primary key.
+func (builder *Builder) returningKey() (*dbal.Column, error) {
+	name, ok := builder.Query.From.Name.(dbal.Name)
+	if !ok {
+		return nil, fmt.Errorf("the returning rows should be selected from a table")
+	}
+
+	table, err := builder.Grammar.GetTable(name.Fullname())
+	if err != nil {
+		return nil, err
+	}
+
+	if table.Primary == nil || len(table.Primary.Columns) != 1 {
+		return nil, fmt.Errorf("the table %s should have a single column primary key to return the rows", name.Fullname())
+	}
+	return table.Primary.Columns[0], nil
+}
+
+// checkReturning The returning columns are returned by the "returning" methods only, the other write methods return an error instead of ignoring them.
+func (builder *Builder) checkReturning(method string) error {
+	if len(builder.Query.Returning) > 0 {
+		return fmt.Errorf("%s does not return the rows, use the returning methods (InsertReturning, UpdateReturning, DeleteReturning, UpsertReturning) instead", method)
+	}
+	return nil
+}
+
+// lockedKeys Lock the rows of the query for update and get the values of the primary key.
+func (builder *Builder) lockedKeys(key string) ([]interface{}, error) {
+	column := key
+	if len(builder.Query.Joins) > 0 {
+		name := builder.Query.From.Name.(dbal.Name)
+		table := name.Fullname()
+		if name.Alias != "" {
+			table = name.Alias
+		}
+		column = fmt.Sprintf("%s.%s", table, key)
+	}
+
+	qb := builder.clone()
+	qb.Query.Columns = []interface{}{column}
+	qb.Query.Lock = "update"
+	rows, err := qb.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []interface{}{}
+	for _, row := range rows {
+		keys = append(keys, row.Get(key))
+	}
+	return keys, nil
+}
+
+// selectReturning Select the returning columns of the rows by the values of the primary key.
+func (builder *Builder) selectReturning(key string, keys []interface{}) ([]xun.R, error) {
+	if len(keys) == 0 {
+		return []xun.R{}, nil
+	}
+
+	qb := builder.new()
+	qb.Query.From = builder.Query.CopyFrom()
+	qb.Query.Columns = builder.returningColumns()
+
+	// The rows are selected by the keys, the updated rows may no longer match the global scopes.
+	qb.WithoutScopes()
+	return qb.WhereIn(key, keys).OrderBy(key).Get()
+}
+
+// returningColumns Get the returning columns of the query.
+func (builder *Builder) returningColumns() []interface{} {
+	if len(builder.Query.Returning) == 0 {
+		return []interface{}{"*"}
+	}
+	return builder.Query.Returning
+}
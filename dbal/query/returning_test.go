@@ -0,0 +1,173 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestReturningInsertReturning(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Returning("id", "name", "status").
+		MustInsertReturning([]xun.R{
+			{"email": "max@yao.run", "name": "Max", "vote": 1, "score": 10.5, "score_grade": 10.5},
+			{"email": "kim@yao.run", "name": "Kim", "vote": 2, "score": 20.5, "score_grade": 20.5},
+		})
+
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Max", rows[0].Get("name"))
+	assert.Equal(t, "WAITING", rows[0].Get("status"))
+	assert.Equal(t, "Kim", rows[1].Get("name"))
+	assert.Equal(t, int64(5), xun.MakeN(rows[0].Get("id")).MustInt64())
+	assert.Nil(t, rows[0].Get("email"))
+}
+
+func TestReturningUpdateReturning(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Where("status", "DONE").
+		Returning("name,vote").
+		MustUpdateReturning(xun.R{"vote": 0})
+
+	assert.Equal(t, 2, len(rows))
+	names := []interface{}{}
+	for _, row := range rows {
+		names = append(names, row.Get("name"))
+		assert.Equal(t, int64(0), xun.MakeN(row.Get("vote")).MustInt64())
+	}
+	assert.ElementsMatch(t, []interface{}{"Ken", "Ben"}, names)
+}
+
+func TestReturningDeleteReturning(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Where("name", "Lee").
+		MustDeleteReturning()
+
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "lee@yao.run", rows[0].Get("email"))
+	assert.Equal(t, int64(3), qb.Table("table_test_paginate").MustCount())
+}
+
+func TestReturningUpsertReturning(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Returning("email", "vote").
+		MustUpsertReturning([]xun.R{
+			{"email": "john@yao.run", "name": "John", "vote": 11, "score": 96.32, "score_grade": 99.27},
+			{"email": "max@yao.run", "name": "Max", "vote": 1, "score": 10.5, "score_grade": 10.5},
+		}, []string{"email"}, []string{"vote"})
+
+	assert.Equal(t, 2, len(rows))
+	votes := map[interface{}]int64{}
+	for _, row := range rows {
+		votes[row.Get("email")] = xun.MakeN(row.Get("vote")).MustInt64()
+	}
+	assert.Equal(t, map[interface{}]int64{"john@yao.run": 11, "max@yao.run": 1}, votes)
+}
+
+func TestReturningWriteMethodsError(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	err := qb.Table("table_test_paginate").Returning("id").
+		Insert(xun.R{"email": "max@yao.run", "name": "Max", "vote": 1, "score": 10.5, "score_grade": 10.5})
+	assert.NotNil(t, err, "Insert should return an error if the returning columns are set")
+
+	_, err = qb.Table("table_test_paginate").Returning("id").Where("name", "Lee").Update(xun.R{"vote": 0})
+	assert.NotNil(t, err, "Update should return an error if the returning columns are set")
+
+	_, err = qb.Table("table_test_paginate").Returning("id").Where("name", "Lee").Delete()
+	assert.NotNil(t, err, "Delete should return an error if the returning columns are set")
+
+	_, err = qb.Table("table_test_paginate").Returning("id").
+		Upsert(xun.R{"email": "lee@yao.run", "name": "Lee", "vote": 1, "score": 10.5, "score_grade": 10.5}, "email", []string{"vote"})
+	assert.NotNil(t, err, "Upsert should return an error if the returning columns are set")
+
+	assert.Panics(t, func() {
+		qb.Table("table_test_paginate").Returning("id").Where("name", "Lee").MustUpdate(xun.R{"vote": 0})
+	})
+
+	assert.Equal(t, int64(4), qb.Table("table_test_paginate").MustCount())
+	assert.Equal(t, int64(5), qb.Table("table_test_paginate").Where("name", "Lee").MustFirst().Get("vote"))
+}
+
+func TestReturningInsertReturningWithoutReturningClause(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilderInstance().clone()
+	qb.Grammar = grammarWithoutReturning{qb.Grammar}
+	rows := qb.Table("table_test_paginate").
+		Returning("id", "name").
+		MustInsertReturning([]xun.R{
+			{"email": "max@yao.run", "name": "Max", "vote": 1, "score": 10.5, "score_grade": 10.5},
+			{"email": "kim@yao.run", "name": "Kim", "vote": 2, "score": 20.5, "score_grade": 20.5},
+		})
+	assert.Equal(t, 2, len(rows))
+	if len(rows) == 2 {
+		assert.Equal(t, int64(5), xun.MakeN(rows[0].Get("id")).MustInt64())
+		assert.Equal(t, "Kim", rows[1].Get("name"))
+	}
+
+	// The rows having different columns
+	rows = qb.Table("table_test_paginate").
+		Returning("id", "status").
+		MustInsertReturning([]xun.R{
+			{"email": "amy@yao.run", "name": "Amy", "vote": 1, "score": 10.5, "score_grade": 10.5, "status": "DONE"},
+			{"email": "eve@yao.run", "name": "Eve", "vote": 2, "score": 20.5, "score_grade": 20.5},
+		})
+	assert.Equal(t, 2, len(rows))
+	if len(rows) == 2 {
+		assert.Equal(t, "DONE", rows[0].Get("status"))
+		assert.Equal(t, "WAITING", rows[1].Get("status"))
+	}
+
+	NewTableForReturningUUIDTest()
+	_, err := qb.Table("table_test_returning_uuid").
+		InsertReturning(xun.R{"name": "Max"})
+	assert.NotNil(t, err, "The values of the non auto-increment primary key should be given")
+	assert.Equal(t, int64(0), qb.Table("table_test_returning_uuid").MustCount())
+
+	rows = qb.Table("table_test_returning_uuid").
+		MustInsertReturning(xun.R{"id": "0b6e1b54-6f0a-4bd5-8f0e-2a1b3c4d5e6f", "name": "Max"})
+	assert.Equal(t, 1, len(rows))
+	if len(rows) == 1 {
+		assert.Equal(t, "Max", rows[0].Get("name"))
+	}
+}
+
+// clean the test data
+func TestReturningClean(t *testing.T) {
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_returning_uuid")
+}
+
+func NewTableForReturningUUIDTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_returning_uuid")
+	builder.MustCreateTable("table_test_returning_uuid", func(table schema.Blueprint) {
+		table.String("id", 36).Primary()
+		table.String("name")
+	})
+}
+
+// grammarWithoutReturning the grammar of the database which does not support the "returning" clause, e.g. MySQL
+type grammarWithoutReturning struct {
+	dbal.Grammar
+}
+
+func (grammar grammarWithoutReturning) SupportsReturning(statement string) bool {
+	return false
+}
+
+func (grammar grammarWithoutReturning) WithExecutor(executor dbal.Executor) dbal.Grammar {
+	return grammarWithoutReturning{grammar.Grammar.WithExecutor(executor)}
+}
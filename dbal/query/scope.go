@@ -0,0 +1,258 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// scopeAll the name of the excluded global scopes which means all of the scopes
+const scopeAll = "*"
+
+// NewScopes create a new registry of the global query scopes
+func NewScopes() *Scopes {
+	return &Scopes{scopes: []Scope{}, softDeletes: map[string]string{}}
+}
+
+// Register Register a global scope of the table, the scope with the same table and name is replaced.
+func (scopes *Scopes) Register(table string, name string, apply func(qb Query)) {
+	scopes.mutex.Lock()
+	defer scopes.mutex.Unlock()
+	for i, scope := range scopes.scopes {
+		if scope.Table == table && scope.Name == name {
+			scopes.scopes[i].Apply = apply
+			return
+		}
+	}
+	scopes.scopes = append(scopes.scopes, Scope{Table: table, Name: name, Apply: apply})
+}
+
+// Remove Remove the global scope of the table
+func (scopes *Scopes) Remove(table string, name string) {
+	scopes.mutex.Lock()
+	defer scopes.mutex.Unlock()
+	for i, scope := range scopes.scopes {
+		if scope.Table == table && scope.Name == name {
+			scopes.scopes = append(scopes.scopes[:i], scopes.scopes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Of Get the global scopes of the table in the order of registration
+func (scopes *Scopes) Of(table string) []Scope {
+	of := []Scope{}
+	if scopes == nil {
+		return of
+	}
+
+	scopes.mutex.RLock()
+	defer scopes.mutex.RUnlock()
+	for _, scope := range scopes.scopes {
+		if scope.Table == table {
+			of = append(of, scope)
+		}
+	}
+	return of
+}
+
+// RegisterScope Register a global scope of the table for the connection. The where clauses added by the scope are applied
+// to the selects, updates and deletes of the table, the joins and the sub-queries of the table included.
+// The columns of the scope are qualified with the table name, or the alias if given.
+// RegisterScope("users", "active", func(qb Query) { qb.Where("status", "active") })
+func (builder *Builder) RegisterScope(table string, name string, apply func(qb Query)) Query {
+	if builder.Conn.Scopes == nil {
+		builder.Conn.Scopes = NewScopes()
+	}
+	builder.Conn.Scopes.Register(table, name, apply)
+	return builder
+}
+
+// WithoutScope Remove the given global scopes from the query, the scopes of the joined tables are removed as well.
+func (builder *Builder) WithoutScope(names ...string) Query {
+	builder.Query.WithoutScopes = append(builder.Query.WithoutScopes, names...)
+	return builder
+}
+
+// WithoutScopes Remove all of the global scopes from the query.
+func (builder *Builder) WithoutScopes() Query {
+	return builder.WithoutScope(scopeAll)
+}
+
+// withoutScope Determine if the global scope is removed from the query
+func (builder *Builder) withoutScope(name string) bool {
+	return utils.StringHave(builder.Query.WithoutScopes, scopeAll) || utils.StringHave(builder.Query.WithoutScopes, name)
+}
+
+// scoped Get the query with the global scopes of the table and the joined tables applied, the scopes of the table are
+// added after the where clauses of the query, the scopes of an outer joined table are added to the "on" clause.
+// The builder itself is returned if there is no scope to apply.
+func (builder *Builder) scoped() *Builder {
+	if builder.Conn == nil || builder.Query.IsJoinClause {
+		return builder
+	}
+
+	if !builder.Query.OnlyTrashed && (builder.Conn.Scopes == nil || builder.withoutScope(scopeAll)) {
+		return builder
+	}
+
+	qb := builder.clone()
+	applied := false
+	wheres := []dbal.Where{}
+	bindings := []interface{}{}
+	if name, ok := qb.Query.From.Name.(dbal.Name); ok && qb.Query.From.Type == "basic" {
+		wheres, bindings = builder.scopeWheres(name, false, qb.Query.OnlyTrashed)
+	}
+
+	// The bindings of the scopes are inserted after the bindings of the join clause
+	joinBindings := qb.Query.Bindings["join"]
+	scopedBindings := []interface{}{}
+	offset := 0
+	for i, join := range qb.Query.Joins {
+		end := offset + joinBindingsSize(join)
+		if end > len(joinBindings) {
+			end = len(joinBindings)
+		}
+		scopedBindings = append(scopedBindings, joinBindings[offset:end]...)
+		offset = end
+
+		name, ok := join.Name.(dbal.Name)
+		if !ok || join.Type == "raw" || join.Query == nil {
+			continue
+		}
+
+		// The cross join has no "on" clause
+		if len(join.Query.Wheres) == 0 {
+			joinWheres, joinScopeBindings := builder.scopeWheres(name, false, false)
+			wheres = append(wheres, joinWheres...)
+			bindings = append(bindings, joinScopeBindings...)
+			continue
+		}
+
+		joinWheres, joinScopeBindings := builder.scopeWheres(name, true, false)
+		if len(joinWheres) == 0 {
+			continue
+		}
+
+		clause := join.Query.Clone()
+		clause.Wheres = append(groupWheres(clause, clause.Wheres), joinWheres...)
+		clause.AddBinding("where", joinScopeBindings)
+		qb.Query.Joins[i].Query = clause
+		scopedBindings = append(scopedBindings, joinScopeBindings...)
+		applied = true
+	}
+	scopedBindings = append(scopedBindings, joinBindings[offset:]...)
+
+	if len(wheres) > 0 {
+		qb.Query.Wheres = append(groupWheres(qb.Query, qb.Query.Wheres), wheres...)
+		qb.Query.Bindings["where"] = append(append([]interface{}{}, qb.Query.Bindings["where"]...), bindings...)
+		applied = true
+	}
+
+	if !applied {
+		return builder
+	}
+
+	// The scopes of the query are applied, so they would not be applied again.
+	qb.Query.Bindings["join"] = scopedBindings
+	qb.Query.WithoutScopes = append(qb.Query.WithoutScopes, scopeAll)
+	qb.Query.OnlyTrashed = false
+	return qb
+}
+
+// scopeWheres Get the nested where clauses of the global scopes of the table and the bindings of them.
+// The soft delete scope of the table selects the trashed rows instead if onlyTrashed is true.
+func (builder *Builder) scopeWheres(name dbal.Name, isJoinClause bool, onlyTrashed bool) ([]dbal.Where, []interface{}) {
+	wheres := []dbal.Where{}
+	bindings := []interface{}{}
+
+	table := name.Fullname()
+	if name.Alias != "" {
+		table = name.Alias
+	}
+
+	if onlyTrashed && builder.Conn.Scopes.SoftDeletesColumn(name.Name) == "" {
+		panic(fmt.Errorf("the table %s does not support soft deletes", name.Name))
+	}
+
+	for _, scope := range builder.Conn.Scopes.Of(name.Name) {
+		apply := scope.Apply
+		if scope.Name == scopeSoftDeletes && onlyTrashed {
+			column := builder.Conn.Scopes.SoftDeletesColumn(name.Name)
+			apply = func(qb Query) { qb.WhereNotNull(column) }
+		} else if builder.withoutScope(scope.Name) {
+			continue
+		}
+
+		qb := builder.new()
+		qb.Query.From = dbal.From{Type: "basic", Alias: name.Alias, Name: name}
+		qb.Query.IsJoinClause = isJoinClause
+		apply(qb)
+		if len(qb.Query.Wheres) == 0 {
+			continue
+		}
+
+		qualifyWheres(qb.Query.Wheres, table)
+		wheres = append(wheres, dbal.Where{Type: "nested", Query: qb.Query, Boolean: "and"})
+		bindings = append(bindings, qb.Query.Bindings["where"]...)
+	}
+	return wheres, bindings
+}
+
+// joinBindingsSize Get the number of the bindings of the join clause
+func joinBindingsSize(join dbal.Join) int {
+	if join.Type == "raw" {
+		return join.Offset
+	}
+
+	size := 0
+	if sub, ok := join.SQL.(*dbal.Query); ok && join.Alias != "" {
+		size = len(sub.GetBindings())
+	}
+	if join.Query != nil {
+		size = size + len(join.Query.GetBindings())
+	}
+	return size
+}
+
+// groupWheres Group the where clauses into a nested where clause if they have the "or" clauses,
+// so the where clauses of the global scopes could be added with the "and" boolean.
+func groupWheres(query *dbal.Query, wheres []dbal.Where) []dbal.Where {
+	for i, where := range wheres {
+		if i > 0 && strings.ToLower(where.Boolean) == "or" {
+			nested := dbal.NewQuery()
+			nested.From = query.From
+			nested.IsJoinClause = query.IsJoinClause
+			nested.Wheres = wheres
+			return []dbal.Where{{Type: "nested", Query: nested, Boolean: "and"}}
+		}
+	}
+	return append([]dbal.Where{}, wheres...)
+}
+
+// qualifyWheres Qualify the columns of the where clauses with the table name
+func qualifyWheres(wheres []dbal.Where, table string) {
+	for i, where := range wheres {
+		switch where.Type {
+		case "nested":
+			qualifyWheres(where.Query.Wheres, table)
+		case "column":
+			wheres[i].First = qualifyColumn(where.First, table)
+			wheres[i].Second = qualifyColumn(where.Second, table)
+		case "raw", "exists", "fulltext":
+		default:
+			wheres[i].Column = qualifyColumn(where.Column, table)
+		}
+	}
+}
+
+// qualifyColumn Qualify the column with the table name, the qualified columns and the JSON selectors are not changed.
+func qualifyColumn(column interface{}, table string) interface{} {
+	value, ok := column.(string)
+	if !ok || value == "" || strings.Contains(value, ".") || strings.Contains(value, "->") {
+		return column
+	}
+	return table + "." + value
+}
@@ -0,0 +1,179 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestScopeSQL(t *testing.T) {
+	qb := getTestBuilder()
+	defer removeTestScopes(qb)
+	registerTestScopes(qb)
+
+	qb.Table("table_test_scope").Where("id", ">", 1).OrWhere("name", "Ben")
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope" where ("id" > $1 or "name" = $2) and ("table_test_scope"."status" = $3)`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope` where (`id` > ? or `name` = ?) and (`table_test_scope`.`status` = ?)", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{1, "Ben", "active"}, qb.GetBindings())
+
+	// The scopes are applied once, and the query of the builder is not changed
+	assert.Equal(t, sql, qb.ToSQL())
+	assert.Equal(t, 2, len(qb.Builder().Query.Wheres))
+}
+
+func TestScopeWithoutScope(t *testing.T) {
+	qb := getTestBuilder()
+	defer removeTestScopes(qb)
+	registerTestScopes(qb)
+	qb.RegisterScope("table_test_scope", "voted", func(qb Query) {
+		qb.Where("vote", ">", 0)
+	})
+
+	qb.Table("table_test_scope as s").WithoutScope("active").Where("id", 1)
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope" as "s" where "id" = $1 and ("s"."vote" > $2)`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope` as `s` where `id` = ? and (`s`.`vote` > ?)", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{1, 0}, qb.GetBindings())
+
+	qb.Table("table_test_scope").WithoutScopes().Where("id", 1)
+	sql = qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope" where "id" = $1`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope` where `id` = ?", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{1}, qb.GetBindings())
+}
+
+func TestScopeJoin(t *testing.T) {
+	qb := getTestBuilder()
+	defer removeTestScopes(qb)
+	registerTestScopes(qb)
+
+	raw := "inner join table_test_scope_item as i on i.order_id = o.id and i.price > ?"
+	if unit.DriverIs("postgres") {
+		raw = "inner join table_test_scope_item as i on i.order_id = o.id and i.price > $1"
+	}
+	qb.Table("table_test_scope_order as o").
+		JoinRaw(raw, 10).
+		LeftJoin("table_test_scope as u", "u.id", "=", "o.user_id").
+		Where("o.amount", ">", 100)
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope_order" as "o" inner join table_test_scope_item as i on i.order_id = o.id and i.price > $1 left join "table_test_scope" as "u" on "u"."id" = "o"."user_id" and ("u"."status" = $2) where "o"."amount" > $3`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope_order` as `o` inner join table_test_scope_item as i on i.order_id = o.id and i.price > ? left join `table_test_scope` as `u` on `u`.`id` = `o`.`user_id` and (`u`.`status` = ?) where `o`.`amount` > ?", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{10, "active", 100}, qb.GetBindings())
+}
+
+func TestScopeSubquery(t *testing.T) {
+	qb := getTestBuilder()
+	defer removeTestScopes(qb)
+	registerTestScopes(qb)
+
+	qb.Table("table_test_scope_order").
+		WhereIn("user_id", func(sub Query) {
+			sub.Select("id").From("table_test_scope")
+		}).
+		WhereExists(func(sub Query) {
+			sub.From("table_test_scope").WhereColumn("table_test_scope_order.user_id", "id").WithoutScope("active")
+		})
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_scope_order" where "user_id" in (select "id" from "table_test_scope" where ("table_test_scope"."status" = $1)) and exists (select * from "table_test_scope" where "table_test_scope_order"."user_id" = "id")`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_scope_order` where `user_id` in (select `id` from `table_test_scope` where (`table_test_scope`.`status` = ?)) and exists (select * from `table_test_scope` where `table_test_scope_order`.`user_id` = `id`)", sql, "the query sql not equal")
+	}
+	assert.Equal(t, []interface{}{"active"}, qb.GetBindings())
+}
+
+func TestScopeSubqueryPostgres(t *testing.T) {
+	qb := getPostgresTestBuilder()
+	qb.RegisterScope("posts", "tenant", func(qb Query) { qb.Where("tenant_id", 8) })
+	qb.Table("posts").Where("a", 1).WhereIn("b", func(sub Query) {
+		sub.Select("pid").From("votes").Where("v", 3)
+	})
+	assert.Equal(t, `select * from "posts" where "a" = $1 and "b" in (select "pid" from "votes" where "v" = $2) and ("posts"."tenant_id" = $3)`, qb.ToSQL())
+	assert.Equal(t, []interface{}{1, 3, 8}, qb.GetBindings())
+
+	// The scopes of the joined tables are numbered before the sub-queries of the where clauses
+	qb = getPostgresTestBuilder()
+	qb.RegisterScope("users", "visible", func(qb Query) { qb.Where("x", 5) })
+	qb.Table("posts").
+		Join("users", "users.id", "=", "posts.user_id").
+		Where(func(sub Query) {
+			sub.SelectRaw("count(*)").From("votes").WhereColumn("votes.pid", "posts.id").Where("vote", ">", 3)
+		}, ">", 2).
+		OrderBy(func(sub Query) {
+			sub.Select("score").From("ranks").WhereColumn("ranks.pid", "posts.id").Where("season", 7).Limit(1)
+		}, "desc")
+	assert.Equal(t, `select * from "posts" inner join "users" on "users"."id" = "posts"."user_id" and ("users"."x" = $1) where (select count(*) from "votes" where "votes"."pid" = "posts"."id" and "vote" > $2) > $3 order by (select "score" from "ranks" where "ranks"."pid" = "posts"."id" and "season" = $4 limit 1) desc`, qb.ToSQL())
+	assert.Equal(t, []interface{}{5, 3, 2, 7}, qb.GetBindings())
+}
+
+func TestScopeGetUpdateDelete(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	defer removeTestScopes(qb)
+	registerTestScopes(qb)
+
+	rows := qb.Table("table_test_scope").OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "John", rows[0].Get("name"))
+	assert.Equal(t, int64(3), qb.Table("table_test_scope").WithoutScopes().MustCount())
+	assert.True(t, qb.Table("table_test_scope").Where("name", "Ben").MustDoesntExist())
+
+	affected := qb.Table("table_test_scope").MustUpdate(xun.R{"vote": 10})
+	assert.Equal(t, int64(2), affected)
+	assert.Equal(t, int64(2), qb.Table("table_test_scope").WithoutScopes().Where("vote", 10).MustCount())
+
+	affected = qb.Table("table_test_scope").MustDelete()
+	assert.Equal(t, int64(2), affected)
+	rows = qb.Table("table_test_scope").WithoutScopes().MustGet()
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Ben", rows[0].Get("name"))
+}
+
+func registerTestScopes(qb Query) {
+	qb.RegisterScope("table_test_scope", "active", func(qb Query) {
+		qb.Where("status", "active")
+	})
+}
+
+func removeTestScopes(qb Query) {
+	qb.Builder().Conn.Scopes.Remove("table_test_scope", "active")
+	qb.Builder().Conn.Scopes.Remove("table_test_scope", "voted")
+}
+
+// NewTableForScopeTest create the testing table of the global scopes
+func NewTableForScopeTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_scope")
+	builder.MustCreateTable("table_test_scope", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 32)
+		table.String("status", 16)
+		table.Integer("vote")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_scope").MustInsert([]xun.R{
+		{"name": "John", "status": "active", "vote": 3},
+		{"name": "Ben", "status": "disabled", "vote": 5},
+		{"name": "Lee", "status": "active", "vote": 7},
+	})
+}
@@ -7,8 +7,11 @@ import (
 	"github.com/yaoapp/xun/dbal"
 )
 
-// SQL Add a new "raw" sql STMT to the query.
+// SQL Add a new "raw" sql STMT to the query. The bindings could be a map or a struct for the ":name" placeholders.
+// SQL("select * from users where id = ?", 1)
+// SQL("select * from users where id = :id", map[string]interface{}{"id": 1})
 func (builder *Builder) SQL(stmt string, bindings ...interface{}) Query {
+	stmt, bindings = builder.prepareNamedBindings(stmt, bindings)
 	builder.Query.SQL = stmt
 	builder.Query.AddBinding("sql", bindings)
 	return builder
@@ -18,6 +21,7 @@ func (builder *Builder) SQL(stmt string, bindings ...interface{}) Query {
 // Select("field1", "field2")
 // Select("field1", "field2 as f2")
 // Select("field1", dbal.Raw("Count(id) as v"))
+// Select("field1", dbal.Over("row_number").PartitionBy("field2").OrderBy("field1").As("rn"))
 func (builder *Builder) Select(columns ...interface{}) Query {
 	builder.Query.Columns = []interface{}{}
 	builder.Query.Bindings["select"] = []interface{}{}
@@ -46,6 +50,7 @@ func (builder *Builder) SelectAppend(columns ...interface{}) Query {
 
 // SelectRaw Add a new "raw" select expression to the query.
 func (builder *Builder) SelectRaw(expression string, bindings ...interface{}) Query {
+	expression, bindings = builder.prepareNamedBindings(expression, bindings)
 	builder.addSelect(dbal.Raw(expression))
 	builder.Query.AddBinding("select", bindings)
 	return builder
@@ -53,13 +58,12 @@ func (builder *Builder) SelectRaw(expression string, bindings ...interface{}) Qu
 
 // SelectSub Add a subselect expression to the query.
 func (builder *Builder) SelectSub(qb interface{}, as string) Query {
-	sub, bindings, selectOffset := builder.createSub(qb)
-	sql := builder.parseSub(sub)
-	column := dbal.Select{
-		Type:   "sub",
-		Alias:  as,
-		SQL:    fmt.Sprintf("(%s)", sql),
-		Offset: selectOffset - 1,
+	sub, bindings, _ := builder.createSub(qb)
+	column := dbal.Select{Type: "sub", Alias: as}
+	if query, ok := sub.(*dbal.Query); ok {
+		column.Name = query
+	} else {
+		column.SQL = fmt.Sprintf("(%s)", sub)
 	}
 	builder.addSelect(column)
 	builder.Query.AddBinding("select", bindings)
@@ -104,6 +108,6 @@ func (builder *Builder) addSelect(column interface{}) {
 			builder.Query.Columns = append(builder.Query.Columns, column)
 		}
 	default:
-		builder.Query.Columns = append(builder.Query.Columns, column)
+		builder.Query.Columns = append(builder.Query.Columns, builder.windowValue(column))
 	}
 }
@@ -256,6 +256,22 @@ func TestSelectDistinctColumnsStyle3(t *testing.T) {
 	checktestSelectDistinctColumns(t, qb)
 }
 
+func TestSelectSelectSubBindings(t *testing.T) {
+	sqls := map[string]string{
+		"mysql":    "select `id`, (select count(*) from `votes` where `votes`.`uid` = `users`.`id` and `votes`.`score` > ?) as `votes` from `users` where `users`.`id` > ?",
+		"postgres": `select "id", (select count(*) from "votes" where "votes"."uid" = "users"."id" and "votes"."score" > $1) as "votes" from "users" where "users"."id" > $2`,
+		"sqlite3":  "select `id`, (select count(*) from `votes` where `votes`.`uid` = `users`.`id` and `votes`.`score` > ?) as `votes` from `users` where `users`.`id` > ?",
+	}
+	for driver, sql := range sqls {
+		qb := getGrammarTestBuilder(driver)
+		qb.Table("users").Select("id").SelectSub(func(sub Query) {
+			sub.From("votes").SelectRaw("count(*)").WhereColumn("votes.uid", "users.id").Where("votes.score", ">", 5)
+		}, "votes").Where("users.id", ">", 10)
+		assert.Equal(t, sql, qb.ToSQL(), driver)
+		assert.Equal(t, []interface{}{5, 10}, qb.GetBindings(), driver)
+	}
+}
+
 // clean the test data
 func TestSelectClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
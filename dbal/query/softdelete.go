@@ -0,0 +1,156 @@
+package query
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// scopeSoftDeletes the name of the global scope which excludes the soft deleted rows
+const scopeSoftDeletes = "softdeletes"
+
+// softDeletesColumn the default soft delete column, created by the SoftDeletes method of the blueprint
+const softDeletesColumn = "deleted_at"
+
+// RegisterSoftDeletes Register the soft delete column of the table, the rows with the column set are excluded by the global scope.
+func (scopes *Scopes) RegisterSoftDeletes(table string, column string) {
+	scopes.Register(table, scopeSoftDeletes, func(qb Query) {
+		qb.WhereNull(column)
+	})
+
+	scopes.mutex.Lock()
+	defer scopes.mutex.Unlock()
+	if scopes.softDeletes == nil {
+		scopes.softDeletes = map[string]string{}
+	}
+	scopes.softDeletes[table] = column
+}
+
+// SoftDeletesColumn Get the soft delete column of the table, returns "" if the table does not support soft deletes.
+func (scopes *Scopes) SoftDeletesColumn(table string) string {
+	if scopes == nil {
+		return ""
+	}
+	scopes.mutex.RLock()
+	defer scopes.mutex.RUnlock()
+	return scopes.softDeletes[table]
+}
+
+// RegisterSoftDeletes Declare the soft delete column of the table for the connection, the column is "deleted_at" if not given.
+// The selects, updates and aggregates of the table exclude the trashed rows, and the Delete method sets the column instead of deleting the rows.
+// RegisterSoftDeletes("users")
+// RegisterSoftDeletes("users", "removed_at")
+func (builder *Builder) RegisterSoftDeletes(table string, column ...string) Query {
+	name := softDeletesColumn
+	if len(column) > 0 {
+		name = column[0]
+	}
+
+	if builder.Conn.Scopes == nil {
+		builder.Conn.Scopes = NewScopes()
+	}
+	builder.Conn.Scopes.RegisterSoftDeletes(table, name)
+	return builder
+}
+
+// DetectSoftDeletes Detect the soft delete column "deleted_at" of the tables from the table schema,
+// the table of the query is detected if the tables are not given.
+func (builder *Builder) DetectSoftDeletes(tables ...string) error {
+	if len(tables) == 0 {
+		name, ok := builder.Query.From.Name.(dbal.Name)
+		if !ok || builder.Query.From.Type != "basic" {
+			return fmt.Errorf("the tables of the soft deletes should be given")
+		}
+		tables = []string{name.Name}
+	}
+
+	for _, table := range tables {
+		schema, err := builder.Grammar.GetTable(dbal.NewName(table, builder.Conn.Option.Prefix).Fullname())
+		if err != nil {
+			return err
+		}
+		if schema.HasColumn(softDeletesColumn) {
+			builder.RegisterSoftDeletes(table)
+		}
+	}
+	return nil
+}
+
+// MustDetectSoftDeletes Detect the soft delete column "deleted_at" of the tables from the table schema.
+func (builder *Builder) MustDetectSoftDeletes(tables ...string) Query {
+	err := builder.DetectSoftDeletes(tables...)
+	utils.PanicIF(err)
+	return builder
+}
+
+// WithTrashed Include the soft deleted rows in the results.
+func (builder *Builder) WithTrashed() Query {
+	builder.Query.OnlyTrashed = false
+	return builder.WithoutScope(scopeSoftDeletes)
+}
+
+// OnlyTrashed Select the soft deleted rows only, the table of the query should support soft deletes.
+func (builder *Builder) OnlyTrashed() Query {
+	builder.Query.OnlyTrashed = true
+	return builder
+}
+
+// Restore Restore the soft deleted rows of the query.
+func (builder *Builder) Restore() (int64, error) {
+	column := builder.trashedColumn()
+	if column == "" {
+		return 0, fmt.Errorf("the table of the query does not support soft deletes")
+	}
+
+	qb := builder.clone()
+	qb.Query.OnlyTrashed = true
+	return qb.Update(xun.R{column: nil})
+}
+
+// MustRestore Restore the soft deleted rows of the query.
+func (builder *Builder) MustRestore() int64 {
+	affected, err := builder.Restore()
+	utils.PanicIF(err)
+	return affected
+}
+
+// ForceDelete Delete the records from the database, even if the table supports soft deletes.
+// The trashed rows are deleted as well, unless the query selects the trashed rows only.
+func (builder *Builder) ForceDelete() (int64, error) {
+	if err := builder.checkReturning("ForceDelete"); err != nil {
+		return 0, err
+	}
+	qb := builder.clone()
+	if !qb.Query.OnlyTrashed {
+		qb.WithTrashed()
+	}
+	return qb.delete()
+}
+
+// MustForceDelete Delete the records from the database, even if the table supports soft deletes.
+func (builder *Builder) MustForceDelete() int64 {
+	affected, err := builder.ForceDelete()
+	utils.PanicIF(err)
+	return affected
+}
+
+// trashedColumn Get the soft delete column of the table of the query, returns "" if the table does not support soft deletes.
+func (builder *Builder) trashedColumn() string {
+	name, ok := builder.Query.From.Name.(dbal.Name)
+	if !ok || builder.Query.From.Type != "basic" {
+		return ""
+	}
+	return builder.Conn.Scopes.SoftDeletesColumn(name.Name)
+}
+
+// trashedValues Get the values of the soft delete update, the deletion time is given by the clock of the timestamps if the builder uses the timestamps.
+func (builder *Builder) trashedValues(column string) xun.R {
+	timestamps := builder.timestamps()
+	if timestamps == nil {
+		return xun.R{column: time.Now()}
+	}
+	return xun.R{column: timestamps.value(builder.timestampTypes()[column], timestamps.clock())}
+}
@@ -0,0 +1,136 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestSoftDeleteSQL(t *testing.T) {
+	qb := getTestBuilder()
+	defer removeTestSoftDeletes(qb)
+	qb.RegisterSoftDeletes("table_test_softdelete")
+
+	qb.Table("table_test_softdelete").Where("vote", ">", 1)
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_softdelete" where "vote" > $1 and ("table_test_softdelete"."deleted_at" is null)`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_softdelete` where `vote` > ? and (`table_test_softdelete`.`deleted_at` is null)", sql, "the query sql not equal")
+	}
+
+	qb.Table("table_test_softdelete").Where("vote", ">", 1).OnlyTrashed()
+	sql = qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_softdelete" where "vote" > $1 and ("table_test_softdelete"."deleted_at" is not null)`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_softdelete` where `vote` > ? and (`table_test_softdelete`.`deleted_at` is not null)", sql, "the query sql not equal")
+	}
+
+	qb.Table("table_test_softdelete").Where("vote", ">", 1).WithTrashed()
+	sql = qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_softdelete" where "vote" > $1`, sql, "the query sql not equal")
+	} else {
+		assert.Equal(t, "select * from `table_test_softdelete` where `vote` > ?", sql, "the query sql not equal")
+	}
+}
+
+func TestSoftDeleteOnlyTrashedNotSupported(t *testing.T) {
+	qb := getTestBuilder()
+	assert.PanicsWithError(t, "the table table_test_softdelete_none does not support soft deletes", func() {
+		qb.Table("table_test_softdelete_none").OnlyTrashed().ToSQL()
+	})
+
+	_, err := qb.Table("table_test_softdelete_none").Restore()
+	assert.Error(t, err)
+}
+
+func TestSoftDeleteDeleteRestore(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	defer removeTestSoftDeletes(qb)
+	qb.Table("table_test_softdelete").MustDetectSoftDeletes()
+
+	affected := qb.Table("table_test_softdelete").Where("name", "Ben").MustDelete()
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(2), qb.Table("table_test_softdelete").MustCount())
+	assert.Equal(t, int64(3), qb.Table("table_test_softdelete").WithTrashed().MustCount())
+	assert.False(t, qb.Table("table_test_softdelete").Where("name", "Ben").MustExists())
+
+	rows := qb.Table("table_test_softdelete").OnlyTrashed().MustGet()
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Ben", rows[0].Get("name"))
+	assert.NotNil(t, rows[0].Get("deleted_at"))
+
+	paginator := qb.Table("table_test_softdelete").OrderBy("id").MustPaginate(10, 1)
+	assert.Equal(t, 2, paginator.Total)
+
+	affected = qb.Table("table_test_softdelete").MustRestore()
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(3), qb.Table("table_test_softdelete").MustCount())
+
+	affected = qb.Table("table_test_softdelete").Where("name", "Lee").MustForceDelete()
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(2), qb.Table("table_test_softdelete").WithTrashed().MustCount())
+
+	// The trashed rows are force deleted as well
+	qb.Table("table_test_softdelete").Where("name", "Ben").MustDelete()
+	id := qb.Table("table_test_softdelete").WithTrashed().Where("name", "Ben").MustValue("id")
+	affected = qb.Table("table_test_softdelete").Where("id", id).MustForceDelete()
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(1), qb.Table("table_test_softdelete").WithTrashed().MustCount())
+
+	qb.Table("table_test_softdelete").MustDelete()
+	affected = qb.Table("table_test_softdelete").OnlyTrashed().MustForceDelete()
+	assert.Equal(t, int64(1), affected)
+	assert.Equal(t, int64(0), qb.Table("table_test_softdelete").WithTrashed().MustCount())
+}
+
+func TestSoftDeleteTimestampsClock(t *testing.T) {
+	NewTableForSoftDeleteTest()
+	qb := getTestBuilder()
+	defer removeTestSoftDeletes(qb)
+	qb.Table("table_test_softdelete").MustDetectSoftDeletes()
+
+	shanghai := time.FixedZone("CST", 8*3600)
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tqb := getTestBuilder().New().UseTimestamps(&Timestamps{Location: shanghai, Now: func() time.Time { return now }})
+	affected := tqb.Table("table_test_softdelete").Where("name", "Ben").MustDelete()
+	assert.Equal(t, int64(1), affected)
+
+	row := qb.Table("table_test_softdelete").OnlyTrashed().MustFirst()
+	assert.Equal(t, "2021-03-04 13:06:07", timestampString(row.Get("deleted_at")), "the deletion time should be given by the clock of the timestamps")
+}
+
+func removeTestSoftDeletes(qb Query) {
+	scopes := qb.Builder().Conn.Scopes
+	scopes.Remove("table_test_softdelete", scopeSoftDeletes)
+	scopes.mutex.Lock()
+	delete(scopes.softDeletes, "table_test_softdelete")
+	scopes.mutex.Unlock()
+}
+
+// NewTableForSoftDeleteTest create the testing table of the soft deletes
+func NewTableForSoftDeleteTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_softdelete")
+	builder.MustCreateTable("table_test_softdelete", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name", 32)
+		table.Integer("vote")
+		table.SoftDeletes()
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_softdelete").MustInsert([]xun.R{
+		{"name": "John", "vote": 3},
+		{"name": "Ben", "vote": 5},
+		{"name": "Lee", "vote": 7},
+	})
+}
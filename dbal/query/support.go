@@ -2,9 +2,12 @@ package query
 
 import (
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"unsafe"
 
 	"github.com/yaoapp/xun"
@@ -47,25 +50,141 @@ func (builder *Builder) prepareWhereArgs(args ...interface{}) (string, interface
 	return operator, value, boolean, offset
 }
 
-// prepareInsertValues prepare the insert values
-func (builder *Builder) prepareInsertValues(v interface{}, columns ...interface{}) ([]interface{}, [][]interface{}) {
+// prepareInsertValues prepare the insert values, the rows are grouped by their columns.
+// The rows of each group are inserted with one statement, so the columns omitted by the rows get the defaults instead of null.
+func (builder *Builder) prepareInsertValues(v interface{}, columns ...interface{}) ([]insertGroup, error) {
 
 	if _, ok := v.([][]interface{}); len(columns) > 0 && ok {
 		columns = builder.prepareColumns(columns...)
-		return columns, v.([][]interface{})
+		columns, values := builder.touchInsert(columns, v.([][]interface{}))
+		return []insertGroup{{columns: columns, values: values}}, nil
 	}
 
-	values := xun.MakeRows(v)
-	columns = values[0].Keys()
-	insertValues := [][]interface{}{}
-	for _, row := range values {
-		insertValue := []interface{}{}
+	rows := xun.MakeRows(v)
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the rows of the insert should not be empty")
+	}
+
+	groups := []insertGroup{}
+	positions := map[string]int{}
+	for _, row := range rows {
+		names := row.KeysString()
+		sort.Strings(names)
+		key := strings.Join(names, ",")
+		i, has := positions[key]
+		if !has {
+			i = len(groups)
+			positions[key] = i
+			groups = append(groups, insertGroup{columns: row.Keys(), values: [][]interface{}{}})
+		}
+
+		value := []interface{}{}
+		for _, column := range groups[i].columns {
+			value = append(value, row[column.(string)])
+		}
+		groups[i].values = append(groups[i].values, value)
+	}
+
+	for i := range groups {
+		groups[i].columns, groups[i].values = builder.touchInsert(groups[i].columns, groups[i].values)
+	}
+	return groups, nil
+}
+
+// insertGroups Execute the statement of each group of the insert rows, the statements are executed within a transaction if there are more than one group.
+func (builder *Builder) insertGroups(groups []insertGroup, exec func(qb *Builder, group insertGroup) (int64, error)) (int64, error) {
+	if len(groups) == 1 {
+		return exec(builder, groups[0])
+	}
+
+	var affected int64 = 0
+	err := builder.Transaction(func(qb Query) error {
+		for _, group := range groups {
+			res, err := exec(qb.Builder(), group)
+			if err != nil {
+				return err
+			}
+			affected = affected + res
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// prepareUpdateValues parepare the values of the update, the auto columns of the struct are never updated.
+func (builder *Builder) prepareUpdateValues(v interface{}) map[string]interface{} {
+	values := xun.MakeR(v).ToMap()
+	reflectValue := reflect.Indirect(reflect.ValueOf(v))
+	if reflectValue.Kind() != reflect.Struct {
+		return values
+	}
+
+	for i := 0; i < reflectValue.NumField(); i++ {
+		if tag, has := xun.GetDBTag(reflectValue.Type().Field(i)); has && tag.Auto {
+			delete(values, tag.Name)
+		}
+	}
+	return values
+}
+
+// prepareUpsertValues parepare the update values of the upsert, the struct is converted to the column values.
+func (builder *Builder) prepareUpsertValues(update interface{}) interface{} {
+	if update != nil && reflect.Indirect(reflect.ValueOf(update)).Kind() == reflect.Struct {
+		return builder.prepareUpdateValues(update)
+	}
+	return update
+}
+
+// prepareBatchValues parepare the columns and the values of the batch update, the key column is the first one.
+// All of the rows should have the same columns with the key column.
+func (builder *Builder) prepareBatchValues(v interface{}, key string) ([]interface{}, [][]interface{}, error) {
+	rows := xun.MakeRows(v)
+	if len(rows) == 0 {
+		return nil, nil, nil
+	}
+
+	names := []string{}
+	for _, name := range rows[0].KeysString() {
+		if name != key {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return nil, nil, fmt.Errorf("the rows of the batch update should have the columns to update")
+	}
+
+	columns := []interface{}{key}
+	for _, name := range names {
+		columns = append(columns, name)
+	}
+
+	values := [][]interface{}{}
+	for i, row := range rows {
+		if len(row) != len(columns) {
+			return nil, nil, fmt.Errorf("the row %d of the batch update should have the same columns with the first one", i)
+		}
+
+		value := []interface{}{}
 		for _, column := range columns {
-			insertValue = append(insertValue, row.Get(column))
+			name := column.(string)
+			if !row.Has(name) {
+				return nil, nil, fmt.Errorf("the row %d of the batch update should have the column %s", i, name)
+			}
+			value = append(value, row.Get(name))
+		}
+
+		if utils.IsNil(value[0]) {
+			return nil, nil, fmt.Errorf("the key %s of the row %d of the batch update should not be null", key, i)
 		}
-		insertValues = append(insertValues, insertValue)
+		values = append(values, value)
 	}
-	return columns, insertValues
+	return columns, values, nil
 }
 
 // prepareColumns parepare the select columns
@@ -260,13 +379,10 @@ func (builder *Builder) mapScan(rows *sql.Rows) ([]xun.R, error) {
 	values := builder.makeMapValues(len(columns))
 
 	for rows.Next() {
-		if err := rows.Scan(values...); err != nil {
+		dest, err := builder.mapScanRow(rows, columns, values)
+		if err != nil {
 			return nil, err
 		}
-		dest := xun.R{}
-		for i, column := range columns {
-			dest[column] = builder.getValue(values[i])
-		}
 		res = append(res, dest)
 	}
 
@@ -277,8 +393,20 @@ func (builder *Builder) mapScan(rows *sql.Rows) ([]xun.R, error) {
 	return res, nil
 }
 
-// structScan scan the result from sql.Rows
-func (builder *Builder) structScan(rows *sql.Rows, v interface{}) error {
+// mapScanRow scan the current row from sql.Rows
+func (builder *Builder) mapScanRow(rows *sql.Rows, columns []string, values []interface{}) (xun.R, error) {
+	if err := rows.Scan(values...); err != nil {
+		return nil, err
+	}
+	dest := xun.R{}
+	for i, column := range columns {
+		dest[column] = builder.getValue(values[i])
+	}
+	return dest, nil
+}
+
+// structScan scan the result from sql.Rows, the columns out of the struct are scanned into the extra destinations.
+func (builder *Builder) structScan(rows *sql.Rows, v interface{}, extras map[string]interface{}) error {
 	defer rows.Close()
 
 	columns, err := rows.Columns()
@@ -305,7 +433,7 @@ func (builder *Builder) structScan(rows *sql.Rows, v interface{}) error {
 	for rows.Next() {
 		dest := reflect.New(structType)
 		if vStruct {
-			values, err := builder.makeStructValues(dest, fieldMap, columns)
+			values, err := builder.makeStructValues(dest, fieldMap, columns, extras)
 			if err != nil {
 				return err
 			}
@@ -313,6 +441,10 @@ func (builder *Builder) structScan(rows *sql.Rows, v interface{}) error {
 				return err
 			}
 
+		} else if vSlice {
+			if err := rows.Scan(dest.Interface()); err != nil {
+				return err
+			}
 		} else {
 			if err := rows.Scan(v); err != nil {
 				return err
@@ -355,14 +487,30 @@ func (builder *Builder) getStructType(v interface{}) (reflect.Type, bool, error)
 	return structType, structType.Kind() == reflect.Struct, nil
 }
 
+// fieldMaps the cached field maps of the struct types, the keys are the reflect.Type of the structs
+var fieldMaps sync.Map
+
+// getFieldMap Get the fields of the struct type by the column names, the field map is built once per type and cached.
 func (builder *Builder) getFieldMap(structType reflect.Type) (map[string]reflect.StructField, error) {
+	if fieldMap, has := fieldMaps.Load(structType); has {
+		return fieldMap.(map[string]reflect.StructField), nil
+	}
+
 	fieldMap := map[string]reflect.StructField{}
 	for i := 0; i < structType.NumField(); i++ {
+		if dbTag, has := xun.GetDBTag(structType.Field(i)); has {
+			if !dbTag.Skip {
+				fieldMap[dbTag.Name] = structType.Field(i)
+			}
+			continue
+		}
+
 		tag := xun.GetTagName(structType.Field(i), "json")
 		if tag != "" && tag != "-" {
 			fieldMap[tag] = structType.Field(i)
 		}
 	}
+	fieldMaps.Store(structType, fieldMap)
 	return fieldMap, nil
 }
 
@@ -387,18 +535,46 @@ func (builder *Builder) makeMapValues(length int) []interface{} {
 	return values
 }
 
-func (builder *Builder) makeStructValues(dest reflect.Value, fieldMap map[string]reflect.StructField, columns []string) ([]interface{}, error) {
+func (builder *Builder) makeStructValues(dest reflect.Value, fieldMap map[string]reflect.StructField, columns []string, extras map[string]interface{}) ([]interface{}, error) {
 
 	values := []interface{}{}
 	for _, column := range columns {
 		field, has := fieldMap[column]
+		if extra, isExtra := extras[column]; !has && isExtra {
+			values = append(values, extra)
+			continue
+		}
 		if !has {
 			return nil, fmt.Errorf("scan: expected `%s` destination arguments in Scan", column)
 		}
 		value := dest.Elem().FieldByName(field.Name)
 		vPtr := reflect.NewAt(value.Type(), unsafe.Pointer(value.UnsafeAddr()))
+		if tag, has := xun.GetDBTag(field); has && tag.JSON {
+			values = append(values, jsonScanner{dest: vPtr.Interface()})
+			continue
+		}
 		values = append(values, vPtr.Interface())
 	}
 
 	return values, nil
 }
+
+// jsonScanner scan the JSON text of the column into the field of the "json" db tag
+type jsonScanner struct {
+	dest interface{}
+}
+
+// Scan unmarshal the JSON text into the field, the field is reset to the zero value if the column is null
+func (scanner jsonScanner) Scan(src interface{}) error {
+	switch data := src.(type) {
+	case nil:
+		value := reflect.ValueOf(scanner.dest).Elem()
+		value.Set(reflect.Zero(value.Type()))
+		return nil
+	case []byte:
+		return json.Unmarshal(data, scanner.dest)
+	case string:
+		return json.Unmarshal([]byte(data), scanner.dest)
+	}
+	return fmt.Errorf("scan: the %T value could not be unmarshalled into the json field", src)
+}
@@ -0,0 +1,204 @@
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+)
+
+// UseTimestamps Fill the created_at and updated_at columns of the table on write, the columns which are not found in the table are skipped.
+// The columns given by the caller are not changed. The timestamps of the connection are used if the builder does not use the timestamps.
+// UseTimestamps()
+// UseTimestamps(&Timestamps{Clock: "database"})
+// UseTimestamps(&Timestamps{Location: time.Local})
+func (builder *Builder) UseTimestamps(option ...*Timestamps) Query {
+	builder.Timestamps = &Timestamps{}
+	if len(option) > 0 && option[0] != nil {
+		builder.Timestamps = option[0]
+	}
+	return builder
+}
+
+// timestamps Get the automatic timestamps of the builder, returns nil if the timestamps are not used
+func (builder *Builder) timestamps() *Timestamps {
+	if builder.Timestamps != nil {
+		return builder.Timestamps
+	}
+	return builder.Conn.Timestamps
+}
+
+// touchInsert Add the creation and modification time to the insert values, the given columns are not changed.
+func (builder *Builder) touchInsert(columns []interface{}, values [][]interface{}) ([]interface{}, [][]interface{}) {
+	timestamps := builder.timestamps()
+	if timestamps == nil {
+		return columns, values
+	}
+
+	types := builder.timestampTypes()
+	current := timestamps.clock()
+	added := []interface{}{}
+	touched := append([]interface{}{}, columns...)
+	for _, name := range []string{timestamps.createdAt(), timestamps.updatedAt()} {
+		if typ, has := types[name]; has && !hasColumn(columns, name) {
+			touched = append(touched, name)
+			added = append(added, timestamps.value(typ, current))
+		}
+	}
+	if len(added) == 0 {
+		return columns, values
+	}
+
+	rows := [][]interface{}{}
+	for _, row := range values {
+		rows = append(rows, append(append([]interface{}{}, row...), added...))
+	}
+	return touched, rows
+}
+
+// touchUpdate Add the modification time to the update values, the given columns are not changed.
+func (builder *Builder) touchUpdate(values map[string]interface{}) map[string]interface{} {
+	timestamps := builder.timestamps()
+	if timestamps == nil {
+		return values
+	}
+
+	name := timestamps.updatedAt()
+	typ, has := builder.timestampTypes()[name]
+	if !has {
+		return values
+	}
+	if _, has := values[name]; has {
+		return values
+	}
+
+	touched := map[string]interface{}{}
+	for key, value := range values {
+		touched[key] = value
+	}
+	touched[name] = timestamps.value(typ, timestamps.clock())
+	return touched
+}
+
+// touchUpsert Add the modification time to the update part of the upsert, the creation time is kept when the rows are updated.
+// The update columns get the inserted values, the update values get the current time.
+func (builder *Builder) touchUpsert(update interface{}) interface{} {
+	timestamps := builder.timestamps()
+	if timestamps == nil || update == nil {
+		return update
+	}
+
+	name := timestamps.updatedAt()
+	if _, has := builder.timestampTypes()[name]; !has {
+		return update
+	}
+
+	switch reflect.ValueOf(update).Kind() {
+	case reflect.Array, reflect.Slice:
+		columns := []interface{}{}
+		reflectValue := reflect.ValueOf(update)
+		for i := 0; i < reflectValue.Len(); i++ {
+			columns = append(columns, reflectValue.Index(i).Interface())
+		}
+		if hasColumn(columns, name) {
+			return update
+		}
+		return append(columns, name)
+
+	case reflect.Map:
+		return builder.touchUpdate(xun.MakeR(update).ToMap())
+	}
+	return update
+}
+
+// timestampTypes Get the types of the columns of the table which the query is targeting, the timestamp columns are looked up in it.
+func (builder *Builder) timestampTypes() map[string]string {
+	name, ok := builder.Query.From.Name.(dbal.Name)
+	if !ok || builder.Query.From.Type != "basic" {
+		return map[string]string{}
+	}
+	return builder.timestamps().columnsOf(builder, name.Fullname())
+}
+
+// columnsOf Get the types of the columns of the table, the table schema is loaded once and cached. Nothing is cached if the table could not be loaded.
+func (timestamps *Timestamps) columnsOf(builder *Builder, table string) map[string]string {
+	timestamps.mutex.RLock()
+	columns, has := timestamps.columns[table]
+	timestamps.mutex.RUnlock()
+	if has {
+		return columns
+	}
+
+	schema, err := builder.Grammar.GetTable(table)
+	if err != nil {
+		return map[string]string{}
+	}
+
+	columns = map[string]string{}
+	for _, column := range schema.Columns {
+		columns[column.Name] = column.Type
+	}
+
+	timestamps.mutex.Lock()
+	defer timestamps.mutex.Unlock()
+	if timestamps.columns == nil {
+		timestamps.columns = map[string]map[string]string{}
+	}
+	timestamps.columns[table] = columns
+	return columns
+}
+
+// clock Get the current time of the application clock in the location
+func (timestamps *Timestamps) clock() time.Time {
+	now := time.Now
+	if timestamps.Now != nil {
+		now = timestamps.Now
+	}
+
+	location := time.UTC
+	if timestamps.Location != nil {
+		location = timestamps.Location
+	}
+	return now().In(location)
+}
+
+// value Get the timestamp value of the column type. The database clock is the CURRENT_TIMESTAMP expression. The application clock
+// keeps the time zone for the columns with time zone, the columns without time zone get the wall clock time of the location.
+func (timestamps *Timestamps) value(typ string, current time.Time) interface{} {
+	if timestamps.Clock == "database" {
+		return dbal.Raw("CURRENT_TIMESTAMP")
+	}
+	if strings.HasSuffix(typ, "Tz") {
+		return current
+	}
+	return current.Format("2006-01-02 15:04:05.000000")
+}
+
+// createdAt Get the column of the creation time
+func (timestamps *Timestamps) createdAt() string {
+	if timestamps.CreatedAt == "" {
+		return "created_at"
+	}
+	return timestamps.CreatedAt
+}
+
+// updatedAt Get the column of the modification time
+func (timestamps *Timestamps) updatedAt() string {
+	if timestamps.UpdatedAt == "" {
+		return "updated_at"
+	}
+	return timestamps.UpdatedAt
+}
+
+// hasColumn Determine if the column is in the columns
+func hasColumn(columns []interface{}, name string) bool {
+	for _, column := range columns {
+		if fmt.Sprintf("%v", column) == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTimestampsInsertUpdate(t *testing.T) {
+	NewTableForTimestampsTest()
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	qb := getTestBuilder().New().UseTimestamps(&Timestamps{Now: func() time.Time { return now }})
+
+	qb.Table("table_test_timestamps").MustInsert(xun.R{"email": "john@yao.run", "vote": 1})
+	id := qb.Table("table_test_timestamps").MustInsertGetID(xun.R{"email": "ben@yao.run", "vote": 2, "created_at": "2020-01-01 00:00:00"})
+
+	row := qb.Table("table_test_timestamps").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, "2021-03-04 05:06:07", timestampString(row.Get("created_at")))
+	assert.Equal(t, "2021-03-04 05:06:07", timestampString(row.Get("updated_at")))
+
+	// The values given by the caller are not changed
+	row = qb.Table("table_test_timestamps").Where("id", id).MustFirst()
+	assert.Equal(t, "2020-01-01 00:00:00", timestampString(row.Get("created_at")))
+	assert.Equal(t, "2021-03-04 05:06:07", timestampString(row.Get("updated_at")))
+
+	now = now.Add(time.Hour)
+	qb.Table("table_test_timestamps").Where("email", "john@yao.run").MustUpdate(xun.R{"vote": 3})
+	row = qb.Table("table_test_timestamps").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, "2021-03-04 05:06:07", timestampString(row.Get("created_at")))
+	assert.Equal(t, "2021-03-04 06:06:07", timestampString(row.Get("updated_at")))
+
+	// The builders without the timestamps are not changed
+	getTestBuilder().Table("table_test_timestamps").Where("email", "john@yao.run").MustUpdate(xun.R{"vote": 4})
+	row = qb.Table("table_test_timestamps").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, "2021-03-04 06:06:07", timestampString(row.Get("updated_at")))
+}
+
+func TestTimestampsUpsert(t *testing.T) {
+	NewTableForTimestampsTest()
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	qb := getTestBuilder().New().UseTimestamps(&Timestamps{Now: func() time.Time { return now }})
+	qb.Table("table_test_timestamps").MustInsert(xun.R{"email": "john@yao.run", "vote": 1})
+
+	now = now.Add(time.Hour)
+	qb.Table("table_test_timestamps").MustUpsert([]xun.R{
+		{"email": "john@yao.run", "vote": 2},
+		{"email": "ben@yao.run", "vote": 3},
+	}, "email", []string{"vote"})
+
+	rows := qb.Table("table_test_timestamps").OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "2021-03-04 05:06:07", timestampString(rows[0].Get("created_at")), "the creation time should be kept on the update path")
+	assert.Equal(t, "2021-03-04 06:06:07", timestampString(rows[0].Get("updated_at")))
+	assert.Equal(t, "2021-03-04 06:06:07", timestampString(rows[1].Get("created_at")))
+	assert.Equal(t, "2021-03-04 06:06:07", timestampString(rows[1].Get("updated_at")))
+}
+
+func TestTimestampsDatabaseClock(t *testing.T) {
+	NewTableForTimestampsTest()
+	qb := getTestBuilder().New().UseTimestamps(&Timestamps{Clock: "database"})
+	qb.Table("table_test_timestamps").MustInsert(xun.R{"email": "john@yao.run", "vote": 1})
+
+	row := qb.Table("table_test_timestamps").Where("email", "john@yao.run").MustFirst()
+	assert.NotNil(t, row.Get("created_at"))
+	assert.NotNil(t, row.Get("updated_at"))
+
+	groups, err := qb.Table("table_test_timestamps").Builder().prepareInsertValues(xun.R{"email": "ben@yao.run"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(groups))
+	assert.Equal(t, []interface{}{"email", "created_at", "updated_at"}, groups[0].columns)
+	assert.Equal(t, dbal.Raw("CURRENT_TIMESTAMP"), groups[0].values[0][1])
+}
+
+func TestTimestampsValue(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	timestamps := &Timestamps{Location: shanghai, Now: func() time.Time { return now }}
+
+	current := timestamps.clock()
+	assert.Equal(t, "2021-03-04 13:06:07.000000", timestamps.value("timestamp", current), "the columns without time zone get the wall clock time of the location")
+	assert.True(t, now.Equal(timestamps.value("timestampTz", current).(time.Time)), "the columns with time zone keep the time zone")
+
+	// The location is UTC if not given
+	timestamps = &Timestamps{Now: func() time.Time { return now.In(shanghai) }}
+	assert.Equal(t, "2021-03-04 05:06:07.000000", timestamps.value("dateTime", timestamps.clock()))
+}
+
+func TestTimestampsTableNotFound(t *testing.T) {
+	defer unit.Catch()
+	getTestSchemaBuilder().DropTableIfExists("table_test_timestamps")
+	now := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	qb := getTestBuilder().New().UseTimestamps(&Timestamps{Now: func() time.Time { return now }})
+	assert.Equal(t, map[string]string{}, qb.Table("table_test_timestamps").Builder().timestampTypes())
+
+	// The columns are loaded again after the table is created
+	NewTableForTimestampsTest()
+	qb.Table("table_test_timestamps").MustInsert(xun.R{"email": "john@yao.run", "vote": 1})
+	row := qb.Table("table_test_timestamps").Where("email", "john@yao.run").MustFirst()
+	assert.Equal(t, "2021-03-04 05:06:07", timestampString(row.Get("created_at")))
+}
+
+// timestampString the timestamp value of the row without the fraction and time zone
+func timestampString(value interface{}) string {
+	text := ""
+	switch v := value.(type) {
+	case time.Time:
+		text = v.Format("2006-01-02 15:04:05")
+	case []byte:
+		text = string(v)
+	default:
+		text = fmt.Sprintf("%v", v)
+	}
+	if len(text) > 19 {
+		text = text[:19]
+	}
+	return text
+}
+
+// NewTableForTimestampsTest create the testing table of the automatic timestamps
+func NewTableForTimestampsTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_timestamps")
+	builder.MustCreateTable("table_test_timestamps", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email", 64).Unique()
+		table.Integer("vote")
+		table.Timestamp("created_at").Null()
+		table.Timestamp("updated_at").Null()
+	})
+}
@@ -0,0 +1,100 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Begin Start a new transaction and return a query builder bound to it. If the builder was already in a transaction, a savepoint will be created.
+func (builder *Builder) Begin() (Query, error) {
+	var err error
+	var tx *dbal.Transaction
+	if builder.Tx == nil {
+		tx, err = dbal.BeginTransactionContext(builder.ctx(), builder.Conn.Write)
+	} else {
+		tx, err = builder.Tx.Begin(builder.Grammar)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	new := builder.clone()
+	new.Tx = tx
+	new.Grammar = builder.Grammar.WithExecutor(dbal.WithContext(builder.Context, tx.Tx))
+	new.Query.UseWriteConnection = true
+	return new, nil
+}
+
+// MustBegin Start a new transaction and return a query builder bound to it. If the builder was already in a transaction, a savepoint will be created.
+func (builder *Builder) MustBegin() Query {
+	qb, err := builder.Begin()
+	utils.PanicIF(err)
+	return qb
+}
+
+// Commit Commit the transaction, the savepoint will be released if the transaction is nested.
+func (builder *Builder) Commit() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the transaction was not started")
+	}
+	return builder.Tx.Commit(builder.Grammar)
+}
+
+// MustCommit Commit the transaction, the savepoint will be released if the transaction is nested.
+func (builder *Builder) MustCommit() {
+	err := builder.Commit()
+	utils.PanicIF(err)
+}
+
+// Rollback Rollback the transaction, rollback to the savepoint if the transaction is nested.
+func (builder *Builder) Rollback() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the transaction was not started")
+	}
+	return builder.Tx.Rollback(builder.Grammar)
+}
+
+// MustRollback Rollback the transaction, rollback to the savepoint if the transaction is nested.
+func (builder *Builder) MustRollback() {
+	err := builder.Rollback()
+	utils.PanicIF(err)
+}
+
+// Transaction Execute the callback within a transaction. The transaction will be rolled back if the callback returns an error or panics, otherwise it will be committed.
+func (builder *Builder) Transaction(callback func(qb Query) error) error {
+	qb, err := builder.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			qb.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = callback(qb)
+	if err != nil {
+		if rerr := qb.Rollback(); rerr != nil {
+			return fmt.Errorf("%s (rollback error: %s)", err, rerr)
+		}
+		return err
+	}
+
+	return qb.Commit()
+}
+
+// MustTransaction Execute the callback within a transaction. The transaction will be rolled back if the callback returns an error or panics, otherwise it will be committed.
+func (builder *Builder) MustTransaction(callback func(qb Query) error) {
+	err := builder.Transaction(callback)
+	utils.PanicIF(err)
+}
+
+// InTransaction Determine if the builder was bound to a transaction.
+func (builder *Builder) InTransaction() bool {
+	return builder.Tx != nil
+}
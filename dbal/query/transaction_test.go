@@ -0,0 +1,108 @@
+package query
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/dbal/schema"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		assert.True(t, tx.InTransaction(), "The builder should be in transaction")
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "john@yao.run", "vote": 10})
+		_, err := tx.New().Table("table_test_transaction").InsertGetID(xun.R{"email": "lee@yao.run", "vote": 5})
+		return err
+	})
+	assert.Nil(t, err)
+	assert.False(t, qb.InTransaction(), "The builder should not be in transaction")
+	assert.Equal(t, int64(2), qb.Table("table_test_transaction").MustCount(), "The records should be committed")
+}
+
+func TestTransactionRollback(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "john@yao.run", "vote": 10})
+		return errors.New("something wrong")
+	})
+	assert.Equal(t, "something wrong", err.Error())
+	assert.Equal(t, int64(0), qb.Table("table_test_transaction").MustCount(), "The records should be rolled back")
+}
+
+func TestTransactionPanic(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	assert.Panics(t, func() {
+		qb.Transaction(func(tx Query) error {
+			tx.Table("table_test_transaction").MustInsert(xun.R{"email": "john@yao.run", "vote": 10})
+			panic("something wrong")
+		})
+	})
+	assert.Equal(t, int64(0), qb.Table("table_test_transaction").MustCount(), "The records should be rolled back")
+}
+
+func TestTransactionNested(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	err := qb.Transaction(func(tx Query) error {
+		tx.Table("table_test_transaction").MustInsert(xun.R{"email": "john@yao.run", "vote": 10})
+		err := tx.Transaction(func(nested Query) error {
+			nested.Table("table_test_transaction").MustInsert(xun.R{"email": "lee@yao.run", "vote": 5})
+			return errors.New("something wrong")
+		})
+		assert.NotNil(t, err)
+
+		tx.MustTransaction(func(nested Query) error {
+			nested.Table("table_test_transaction").MustInsert(xun.R{"email": "ken@yao.run", "vote": 125})
+			return nil
+		})
+		return nil
+	})
+	assert.Nil(t, err)
+
+	rows := qb.Table("table_test_transaction").OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows), "The nested transaction should be rolled back to the savepoint")
+	if len(rows) == 2 {
+		assert.Equal(t, "john@yao.run", rows[0].Get("email"))
+		assert.Equal(t, "ken@yao.run", rows[1].Get("email"))
+	}
+}
+
+func TestTransactionBegin(t *testing.T) {
+	NewTableForTransactionTest()
+	qb := getTestBuilder()
+	tx := qb.MustBegin()
+	tx.Table("table_test_transaction").MustInsert(xun.R{"email": "john@yao.run", "vote": 10})
+	tx.Clone().Table("table_test_transaction").MustInsert(xun.R{"email": "lee@yao.run", "vote": 5})
+	tx.MustRollback()
+	assert.Equal(t, int64(0), qb.Table("table_test_transaction").MustCount(), "The records should be rolled back")
+
+	tx = qb.MustBegin()
+	tx.Table("table_test_transaction").MustInsert(xun.R{"email": "john@yao.run", "vote": 10})
+	tx.MustCommit()
+	assert.Equal(t, int64(1), qb.Table("table_test_transaction").MustCount(), "The records should be committed")
+}
+
+func TestTransactionNotStarted(t *testing.T) {
+	qb := getTestBuilder()
+	assert.NotNil(t, qb.New().Commit())
+	assert.NotNil(t, qb.New().Rollback())
+}
+
+// clean the test data
+func NewTableForTransactionTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	builder.MustCreateTable("table_test_transaction", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("email").Unique()
+		table.Integer("vote")
+	})
+}
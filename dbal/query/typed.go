@@ -0,0 +1,107 @@
+package query
+
+import (
+	"github.com/yaoapp/xun"
+	"github.com/yaoapp/xun/utils"
+)
+
+// GetAs Execute the query as a "select" statement and scan the rows into a slice of T.
+// T is a struct with the json tags as the column names, or a single column value type.
+// users, err := GetAs[User](qb.Table("users"))
+// ids, err := GetAs[int64](qb.Table("users").Select("id"))
+func GetAs[T any](qb Query) ([]T, error) {
+	rows := []T{}
+	_, err := qb.Get(&rows)
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// MustGetAs Execute the query as a "select" statement and scan the rows into a slice of T.
+func MustGetAs[T any](qb Query) []T {
+	rows, err := GetAs[T](qb)
+	utils.PanicIF(err)
+	return rows
+}
+
+// FirstAs Execute the query and scan the first row into a value of T, returns nil if there is no row.
+func FirstAs[T any](qb Query) (*T, error) {
+	rows, err := GetAs[T](qb.Take(1))
+	if err != nil || len(rows) == 0 {
+		return nil, err
+	}
+	return &rows[0], nil
+}
+
+// MustFirstAs Execute the query and scan the first row into a value of T, returns nil if there is no row.
+func MustFirstAs[T any](qb Query) *T {
+	row, err := FirstAs[T](qb)
+	utils.PanicIF(err)
+	return row
+}
+
+// PaginateAs Paginate the query and scan the rows of the page into a slice of T, the items of the paginator are the values of T.
+func PaginateAs[T any](qb Query, pageSize int, page int) (xun.P, []T, error) {
+	rows := []T{}
+	paginator, err := qb.Paginate(pageSize, page, &rows)
+	if err != nil {
+		return paginator, nil, err
+	}
+	return paginator, rows, nil
+}
+
+// MustPaginateAs Paginate the query and scan the rows of the page into a slice of T.
+func MustPaginateAs[T any](qb Query, pageSize int, page int) (xun.P, []T) {
+	paginator, rows, err := PaginateAs[T](qb, pageSize, page)
+	utils.PanicIF(err)
+	return paginator, rows
+}
+
+// ChunkAs Retrieves a small chunk of results at a time and feeds each chunk as a slice of T into a closure for processing.
+func ChunkAs[T any](qb Query, size int, callback func(items []T, page int) error) error {
+	rows := []T{}
+	return qb.Chunk(size, func(items []interface{}, page int) error {
+		chunk := make([]T, 0, len(items))
+		for _, item := range items {
+			chunk = append(chunk, item.(T))
+		}
+		return callback(chunk, page)
+	}, &rows)
+}
+
+// MustChunkAs Retrieves a small chunk of results at a time and feeds each chunk as a slice of T into a closure for processing.
+func MustChunkAs[T any](qb Query, size int, callback func(items []T, page int) error) {
+	err := ChunkAs(qb, size, callback)
+	utils.PanicIF(err)
+}
+
+// CursorAs Execute the query as a "select" statement and return a cursor which scans the rows into the values of T one at a time.
+func CursorAs[T any](qb Query) (*TypedCursor[T], error) {
+	cursor, err := qb.Cursor()
+	if err != nil {
+		return nil, err
+	}
+	return &TypedCursor[T]{Cursor: cursor}, nil
+}
+
+// MustCursorAs Execute the query as a "select" statement and return a cursor which scans the rows into the values of T one at a time.
+func MustCursorAs[T any](qb Query) *TypedCursor[T] {
+	cursor, err := CursorAs[T](qb)
+	utils.PanicIF(err)
+	return cursor
+}
+
+// Row Scan the current row into a value of T
+func (cursor *TypedCursor[T]) Row() (T, error) {
+	var row T
+	err := cursor.Scan(&row)
+	return row, err
+}
+
+// MustRow Scan the current row into a value of T
+func (cursor *TypedCursor[T]) MustRow() T {
+	row, err := cursor.Row()
+	utils.PanicIF(err)
+	return row
+}
@@ -0,0 +1,96 @@
+package query
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type typedTestRow struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Vote   int    `json:"vote"`
+}
+
+func TestTypedGetAs(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	rows := MustGetAs[typedTestRow](qb.Table("table_test_scope").OrderBy("id"))
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, "John", rows[0].Name)
+	assert.Equal(t, 7, rows[2].Vote)
+
+	names := MustGetAs[string](qb.Table("table_test_scope").Select("name").Where("status", "active").OrderBy("id"))
+	assert.Equal(t, []string{"John", "Lee"}, names)
+
+	rows = MustGetAs[typedTestRow](qb.Table("table_test_scope").Where("id", 99))
+	assert.Equal(t, []typedTestRow{}, rows)
+}
+
+func TestTypedFirstAs(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	row := MustFirstAs[typedTestRow](qb.Table("table_test_scope").Where("name", "Ben"))
+	assert.NotNil(t, row)
+	assert.Equal(t, 5, row.Vote)
+
+	row = MustFirstAs[typedTestRow](qb.Table("table_test_scope").Where("name", "Nobody"))
+	assert.Nil(t, row)
+
+	vote, err := FirstAs[int64](qb.Table("table_test_scope").Select("vote").OrderByDesc("vote"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(7), *vote)
+}
+
+func TestTypedPaginateAs(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	paginator, rows := MustPaginateAs[typedTestRow](qb.Table("table_test_scope").OrderBy("id"), 2, 2)
+	assert.Equal(t, 3, paginator.Total)
+	assert.Equal(t, 2, paginator.LastPage)
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Lee", rows[0].Name)
+	assert.Equal(t, rows[0], paginator.Items[0])
+}
+
+func TestTypedChunkAs(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	names := []string{}
+	pages := []int{}
+	MustChunkAs(qb.Table("table_test_scope").OrderBy("id"), 2, func(items []typedTestRow, page int) error {
+		for _, item := range items {
+			names = append(names, item.Name)
+		}
+		pages = append(pages, page)
+		return nil
+	})
+	assert.Equal(t, []string{"John", "Ben", "Lee"}, names)
+	assert.Equal(t, []int{1, 2}, pages)
+}
+
+func TestTypedCursorAs(t *testing.T) {
+	NewTableForScopeTest()
+	qb := getTestBuilder()
+	cursor := MustCursorAs[typedTestRow](qb.Table("table_test_scope").OrderBy("id"))
+	defer cursor.Close()
+
+	votes := 0
+	for cursor.Next() {
+		votes = votes + cursor.MustRow().Vote
+	}
+	assert.Nil(t, cursor.Err())
+	assert.Equal(t, 15, votes)
+}
+
+func TestTypedFieldMapCache(t *testing.T) {
+	qb := getTestBuilder().Builder()
+	first, err := qb.getFieldMap(reflect.TypeOf(typedTestRow{}))
+	assert.Nil(t, err)
+	second, err := qb.getFieldMap(reflect.TypeOf(typedTestRow{}))
+	assert.Nil(t, err)
+	assert.Equal(t, reflect.ValueOf(first).Pointer(), reflect.ValueOf(second).Pointer())
+	assert.Equal(t, "Name", first["name"].Name)
+}
@@ -1,18 +1,26 @@
 package query
 
 import (
+	"context"
+	"database/sql"
+	"sync"
+	"time"
+
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 )
 
 // Builder the dbal query builder
 type Builder struct {
-	Conn     *Connection
-	Query    *dbal.Query
-	Mode     string
-	Database string
-	Schema   string
-	Grammar  dbal.Grammar
+	Conn       *Connection
+	Query      *dbal.Query
+	Mode       string
+	Database   string
+	Schema     string
+	Grammar    dbal.Grammar
+	Tx         *dbal.Transaction
+	Context    context.Context
+	Timestamps *Timestamps // The automatic timestamps of the builder, the timestamps of the connection are used if nil
 }
 
 // Connection DB Connection
@@ -22,4 +30,89 @@ type Connection struct {
 	Read        *sqlx.DB
 	ReadConfig  *dbal.Config
 	Option      *dbal.Option
+	Cache       dbal.Cache  // The cache store of the query results, the DefaultCache is used if nil
+	Scopes      *Scopes     // The global query scopes registered per table, shared by the builders of the connection
+	Timestamps  *Timestamps // The automatic timestamps of the writes, the timestamps are not filled if nil
+}
+
+// Timestamps the automatic timestamps, the creation and modification time columns of the tables are filled on write if exist
+type Timestamps struct {
+	CreatedAt string           // The column of the creation time, "created_at" if empty
+	UpdatedAt string           // The column of the modification time, "updated_at" if empty
+	Clock     string           // The clock of the timestamps, "app" (default) the application clock, "database" the CURRENT_TIMESTAMP of the database
+	Location  *time.Location   // The time zone of the application clock for the columns without time zone, UTC if nil
+	Now       func() time.Time // The application clock, time.Now if nil
+	columns   map[string]map[string]string
+	mutex     sync.RWMutex
+}
+
+// Scopes the registry of the global query scopes
+type Scopes struct {
+	scopes      []Scope
+	softDeletes map[string]string // The soft delete columns of the tables
+	mutex       sync.RWMutex
+}
+
+// Scope the global query scope, the where clauses added by the callback are applied to the queries of the table
+type Scope struct {
+	Table string
+	Name  string
+	Apply func(qb Query)
+}
+
+// Cursor the streaming cursor over the query results, only the current row is kept in memory
+type Cursor struct {
+	builder *Builder
+	rows    *sql.Rows
+	columns []string
+	values  []interface{}
+	err     error
+}
+
+// TypedCursor the streaming cursor which scans the current row into a value of T
+type TypedCursor[T any] struct {
+	*Cursor
+}
+
+// DSL the declarative JSON query, the selects, filters, sorting, grouping and pagination applied to a builder
+type DSL struct {
+	Table    string     `json:"table"`
+	Select   []string   `json:"select,omitempty"`
+	Wheres   []DSLWhere `json:"wheres,omitempty"`
+	Orders   []DSLOrder `json:"orders,omitempty"`
+	Groups   []string   `json:"groups,omitempty"`
+	Page     int        `json:"page,omitempty"`
+	PageSize int        `json:"pagesize,omitempty"`
+	Limit    int        `json:"limit,omitempty"`
+	Offset   int        `json:"offset,omitempty"`
+	policy   Policy
+}
+
+// DSLWhere the filter of the declarative JSON query, the filter with the wheres is a nested group of the filters
+type DSLWhere struct {
+	Column  string      `json:"column,omitempty"`
+	Op      string      `json:"op,omitempty"`      // The comparison operators, in, not in, between, not between, null and not null. "=" (default)
+	Value   interface{} `json:"value,omitempty"`   // The value, the values of in and not in, the min and max values of between and not between
+	Boolean string      `json:"boolean,omitempty"` // and (default), or
+	Wheres  []DSLWhere  `json:"wheres,omitempty"`
+}
+
+// DSLOrder the sorting of the declarative JSON query
+type DSLOrder struct {
+	Column    string `json:"column"`
+	Direction string `json:"direction,omitempty"` // asc (default), desc
+}
+
+// Policy the whitelist of the declarative JSON query
+type Policy struct {
+	Tables      []string // The tables allowed to query
+	Columns     []string // The columns allowed in the selects, filters, sorting and grouping, the selects default to these columns
+	Operators   []string // The operators allowed in the filters, all of the operators of the grammar, in, between and null if empty
+	MaxPageSize int      // The maximum page size and limit, unlimited if zero
+}
+
+// insertGroup the rows of the insert which have the same columns
+type insertGroup struct {
+	columns []interface{}
+	values  [][]interface{}
 }
@@ -24,6 +24,7 @@ func (builder *Builder) Union(query interface{}, all ...bool) Query {
 	}
 
 	if qb != nil {
+		qb = qb.scoped()
 		builder.Query.Unions = append(builder.Query.Unions, dbal.Union{
 			Query: qb.Query,
 			All:   isUnionAll,
@@ -2,6 +2,7 @@ package query
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
@@ -11,18 +12,28 @@ import (
 
 // Update Update records in the database.
 func (builder *Builder) Update(v interface{}) (int64, error) {
+	if err := builder.checkReturning("Update"); err != nil {
+		return 0, err
+	}
+	return builder.update(v)
+}
 
-	values := xun.MakeR(v).ToMap()
-	sql, bindings := builder.Grammar.CompileUpdate(builder.Query, values)
+// update Execute the update statement
+func (builder *Builder) update(v interface{}) (int64, error) {
+	defer builder.flushCache()
+
+	values := builder.touchUpdate(builder.prepareUpdateValues(v))
+	sql, bindings := builder.Grammar.CompileUpdate(builder.scoped().Query, values)
 	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.ctx(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -37,9 +48,84 @@ func (builder *Builder) MustUpdate(v interface{}) int64 {
 	return affected
 }
 
+// UpdateBatch Update the rows with their own values in one statement, the rows are matched by the key column.
+// The rows are split into several statements within a transaction if the placeholders exceed the limit of the database.
+// UpdateBatch([]xun.R{{"id": 1, "vote": 10}, {"id": 2, "vote": 20}}, "id")
+func (builder *Builder) UpdateBatch(v interface{}, key string) (int64, error) {
+	if err := builder.checkReturning("UpdateBatch"); err != nil {
+		return 0, err
+	}
+
+	columns, values, err := builder.prepareBatchValues(v, key)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(values) == 0 {
+		return 0, nil
+	}
+
+	// The key and the value of each column, and the key in the "where in" clause
+	size := (builder.Grammar.GetMaxPlaceholders() - len(builder.Query.GetBindings("where"))) / (2 * len(columns))
+	if size < 1 {
+		size = 1
+	}
+
+	if len(values) <= size {
+		return builder.updateBatch(key, columns, values)
+	}
+
+	var affected int64 = 0
+	err = builder.Transaction(func(qb Query) error {
+		for start := 0; start < len(values); start = start + size {
+			end := start + size
+			if end > len(values) {
+				end = len(values)
+			}
+			res, err := qb.Builder().updateBatch(key, columns, values[start:end])
+			if err != nil {
+				return err
+			}
+			affected = affected + res
+		}
+		return nil
+	})
+
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
+}
+
+// MustUpdateBatch Update the rows with their own values in one statement, the rows are matched by the key column.
+func (builder *Builder) MustUpdateBatch(v interface{}, key string) int64 {
+	affected, err := builder.UpdateBatch(v, key)
+	utils.PanicIF(err)
+	return affected
+}
+
+// updateBatch Execute the batch update statement of the given rows
+func (builder *Builder) updateBatch(key string, columns []interface{}, values [][]interface{}) (int64, error) {
+	defer builder.flushCache()
+	sql, bindings := builder.Grammar.CompileUpdateBatch(builder.scoped().Query, key, columns, values)
+	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
+
+	builder.UseWrite()
+	res, err := builder.executor().ExecContext(builder.ctx(), sql, bindings...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
 // UpdateOrInsert Insert or update a record matching the attributes, and fill it with values.
 func (builder *Builder) UpdateOrInsert(attributes interface{}, values ...interface{}) (bool, error) {
 
+	// The struct attributes are matched by the columns of the tags
+	if reflect.Indirect(reflect.ValueOf(attributes)).Kind() == reflect.Struct {
+		attributes = xun.MakeR(attributes).ToMap()
+	}
+
 	exists, err := builder.Where(attributes).Exists()
 	if err != nil {
 		return false, err
@@ -77,18 +163,38 @@ func (builder *Builder) MustUpdateOrInsert(attributes interface{}, values ...int
 
 // Upsert new records or update the existing ones.
 func (builder *Builder) Upsert(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) (int64, error) {
+	if err := builder.checkReturning("Upsert"); err != nil {
+		return 0, err
+	}
+	return builder.upsert(v, uniqueBy, update, columns...)
+}
+
+// upsert Execute the upsert statement of each group of the rows which have the same columns
+func (builder *Builder) upsert(v interface{}, uniqueBy interface{}, update interface{}, columns ...interface{}) (int64, error) {
+	groups, err := builder.prepareInsertValues(v, columns...)
+	if err != nil {
+		return 0, err
+	}
+
+	return builder.insertGroups(groups, func(qb *Builder, group insertGroup) (int64, error) {
+		return qb.upsertGroup(group, uniqueBy, update)
+	})
+}
 
-	columns, values := builder.prepareInsertValues(v, columns...)
-	sql, bindings := builder.Grammar.CompileUpsert(builder.Query, columns, values, utils.Flatten(uniqueBy), update)
+// upsertGroup Execute the upsert statement of the rows which have the same columns
+func (builder *Builder) upsertGroup(group insertGroup, uniqueBy interface{}, update interface{}) (int64, error) {
+	defer builder.flushCache()
+	sql, bindings := builder.Grammar.CompileUpsert(builder.Query, group.columns, group.values, utils.Flatten(uniqueBy), builder.touchUpsert(builder.prepareUpsertValues(update)))
 	defer log.With(log.F{"bindings": bindings}).Debug("%s", sql)
 
-	stmt, err := builder.UseWrite().DB().Prepare(sql)
+	builder.UseWrite()
+	stmt, err := builder.executor().PrepareContext(builder.ctx(), sql)
 	if err != nil {
 		return 0, err
 	}
 	defer stmt.Close()
 
-	res, err := stmt.Exec(bindings...)
+	res, err := stmt.ExecContext(builder.ctx(), bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -284,6 +284,133 @@ func TestUpdateMustUpdateOrInsertUpdateScore(t *testing.T) {
 	}
 }
 
+func TestUpdateMustUpdateBatch(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").MustUpdateBatch([]xun.R{
+		{"id": 1, "name": "John Smith", "vote": 11},
+		{"id": 2, "name": "Lee Wang", "vote": 6},
+		{"id": 3, "name": "Ken Li", "vote": 126},
+	}, "id")
+
+	assert.Equal(t, int64(3), affected, "The affected rows should be 3")
+	rows := qb.Table("table_test_update").Select("id", "name", "vote").OrderBy("id").MustGet()
+	assert.Equal(t, "John Smith", rows[0].Get("name"))
+	assert.Equal(t, int64(6), xun.MakeN(rows[1].Get("vote")).MustInt64())
+	assert.Equal(t, int64(126), xun.MakeN(rows[2].Get("vote")).MustInt64())
+	assert.Equal(t, "Ben", rows[3].Get("name"))
+}
+
+func TestUpdateMustUpdateBatchWithWhere(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_update").
+		Where("status", "DONE").
+		MustUpdateBatch([]xun.R{
+			{"id": 2, "vote": 50},
+			{"id": 3, "vote": 60},
+			{"id": 4, "vote": 70},
+		}, "id")
+
+	assert.Equal(t, int64(2), affected, "The affected rows should be 2")
+	rows := qb.Table("table_test_update").Select("vote").OrderBy("id").MustGet()
+	assert.Equal(t, int64(5), xun.MakeN(rows[1].Get("vote")).MustInt64())
+	assert.Equal(t, int64(60), xun.MakeN(rows[2].Get("vote")).MustInt64())
+	assert.Equal(t, int64(70), xun.MakeN(rows[3].Get("vote")).MustInt64())
+}
+
+func TestUpdateMustUpdateBatchChunk(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	size := qb.Builder().Grammar.GetMaxPlaceholders()/6 + 10
+	inserts := []xun.R{}
+	updates := []xun.R{}
+	for i := 0; i < size; i++ {
+		inserts = append(inserts, xun.R{"email": fmt.Sprintf("user%d@yao.run", i), "name": "User", "vote": 0, "score": 1, "score_grade": 1})
+		updates = append(updates, xun.R{"id": i + 5, "vote": i, "score": 2})
+		if len(inserts) == 1000 || i == size-1 {
+			qb.Table("table_test_update").MustInsert(inserts)
+			inserts = []xun.R{}
+		}
+	}
+
+	affected := qb.Table("table_test_update").MustUpdateBatch(updates, "id")
+	assert.Equal(t, int64(size), affected)
+	assert.Equal(t, int64(size), qb.Table("table_test_update").Where("score", 2).MustCount())
+	row := qb.Table("table_test_update").Where("id", size+4).MustFirst()
+	assert.Equal(t, int64(size-1), xun.MakeN(row.Get("vote")).MustInt64())
+}
+
+func TestUpdateMustUpdateBatchError(t *testing.T) {
+	NewTableForUpdateTest()
+	qb := getTestBuilder()
+	_, err := qb.Table("table_test_update").UpdateBatch([]xun.R{
+		{"id": 1, "vote": 11},
+		{"id": 2, "name": "Lee"},
+	}, "id")
+	assert.Error(t, err)
+
+	_, err = qb.Table("table_test_update").UpdateBatch([]xun.R{{"vote": 11}}, "id")
+	assert.Error(t, err)
+}
+
+func TestUpdateMustUpdateStructTags(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	id := qb.Table("table_test_insert_tag").MustInsertGetID(insertTagUser{Email: "john@yao.run", Name: "John"})
+
+	affected := qb.Table("table_test_insert_tag").Where("id", id).MustUpdate(insertTagUser{ID: id + 100, Email: "john@yao.run", Vote: 5, Meta: map[string]interface{}{"level": 2}})
+	assert.Equal(t, int64(1), affected, "The affected rows should be 1")
+
+	row := qb.Table("table_test_insert_tag").Where("id", id).MustFirst()
+	assert.False(t, row.IsEmpty(), "The auto id should not be updated")
+	assert.Equal(t, "John", row.Get("name"), "The omitted name should not be updated")
+	assert.Equal(t, int64(5), row.Get("vote"), "The vote should be 5")
+	assert.Equal(t, `{"level":2}`, fmt.Sprintf("%s", row.Get("meta")), "The meta should be the JSON text")
+}
+
+func TestUpdateMustUpsertStructTags(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_insert_tag").MustInsert(insertTagUser{Email: "john@yao.run", Name: "John", Vote: 1})
+
+	type voteUpdate struct {
+		ID   int64 `db:"id,auto"`
+		Vote int   `db:"vote"`
+	}
+	qb.Table("table_test_insert_tag").MustUpsert([]insertTagUser{
+		{Email: "john@yao.run", Vote: 3},
+		{Email: "lee@yao.run", Vote: 7},
+	}, "email", voteUpdate{ID: 100, Vote: 9})
+
+	rows := qb.Table("table_test_insert_tag").OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows), "The return rows should be 2")
+	if len(rows) == 2 {
+		assert.Equal(t, "John", rows[0].Get("name"), "The name of the existing row should not be updated")
+		assert.Equal(t, int64(9), rows[0].Get("vote"), "The vote of the existing row should be updated by the struct")
+		assert.Equal(t, int64(7), rows[1].Get("vote"), "The vote of the inserted row should be 7")
+		assert.NotEqual(t, int64(100), rows[0].Get("id"), "The auto id should not be updated")
+	}
+}
+
+func TestUpdateMustUpdateOrInsertStructTags(t *testing.T) {
+	NewTableForInsertTagTest()
+	qb := getTestBuilder()
+	res := qb.Table("table_test_insert_tag").MustUpdateOrInsert(insertTagUser{Email: "john@yao.run"}, insertTagUser{Email: "john@yao.run", Name: "John", Vote: 3})
+	assert.True(t, res, "the return value should be true")
+
+	res = qb.Table("table_test_insert_tag").MustUpdateOrInsert(xun.R{"email": "john@yao.run"}, insertTagUser{Email: "john@yao.run", Vote: 5, Meta: map[string]interface{}{"level": 1}})
+	assert.True(t, res, "the return value should be true")
+
+	rows := qb.Table("table_test_insert_tag").MustGet()
+	assert.Equal(t, 1, len(rows), "The return rows should be 1")
+	if len(rows) == 1 {
+		assert.Equal(t, "John", rows[0].Get("name"), "The omitted name should not be updated")
+		assert.Equal(t, int64(5), rows[0].Get("vote"), "The vote should be 5")
+		assert.Equal(t, `{"level":1}`, fmt.Sprintf("%s", rows[0].Get("meta")), "The meta should be the JSON text")
+	}
+}
+
 // clean the test data
 func TestUpdateClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
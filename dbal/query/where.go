@@ -3,6 +3,7 @@ package query
 import (
 	"fmt"
 	"reflect"
+	"strings"
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
@@ -87,11 +88,9 @@ func (builder *Builder) where(column interface{}, operator string, value interfa
 	// Where( func(qb Query){ qb.Where("name", "Ken")... }, ">", 5)
 	// Where( func(qb Query){ qb.Where("name", "Ken")... }, ">", 5, "or")
 	if whereType == "sub" {
-		sub, bindings, whereOffset := builder.createSub(column)
-		segment := builder.parseSub(sub)
+		sub, bindings, _ := builder.createSub(column)
 		builder.Query.AddBinding("where", bindings)
-		builder.Where(dbal.Raw(fmt.Sprintf("(%s)", segment)), operator, value, boolean, whereOffset)
-		return builder
+		return builder.where(sub, operator, value, boolean, offset, "basic")
 	}
 
 	// Where("vote", '>', func(sub Query) {
@@ -119,7 +118,11 @@ func (builder *Builder) where(column interface{}, operator string, value interfa
 	// If the column is making a JSON reference we'll check to see if the value
 	// is a boolean. If it is, we'll add the raw boolean string as an actual
 	// value to the query to ensure this is properly handled by the query.
-	//  if (Str::contains($column, '->') && is_bool($value)) {
+	if name, ok := column.(string); ok && strings.Contains(name, "->") {
+		if flag, ok := value.(bool); ok {
+			value = dbal.Raw(fmt.Sprintf("%v", flag))
+		}
+	}
 
 	// Where("email", "like", "%@yao.run")
 	// Now that we are working with just a simple query we can put the elements
@@ -241,8 +244,8 @@ func (builder *Builder) createSub(subquery interface{}) (interface{}, []interfac
 func (builder *Builder) makeSub(subquery interface{}) (interface{}, []interface{}, int) {
 	switch subquery.(type) {
 	case *Builder:
-		qb := builder.prependDatabaseNameIfCrossDatabaseQuery(subquery.(*Builder))
-		offset := len(builder.GetBindings())
+		qb := builder.prependDatabaseNameIfCrossDatabaseQuery(subquery.(*Builder)).scoped()
+		offset := len(builder.Query.GetBindings())
 		bindings := qb.GetBindings()
 		whereOffset := offset + len(utils.Flatten(bindings))
 		qb.Query.BindingOffset = offset
@@ -268,6 +271,7 @@ func (builder *Builder) prependDatabaseNameIfCrossDatabaseQuery(subquery *Builde
 func (builder *Builder) whereSub(column string, operator string, callback func(qb Query), boolean string) *Builder {
 	new := builder.forSubQuery()
 	callback(new)
+	new = new.scoped()
 	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
 		Type:     "sub",
 		Column:   column,
@@ -275,7 +279,7 @@ func (builder *Builder) whereSub(column string, operator string, callback func(q
 		Query:    new.Query,
 		Boolean:  boolean,
 	})
-	builder.Query.AddBinding("where", new.Query.Bindings["where"])
+	builder.Query.AddBinding("where", new.GetBindings())
 	return builder
 }
 
@@ -372,7 +376,9 @@ func (builder *Builder) OrWhereNotNull(column interface{}) Query {
 	return builder.WhereNull(column, "or", true)
 }
 
-// WhereRaw Add a basic where clause to the query.
+// WhereRaw Add a basic where clause to the query. The bindings could be a map or a struct for the ":name" placeholders.
+// WhereRaw("vote > ?", 10)
+// WhereRaw("vote > :min or score > :min", map[string]interface{}{"min": 10})
 func (builder *Builder) WhereRaw(sql string, bindings ...interface{}) Query {
 	return builder.whereRaw(sql, bindings, "and")
 }
@@ -383,6 +389,7 @@ func (builder *Builder) OrWhereRaw(sql string, bindings ...interface{}) Query {
 }
 
 func (builder *Builder) whereRaw(sql string, bindings []interface{}, boolean string) Query {
+	sql, bindings = builder.prepareNamedBindings(sql, bindings)
 	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
 		Type:    "raw",
 		SQL:     sql,
@@ -459,24 +466,19 @@ func (builder *Builder) OrWhereNotIn(column interface{}, values interface{}) Que
 // whereIn Add a "where in" clause to the query.
 func (builder *Builder) whereIn(column interface{}, values interface{}, boolean string, not bool) Query {
 
-	inOffset := 0
-
 	// If the value is a query builder instance we will assume the developer wants to
 	// look for any values that exists within this given query. So we will add the
 	// query accordingly so that this query is properly executed when it is run.
 	if builder.isQueryable(values) {
-		sub, bindings, offset := builder.createSub(values)
-		segment := builder.parseSub(sub)
-		values = dbal.Raw(segment)
+		sub, bindings, _ := builder.createSub(values)
+		values = sub
 		builder.Query.AddBinding("where", bindings)
-		inOffset = offset
 	}
 
 	where := dbal.Where{
 		Type:     "in",
 		ValuesIn: values,
 		Column:   column,
-		Offset:   inOffset,
 		Not:      not,
 		Boolean:  boolean,
 	}
@@ -515,6 +517,7 @@ func (builder *Builder) OrWhereNotExists(closure func(qb Query)) Query {
 func (builder *Builder) whereExists(closure func(qb Query), boolean string, not bool) Query {
 	new := builder.forSubQuery()
 	closure(new)
+	new = new.scoped()
 	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
 		Type:    "exists",
 		Not:     not,
@@ -645,7 +648,7 @@ func (builder *Builder) Unless(value bool, callback func(qb Query, value bool),
 }
 
 // WhereJSONContains Add a "where JSON contains" clause to the query.
-// PostgreSQL: col::jsonb @> value, MySQL: JSON_CONTAINS(col, value), SQLite: col LIKE value
+// PostgreSQL: col::jsonb @> value, MySQL: JSON_CONTAINS(col, value), SQLite: json_each(value) all in json_each(col)
 func (builder *Builder) WhereJSONContains(column interface{}, value interface{}) Query {
 	return builder.whereJSONContains(column, value, "and", false)
 }
@@ -681,9 +684,29 @@ func (builder *Builder) whereJSONContains(column interface{}, value interface{},
 }
 
 // WhereJSONLength Add a "where JSON length" clause to the query.
-func (builder *Builder) WhereJSONLength() {
+// WhereJSONLength("options->languages", 0)
+// WhereJSONLength("options->languages", ">", 1)
+func (builder *Builder) WhereJSONLength(column interface{}, args ...interface{}) Query {
+	operator, value, boolean, _ := builder.prepareWhereArgs(args...)
+	if builder.invalidOperator(operator) {
+		operator = "="
+	}
+	builder.Query.Wheres = append(builder.Query.Wheres, dbal.Where{
+		Type:     "jsonlength",
+		Column:   column,
+		Operator: operator,
+		Value:    value,
+		Boolean:  boolean,
+		Offset:   1,
+	})
+	if !builder.isExpression(value) {
+		builder.Query.AddBinding("where", value)
+	}
+	return builder
 }
 
 // OrWhereJSONLength Add an "or where JSON length" clause to the query.
-func (builder *Builder) OrWhereJSONLength() {
+func (builder *Builder) OrWhereJSONLength(column interface{}, args ...interface{}) Query {
+	operator, value, _, _ := builder.prepareWhereArgs(args...)
+	return builder.WhereJSONLength(column, operator, value, "or")
 }
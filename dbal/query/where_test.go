@@ -1612,6 +1612,16 @@ func checkNone(t *testing.T, qb Query) {
 
 // ==================== WhereJSONContains Tests ====================
 
+func jsonContainsSQLite(not bool, column string) string {
+	exists := "not exists"
+	if not {
+		exists = "exists"
+	}
+	return exists + " (select 1 from json_each(?) as candidate where not exists (" +
+		"select 1 from json_each(`" + column + "`) as target where target.type = candidate.type and target.value is candidate.value " +
+		"and (candidate.key is null or typeof(candidate.key) = 'integer' or target.key = candidate.key)))"
+}
+
 func NewTableForJSONContainsTest() {
 	defer unit.Catch()
 	builder := getTestSchemaBuilder()
@@ -1636,12 +1646,7 @@ func TestWhereJSONContains(t *testing.T) {
 	NewTableForJSONContainsTest()
 	qb := getTestBuilder()
 
-	var value interface{}
-	if unit.DriverIs("sqlite3") {
-		value = "%admin%"
-	} else {
-		value = `"admin"`
-	}
+	value := `"admin"`
 
 	qb.Table("table_test_json_contains").
 		WhereJSONContains("tags", value).
@@ -1651,7 +1656,7 @@ func TestWhereJSONContains(t *testing.T) {
 	if unit.DriverIs("postgres") {
 		assert.Equal(t, `select * from "table_test_json_contains" where "tags"::jsonb @> $1 order by "id" asc`, sql)
 	} else if unit.DriverIs("sqlite3") {
-		assert.Equal(t, "select * from `table_test_json_contains` where `tags` like ? order by `id` asc", sql)
+		assert.Equal(t, "select * from `table_test_json_contains` where "+jsonContainsSQLite(false, "tags")+" order by `id` asc", sql)
 	} else {
 		assert.Equal(t, "select * from `table_test_json_contains` where JSON_CONTAINS(`tags`, ?) order by `id` asc", sql)
 	}
@@ -1671,14 +1676,8 @@ func TestOrWhereJSONContains(t *testing.T) {
 	NewTableForJSONContainsTest()
 	qb := getTestBuilder()
 
-	var tagVal, localeVal interface{}
-	if unit.DriverIs("sqlite3") {
-		tagVal = "%admin%"
-		localeVal = "%fr%"
-	} else {
-		tagVal = `"admin"`
-		localeVal = `"fr"`
-	}
+	tagVal := `"admin"`
+	localeVal := `"fr"`
 
 	qb.Table("table_test_json_contains").
 		WhereJSONContains("tags", tagVal).
@@ -1689,7 +1688,7 @@ func TestOrWhereJSONContains(t *testing.T) {
 	if unit.DriverIs("postgres") {
 		assert.Equal(t, `select * from "table_test_json_contains" where "tags"::jsonb @> $1 or "locales"::jsonb @> $2 order by "id" asc`, sql)
 	} else if unit.DriverIs("sqlite3") {
-		assert.Equal(t, "select * from `table_test_json_contains` where `tags` like ? or `locales` like ? order by `id` asc", sql)
+		assert.Equal(t, "select * from `table_test_json_contains` where "+jsonContainsSQLite(false, "tags")+" or "+jsonContainsSQLite(false, "locales")+" order by `id` asc", sql)
 	} else {
 		assert.Equal(t, "select * from `table_test_json_contains` where JSON_CONTAINS(`tags`, ?) or JSON_CONTAINS(`locales`, ?) order by `id` asc", sql)
 	}
@@ -1710,12 +1709,7 @@ func TestWhereJSONDoesntContain(t *testing.T) {
 	NewTableForJSONContainsTest()
 	qb := getTestBuilder()
 
-	var value interface{}
-	if unit.DriverIs("sqlite3") {
-		value = "%admin%"
-	} else {
-		value = `"admin"`
-	}
+	value := `"admin"`
 
 	qb.Table("table_test_json_contains").
 		WhereJSONDoesntContain("tags", value).
@@ -1725,7 +1719,7 @@ func TestWhereJSONDoesntContain(t *testing.T) {
 	if unit.DriverIs("postgres") {
 		assert.Equal(t, `select * from "table_test_json_contains" where not "tags"::jsonb @> $1 order by "id" asc`, sql)
 	} else if unit.DriverIs("sqlite3") {
-		assert.Equal(t, "select * from `table_test_json_contains` where not `tags` like ? order by `id` asc", sql)
+		assert.Equal(t, "select * from `table_test_json_contains` where "+jsonContainsSQLite(true, "tags")+" order by `id` asc", sql)
 	} else {
 		assert.Equal(t, "select * from `table_test_json_contains` where not JSON_CONTAINS(`tags`, ?) order by `id` asc", sql)
 	}
@@ -1742,14 +1736,8 @@ func TestOrWhereJSONDoesntContain(t *testing.T) {
 	NewTableForJSONContainsTest()
 	qb := getTestBuilder()
 
-	var tagNotVal, localeNotVal interface{}
-	if unit.DriverIs("sqlite3") {
-		tagNotVal = "%admin%"
-		localeNotVal = "%en%"
-	} else {
-		tagNotVal = `"admin"`
-		localeNotVal = `"en"`
-	}
+	tagNotVal := `"admin"`
+	localeNotVal := `"en"`
 
 	qb.Table("table_test_json_contains").
 		WhereJSONDoesntContain("tags", tagNotVal).
@@ -1760,7 +1748,7 @@ func TestOrWhereJSONDoesntContain(t *testing.T) {
 	if unit.DriverIs("postgres") {
 		assert.Equal(t, `select * from "table_test_json_contains" where not "tags"::jsonb @> $1 or not "locales"::jsonb @> $2 order by "id" asc`, sql)
 	} else if unit.DriverIs("sqlite3") {
-		assert.Equal(t, "select * from `table_test_json_contains` where not `tags` like ? or not `locales` like ? order by `id` asc", sql)
+		assert.Equal(t, "select * from `table_test_json_contains` where "+jsonContainsSQLite(true, "tags")+" or "+jsonContainsSQLite(true, "locales")+" order by `id` asc", sql)
 	} else {
 		assert.Equal(t, "select * from `table_test_json_contains` where not JSON_CONTAINS(`tags`, ?) or not JSON_CONTAINS(`locales`, ?) order by `id` asc", sql)
 	}
@@ -1775,14 +1763,8 @@ func TestWhereJSONContainsNested(t *testing.T) {
 	NewTableForJSONContainsTest()
 	qb := getTestBuilder()
 
-	var tagVal, localeVal interface{}
-	if unit.DriverIs("sqlite3") {
-		tagVal = "%test%"
-		localeVal = "%en%"
-	} else {
-		tagVal = `"test"`
-		localeVal = `"en"`
-	}
+	tagVal := `"test"`
+	localeVal := `"en"`
 
 	qb.Table("table_test_json_contains").
 		Where("name", "<>", "Dave").
@@ -1796,7 +1778,7 @@ func TestWhereJSONContainsNested(t *testing.T) {
 	if unit.DriverIs("postgres") {
 		assert.Equal(t, `select * from "table_test_json_contains" where "name" <> $1 and ("tags"::jsonb @> $2 or "locales"::jsonb @> $3) order by "id" asc`, sql)
 	} else if unit.DriverIs("sqlite3") {
-		assert.Equal(t, "select * from `table_test_json_contains` where `name` <> ? and (`tags` like ? or `locales` like ?) order by `id` asc", sql)
+		assert.Equal(t, "select * from `table_test_json_contains` where `name` <> ? and ("+jsonContainsSQLite(false, "tags")+" or "+jsonContainsSQLite(false, "locales")+") order by `id` asc", sql)
 	} else {
 		assert.Equal(t, "select * from `table_test_json_contains` where `name` <> ? and (JSON_CONTAINS(`tags`, ?) or JSON_CONTAINS(`locales`, ?)) order by `id` asc", sql)
 	}
@@ -1816,12 +1798,7 @@ func TestWhereJSONContainsWithOtherWheres(t *testing.T) {
 	NewTableForJSONContainsTest()
 	qb := getTestBuilder()
 
-	var value interface{}
-	if unit.DriverIs("sqlite3") {
-		value = "%test%"
-	} else {
-		value = `"test"`
-	}
+	value := `"test"`
 
 	qb.Table("table_test_json_contains").
 		Where("name", "like", "%o%").
@@ -1832,7 +1809,7 @@ func TestWhereJSONContainsWithOtherWheres(t *testing.T) {
 	if unit.DriverIs("postgres") {
 		assert.Equal(t, `select * from "table_test_json_contains" where "name" like $1 and "tags"::jsonb @> $2 order by "id" asc`, sql)
 	} else if unit.DriverIs("sqlite3") {
-		assert.Equal(t, "select * from `table_test_json_contains` where `name` like ? and `tags` like ? order by `id` asc", sql)
+		assert.Equal(t, "select * from `table_test_json_contains` where `name` like ? and "+jsonContainsSQLite(false, "tags")+" order by `id` asc", sql)
 	} else {
 		assert.Equal(t, "select * from `table_test_json_contains` where `name` like ? and JSON_CONTAINS(`tags`, ?) order by `id` asc", sql)
 	}
@@ -1844,7 +1821,242 @@ func TestWhereJSONContainsWithOtherWheres(t *testing.T) {
 	}
 }
 
+func TestWhereJSONContainsNestedValues(t *testing.T) {
+	NewTableForJSONContainsTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_json_contains").MustInsert([]xun.R{
+		{"name": "Eve", "tags": `{"dining":{"meal":"salad","drink":"tea"}}`, "locales": `[{"a":1,"b":2}]`},
+		{"name": "Frank", "tags": `{"tags":["x","y"]}`, "locales": `[{"a":2}]`},
+	})
+
+	names := func(column string, value string) []string {
+		rows := qb.Table("table_test_json_contains").Select("name").WhereJSONContains(column, value).OrderBy("id").MustGet()
+		res := []string{}
+		for _, row := range rows {
+			res = append(res, row["name"].(string))
+		}
+		return res
+	}
+
+	assert.Equal(t, []string{"Eve"}, names("tags", `{"dining":{"meal":"salad"}}`))
+	assert.Equal(t, []string{"Eve"}, names("locales", `[{"a":1}]`))
+	assert.Equal(t, []string{"Frank"}, names("tags", `{"tags":["x"]}`))
+	assert.Equal(t, []string{}, names("tags", `{"dining":{"meal":"soup"}}`))
+	assert.Equal(t, []string{}, names("locales", `[{"a":1,"b":3}]`))
+	assert.Equal(t, []string{}, names("tags", `{"tags":["x","z"]}`))
+}
+
 func TestWhereJSONContainsClean(t *testing.T) {
 	builder := getTestSchemaBuilder()
 	builder.DropTableIfExists("table_test_json_contains")
 }
+
+// ==================== JSON Path Tests ====================
+
+func NewTableForJSONPathTest() {
+	defer unit.Catch()
+	builder := getTestSchemaBuilder()
+	builder.DropTableIfExists("table_test_json_path")
+	builder.MustCreateTable("table_test_json_path", func(table schema.Blueprint) {
+		table.ID("id")
+		table.String("name")
+		table.JSON("preferences")
+	})
+
+	qb := getTestBuilder()
+	qb.Table("table_test_json_path").Insert([]xun.R{
+		{"name": "Alice", "preferences": `{"dining":{"meal":"salad"},"enabled":true,"level":5}`},
+		{"name": "Bob", "preferences": `{"dining":{"meal":"steak"},"enabled":false,"level":12}`},
+		{"name": "Charlie", "preferences": `{"dining":{"meal":"salad"},"enabled":false,"level":3}`},
+	})
+}
+
+func TestWhereJSONPath(t *testing.T) {
+	NewTableForJSONPathTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_json_path").
+		Where("preferences->dining->meal", "salad").
+		OrderBy("id")
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_json_path" where "preferences"->'dining'->>'meal' = $1 order by "id" asc`, sql)
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_json_path` where json_extract(`preferences`, '$.\"dining\".\"meal\"') = ? order by `id` asc", sql)
+	} else {
+		assert.Equal(t, "select * from `table_test_json_path` where json_unquote(json_extract(`preferences`, '$.\"dining\".\"meal\"')) = ? order by `id` asc", sql)
+	}
+
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Alice", rows[0].Get("name"))
+	assert.Equal(t, "Charlie", rows[1].Get("name"))
+}
+
+func TestWhereJSONPathBoolean(t *testing.T) {
+	NewTableForJSONPathTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_json_path").Where("preferences->enabled", true)
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_json_path" where ("preferences"->'enabled')::jsonb = 'true'::jsonb`, sql)
+	} else {
+		assert.Equal(t, "select * from `table_test_json_path` where json_extract(`preferences`, '$.\"enabled\"') = true", sql)
+	}
+	assert.Equal(t, 0, len(qb.GetBindings()))
+
+	rows := qb.MustGet()
+	assert.Equal(t, 1, len(rows))
+	assert.Equal(t, "Alice", rows[0].Get("name"))
+}
+
+func TestWhereJSONPathNumeric(t *testing.T) {
+	NewTableForJSONPathTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_json_path").Where("preferences->level", ">", 4).OrderBy("id")
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_json_path" where ("preferences"->>'level')::numeric > $1 order by "id" asc`, sql)
+	}
+
+	rows := qb.MustGet()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Alice", rows[0].Get("name"))
+	assert.Equal(t, "Bob", rows[1].Get("name"))
+}
+
+func TestWhereJSONPathSelectOrderGroup(t *testing.T) {
+	NewTableForJSONPathTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_json_path").
+		Select("name", "preferences->dining->meal as meal").
+		OrderByDesc("preferences->level").
+		MustGet()
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, "Bob", rows[0].Get("name"))
+	assert.Equal(t, "steak", rows[0].Get("meal"))
+
+	rows = qb.Table("table_test_json_path").
+		Select("preferences->dining->meal as meal").
+		SelectRaw("count(*) as total").
+		GroupBy("preferences->dining->meal").
+		OrderBy("preferences->dining->meal").
+		MustGet()
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "salad", rows[0].Get("meal"))
+	assert.Equal(t, int64(2), rows[0].Get("total"))
+}
+
+func TestWhereJSONPathUpdate(t *testing.T) {
+	NewTableForJSONPathTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_json_path").
+		Where("name", "Bob").
+		MustUpdate(xun.R{"preferences->enabled": true, "preferences->dining->meal": "salad", "name": "Bobby"})
+	assert.Equal(t, int64(1), affected)
+
+	row := qb.Table("table_test_json_path").
+		Select("name", "preferences->dining->meal as meal", "preferences->level as level").
+		Where("preferences->enabled", true).
+		Where("id", 2).
+		MustFirst()
+	assert.Equal(t, "Bobby", row.Get("name"))
+	assert.Equal(t, "salad", row.Get("meal"))
+	assert.Equal(t, int64(12), xun.MakeN(row.Get("level")).MustInt64())
+}
+
+func TestWhereJSONContainsArray(t *testing.T) {
+	NewTableForJSONContainsTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_json_contains").
+		WhereJSONContains("tags", `["test","admin"]`).
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 1, len(rows), "only Alice has both test and admin tags")
+	if len(rows) == 1 {
+		assert.Equal(t, "Alice", rows[0]["name"].(string))
+	}
+
+	rows = qb.Table("table_test_json_contains").
+		WhereJSONContains("tags", `"adm"`).
+		MustGet()
+	assert.Equal(t, 0, len(rows), "the partial values should not be matched")
+}
+
+func TestWhereJSONContainsPath(t *testing.T) {
+	NewTableForJSONPathTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_json_path").
+		WhereJSONContains("preferences", `{"dining":{"meal":"salad"},"enabled":true}`).
+		MustGet()
+	assert.Equal(t, 1, len(rows))
+	if len(rows) == 1 {
+		assert.Equal(t, "Alice", rows[0]["name"].(string))
+	}
+
+	rows = qb.Table("table_test_json_path").
+		WhereJSONContains("preferences->dining", `{"meal":"salad"}`).
+		OrderBy("id").
+		MustGet()
+	assert.Equal(t, 2, len(rows))
+}
+
+func TestWhereJSONLength(t *testing.T) {
+	NewTableForJSONContainsTest()
+	qb := getTestBuilder()
+	qb.Table("table_test_json_contains").
+		WhereJSONLength("tags", 2).
+		OrWhereJSONLength("locales", ">", 1).
+		OrderBy("id")
+
+	sql := qb.ToSQL()
+	if unit.DriverIs("postgres") {
+		assert.Equal(t, `select * from "table_test_json_contains" where jsonb_array_length("tags"::jsonb) = $1 or jsonb_array_length("locales"::jsonb) > $2 order by "id" asc`, sql)
+	} else if unit.DriverIs("sqlite3") {
+		assert.Equal(t, "select * from `table_test_json_contains` where json_array_length(`tags`) = ? or json_array_length(`locales`) > ? order by `id` asc", sql)
+	} else {
+		assert.Equal(t, "select * from `table_test_json_contains` where json_length(`tags`) = ? or json_length(`locales`) > ? order by `id` asc", sql)
+	}
+
+	rows := qb.MustGet()
+	assert.Equal(t, 4, len(rows))
+
+	rows = qb.Table("table_test_json_contains").WhereJSONLength("tags", 1).OrderBy("id").MustGet()
+	assert.Equal(t, 2, len(rows), "Charlie and Dave have one tag")
+}
+
+func TestWhereWhereInSubBindings(t *testing.T) {
+	sqls := map[string]string{
+		"mysql":    "select * from `users` where `vote` > ? and `id` in (select `uid` from `votes` where `score` > ? limit 3) and `status` = ?",
+		"postgres": `select * from "users" where "vote" > $1 and "id" in (select "uid" from "votes" where "score" > $2 limit 3) and "status" = $3`,
+		"sqlite3":  "select * from `users` where `vote` > ? and `id` in (select `uid` from `votes` where `score` > ? limit 3) and `status` = ?",
+	}
+	for driver, sql := range sqls {
+		qb := getGrammarTestBuilder(driver)
+		qb.Table("users").Where("vote", ">", 1).WhereIn("id", func(sub Query) {
+			sub.From("votes").Select("uid").Where("score", ">", 5).Limit(3)
+		}).Where("status", "on")
+		assert.Equal(t, sql, qb.ToSQL(), driver)
+		assert.Equal(t, []interface{}{1, 5, "on"}, qb.GetBindings(), driver)
+	}
+}
+
+func TestWhereWhereValueIsClosureBindings(t *testing.T) {
+	sqls := map[string]string{
+		"mysql":    "select * from `users` where `vote` > ? and `score` > (select max(score) from `ranks` inner join `seasons` on (`seasons`.`id` = `ranks`.`sid` and `seasons`.`year` = ?) where `ranks`.`level` = ?) and `status` = ?",
+		"postgres": `select * from "users" where "vote" > $1 and "score" > (select max(score) from "ranks" inner join "seasons" on ("seasons"."id" = "ranks"."sid" and "seasons"."year" = $2) where "ranks"."level" = $3) and "status" = $4`,
+		"sqlite3":  "select * from `users` where `vote` > ? and `score` > (select max(score) from `ranks` inner join `seasons` on (`seasons`.`id` = `ranks`.`sid` and `seasons`.`year` = ?) where `ranks`.`level` = ?) and `status` = ?",
+	}
+	for driver, sql := range sqls {
+		qb := getGrammarTestBuilder(driver)
+		qb.Table("users").Where("vote", ">", 1).Where("score", ">", func(sub Query) {
+			sub.From("ranks").SelectRaw("max(score)").Join("seasons", func(join Query) {
+				join.On("seasons.id", "=", "ranks.sid").Where("seasons.year", 2021)
+			}).Where("ranks.level", 7)
+		}).Where("status", "on")
+		assert.Equal(t, sql, qb.ToSQL(), driver)
+		assert.Equal(t, []interface{}{1, 2021, 7, "on"}, qb.GetBindings(), driver)
+	}
+}
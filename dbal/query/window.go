@@ -0,0 +1,22 @@
+package query
+
+import (
+	"github.com/yaoapp/xun/dbal"
+)
+
+// Window Add a named window definition to the query. ( window `name` as (partition by ... order by ...) )
+// Window("w", dbal.NewWindow().PartitionBy("status").OrderBy("vote"))
+func (builder *Builder) Window(name string, window *dbal.Window) Query {
+	definition := window.Copy()
+	definition.Name = name
+	builder.Query.Windows = append(builder.Query.Windows, definition)
+	return builder
+}
+
+// windowValue Get the copy of the given window function, the window should not be changed after it was added.
+func (builder *Builder) windowValue(value interface{}) interface{} {
+	if window, ok := value.(*dbal.Window); ok {
+		return window.Copy()
+	}
+	return value
+}
@@ -0,0 +1,69 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+)
+
+func TestWindowSelect(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Select("name", dbal.Over("row_number").PartitionBy("status").OrderByDesc("vote").As("rn")).
+		OrderBy("id").
+		MustGet()
+
+	assert.Equal(t, 4, len(rows))
+	assert.Equal(t, int64(1), rows[0].Get("rn"))
+	assert.Equal(t, int64(1), rows[2].Get("rn"))
+	assert.Equal(t, int64(2), rows[3].Get("rn"))
+}
+
+func TestWindowNamed(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Select("name",
+			dbal.Over("rank").Window("w").As("rank"),
+			dbal.Over("sum", "vote").Window("w").As("total"),
+		).
+		Window("w", dbal.NewWindow().OrderByDesc("vote")).
+		OrderBy("id").
+		MustGet()
+
+	assert.Equal(t, 4, len(rows))
+	assert.Equal(t, int64(2), rows[0].Get("rank"))
+	assert.Equal(t, int64(135), rows[0].Get("total"))
+	assert.Equal(t, int64(1), rows[2].Get("rank"))
+}
+
+func TestWindowOrderBy(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		Select("name").
+		OrderBy(dbal.Over("row_number").OrderByDesc("vote"), "desc").
+		MustGet()
+
+	assert.Equal(t, 4, len(rows))
+	assert.Equal(t, "Lee", rows[0].Get("name"))
+	assert.Equal(t, "Ken", rows[3].Get("name"))
+}
+
+func TestWindowFromSub(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.New().
+		FromSub(func(qb Query) {
+			qb.Select("name", "status", dbal.Over("row_number").PartitionBy("table_test_paginate.status").OrderByDesc("vote").As("rn")).
+				From("table_test_paginate")
+		}, "ranked").
+		Where("rn", 1).
+		OrderBy("status").
+		MustGet()
+
+	assert.Equal(t, 3, len(rows))
+	assert.Equal(t, "Ken", rows[0].Get("name"))
+}
@@ -0,0 +1,59 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// With Add a common table expression to the query. The subquery could be a query builder instance, a closure or a raw SQL string.
+func (builder *Builder) With(name string, qb interface{}, columns ...string) Query {
+	return builder.with(name, qb, columns, false, false)
+}
+
+// WithRecursive Add a recursive common table expression to the query. The unions of the subquery are compiled as the recursive part.
+func (builder *Builder) WithRecursive(name string, qb interface{}, columns ...string) Query {
+	return builder.with(name, qb, columns, true, false)
+}
+
+// WithMaterialized Add a materialized common table expression to the query. The hint is ignored by the grammars which do not support it (MySQL).
+func (builder *Builder) WithMaterialized(name string, qb interface{}, columns ...string) Query {
+	return builder.with(name, qb, columns, false, true)
+}
+
+// with Add a common table expression to the query.
+func (builder *Builder) with(name string, subquery interface{}, columns []string, recursive bool, materialized bool) Query {
+
+	if builder.isClosure(subquery) {
+		callback := subquery.(func(qb Query))
+		qb := builder.forSubQuery()
+		callback(qb)
+		subquery = qb
+	}
+
+	cte := dbal.CTE{
+		Name:         builder.Conn.Option.Prefix + name,
+		Columns:      columns,
+		Recursive:    recursive,
+		Materialized: materialized,
+	}
+
+	// The subquery is compiled with the statement, so the placeholders are numbered from the bindings before it.
+	bindings := []interface{}{}
+	switch value := subquery.(type) {
+	case *Builder:
+		value = value.scoped()
+		cte.Query = value.Query
+		bindings = value.GetBindings()
+	case dbal.Expression:
+		cte.SQL = value.GetValue()
+	case string:
+		cte.SQL = value
+	default:
+		panic(fmt.Errorf("a common table expression must be a query builder instance, a Closure, or a string"))
+	}
+
+	builder.Query.CTEs = append(builder.Query.CTEs, cte)
+	builder.Query.AddBinding("with", bindings)
+	return builder
+}
@@ -0,0 +1,60 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun"
+)
+
+func TestWithWith(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	rows := qb.Table("table_test_paginate").
+		With("done", func(qb Query) {
+			qb.Select("id", "name", "vote").From("table_test_paginate").Where("status", "DONE")
+		}).
+		From("done").
+		Where("vote", ">", 5).
+		OrderBy("id").
+		MustGet()
+
+	assert.Equal(t, 2, len(rows))
+	assert.Equal(t, "Ken", rows[0].Get("name"))
+	assert.Equal(t, "Ben", rows[1].Get("name"))
+}
+
+func TestWithWithRecursive(t *testing.T) {
+	qb := getTestBuilder()
+	rows := qb.New().
+		WithRecursive("nums", func(qb Query) {
+			qb.SelectRaw("1").UnionAll(func(qb Query) {
+				qb.SelectRaw("n + 1").From("nums").Where("n", "<", 5)
+			})
+		}, "n").
+		From("nums").
+		MustGet()
+
+	assert.Equal(t, 5, len(rows))
+}
+
+func TestWithUpdateAndDelete(t *testing.T) {
+	NewTableForPaginateTest()
+	qb := getTestBuilder()
+	affected := qb.Table("table_test_paginate").
+		With("done", func(qb Query) {
+			qb.Select("id").From("table_test_paginate").Where("status", "DONE")
+		}).
+		WhereIn("id", func(qb Query) { qb.Select("id").From("done") }).
+		MustUpdate(xun.R{"vote": 0})
+	assert.Equal(t, int64(2), affected)
+
+	affected = qb.Table("table_test_paginate").
+		With("zero", func(qb Query) {
+			qb.Select("id").From("table_test_paginate").Where("vote", 0)
+		}).
+		WhereIn("id", func(qb Query) { qb.Select("id").From("zero") }).
+		MustDelete()
+	assert.Equal(t, int64(2), affected)
+	assert.Equal(t, int64(2), qb.Table("table_test_paginate").MustCount())
+}
@@ -315,7 +315,7 @@ func TestBuilderGetVersion(t *testing.T) {
 	} else if unit.Is("sqlite3") {
 		assert.Equal(t, "sqlite3", version.Driver, "the driver should be sqlite3")
 		assert.Equal(t, 3, int(version.Major), "the major version should be 3")
-		assert.Equal(t, 34, int(version.Minor), "the minor version should be 34")
+		assert.Equal(t, 39, int(version.Minor), "the minor version should be 39")
 	}
 	// fmt.Printf("The version is: %s %d.%d\n", version.Driver, version.Major, version.Minor)
 }
@@ -532,7 +532,7 @@ func TestBuilderMustGetVersion(t *testing.T) {
 	} else if unit.Is("sqlite3") {
 		assert.Equal(t, "sqlite3", version.Driver, "the driver should be sqlite3")
 		assert.Equal(t, 3, int(version.Major), "the major version should be 3")
-		assert.Equal(t, 34, int(version.Minor), "the minor version should be 34")
+		assert.Equal(t, 39, int(version.Minor), "the minor version should be 39")
 	}
 	// fmt.Printf("The version is: %s %d.%d\n", version.Driver, version.Major, version.Minor)
 }
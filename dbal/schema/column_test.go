@@ -1,6 +1,7 @@
 package schema
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -243,3 +244,25 @@ func NewTableForColumnTest() {
 		table.AddIndex("field1_field2", "field1", "field2")
 	})
 }
+
+func TestColumnQuotedDefaultAndComment(t *testing.T) {
+	builder := getTestBuilderInstance()
+	builder.DropTableIfExists("table_test_column_quoted")
+	defer builder.DropTableIfExists("table_test_column_quoted")
+
+	err := builder.CreateTable("table_test_column_quoted", func(table Blueprint) {
+		table.ID("id")
+		table.String("note", 50).SetDefault(`it's C:\temp`).SetComment(`it's C:\temp`)
+	})
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	_, err = builder.Conn.Write.Exec(fmt.Sprintf("insert into %s (id) values (1)", builder.Grammar.WrapTable(builder.Conn.Option.Prefix+"table_test_column_quoted")))
+	assert.Nil(t, err)
+
+	note := ""
+	err = builder.Conn.Write.Get(&note, fmt.Sprintf("select note from %s", builder.Grammar.WrapTable(builder.Conn.Option.Prefix+"table_test_column_quoted")))
+	assert.Nil(t, err)
+	assert.Equal(t, `it's C:\temp`, note)
+}
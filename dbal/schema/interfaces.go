@@ -39,6 +39,17 @@ type Schema interface {
 	MustDropTableIfExists(name string)
 
 	DB() *sqlx.DB // alias MustGetDB
+
+	Begin() (Schema, error)
+	Commit() error
+	Rollback() error
+	Transaction(callback func(sch Schema) error) error
+	InTransaction() bool
+
+	MustBegin() Schema
+	MustCommit()
+	MustRollback()
+	MustTransaction(callback func(sch Schema) error)
 }
 
 // Blueprint the table operating interface
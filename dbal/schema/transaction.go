@@ -0,0 +1,99 @@
+package schema
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// Begin Start a new transaction and return a schema builder bound to it. If the builder was already in a transaction, a savepoint will be created.
+func (builder *Builder) Begin() (Schema, error) {
+	var err error
+	var tx *dbal.Transaction
+	if builder.Tx == nil {
+		tx, err = dbal.BeginTransaction(builder.Conn.Write)
+	} else {
+		tx, err = builder.Tx.Begin(builder.Grammar)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	new := *builder
+	new.Tx = tx
+	new.Grammar = builder.Grammar.WithExecutor(tx.Tx)
+	return &new, nil
+}
+
+// MustBegin Start a new transaction and return a schema builder bound to it.
+func (builder *Builder) MustBegin() Schema {
+	sch, err := builder.Begin()
+	utils.PanicIF(err)
+	return sch
+}
+
+// Commit Commit the transaction, the savepoint will be released if the transaction is nested.
+func (builder *Builder) Commit() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the transaction was not started")
+	}
+	return builder.Tx.Commit(builder.Grammar)
+}
+
+// MustCommit Commit the transaction, the savepoint will be released if the transaction is nested.
+func (builder *Builder) MustCommit() {
+	err := builder.Commit()
+	utils.PanicIF(err)
+}
+
+// Rollback Rollback the transaction, rollback to the savepoint if the transaction is nested.
+func (builder *Builder) Rollback() error {
+	if builder.Tx == nil {
+		return fmt.Errorf("the transaction was not started")
+	}
+	return builder.Tx.Rollback(builder.Grammar)
+}
+
+// MustRollback Rollback the transaction, rollback to the savepoint if the transaction is nested.
+func (builder *Builder) MustRollback() {
+	err := builder.Rollback()
+	utils.PanicIF(err)
+}
+
+// Transaction Execute the callback within a transaction. The transaction will be rolled back if the callback returns an error or panics, otherwise it will be committed.
+func (builder *Builder) Transaction(callback func(sch Schema) error) error {
+	sch, err := builder.Begin()
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			sch.Rollback()
+			panic(r)
+		}
+	}()
+
+	err = callback(sch)
+	if err != nil {
+		if rerr := sch.Rollback(); rerr != nil {
+			return fmt.Errorf("%s (rollback error: %s)", err, rerr)
+		}
+		return err
+	}
+
+	return sch.Commit()
+}
+
+// MustTransaction Execute the callback within a transaction.
+func (builder *Builder) MustTransaction(callback func(sch Schema) error) {
+	err := builder.Transaction(callback)
+	utils.PanicIF(err)
+}
+
+// InTransaction Determine if the builder was bound to a transaction.
+func (builder *Builder) InTransaction() bool {
+	return builder.Tx != nil
+}
@@ -0,0 +1,44 @@
+package schema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/unit"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	defer unit.Catch()
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	err := builder.Transaction(func(sch Schema) error {
+		assert.True(t, sch.InTransaction(), "The builder should be in transaction")
+		return sch.CreateTable("table_test_transaction", func(table Blueprint) {
+			table.ID("id")
+			table.String("name")
+		})
+	})
+	assert.Nil(t, err)
+	assert.True(t, builder.MustHasTable("table_test_transaction"), "The table should be created")
+	builder.MustDropTableIfExists("table_test_transaction")
+}
+
+func TestTransactionRollback(t *testing.T) {
+	defer unit.Catch()
+	if unit.DriverIs("mysql") {
+		// DDL statements cause an implicit commit in MySQL
+		return
+	}
+	builder := getTestBuilder()
+	builder.DropTableIfExists("table_test_transaction")
+	err := builder.Transaction(func(sch Schema) error {
+		sch.MustCreateTable("table_test_transaction", func(table Blueprint) {
+			table.ID("id")
+			table.String("name")
+		})
+		return errors.New("something wrong")
+	})
+	assert.Equal(t, "something wrong", err.Error())
+	assert.False(t, builder.MustHasTable("table_test_transaction"), "The table should not be created")
+}
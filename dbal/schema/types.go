@@ -19,6 +19,7 @@ type Builder struct {
 	Mode     string
 	Database string
 	Schema   string
+	Tx       *dbal.Transaction
 	dbal.Grammar
 }
 
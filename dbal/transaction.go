@@ -0,0 +1,82 @@
+package dbal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// BeginTransaction begin a new transaction using the given connection
+func BeginTransaction(db *sqlx.DB) (*Transaction, error) {
+	return BeginTransactionContext(context.Background(), db)
+}
+
+// BeginTransactionContext begin a new transaction using the given connection and context
+func BeginTransactionContext(ctx context.Context, db *sqlx.DB) (*Transaction, error) {
+	if db == nil {
+		return nil, fmt.Errorf("the connection is nil")
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{Tx: tx, Level: 0}, nil
+}
+
+// Begin create a savepoint and return the nested transaction
+func (trans *Transaction) Begin(grammar Grammar) (*Transaction, error) {
+	nested := &Transaction{
+		Tx:        trans.Tx,
+		Level:     trans.Level + 1,
+		Savepoint: fmt.Sprintf("xun_savepoint_%d", trans.Level+1),
+		root:      trans.top(),
+	}
+	_, err := trans.Tx.Exec(grammar.CompileSavepoint(nested.Savepoint))
+	if err != nil {
+		return nil, err
+	}
+	return nested, nil
+}
+
+// Commit commit the transaction, release the savepoint if the transaction is nested
+func (trans *Transaction) Commit(grammar Grammar) error {
+	if trans.Level == 0 {
+		err := trans.Tx.Commit()
+		if err != nil {
+			return err
+		}
+		for _, fn := range trans.committed {
+			fn()
+		}
+		trans.committed = nil
+		return nil
+	}
+	_, err := trans.Tx.Exec(grammar.CompileSavepointRelease(trans.Savepoint))
+	return err
+}
+
+// Rollback rollback the transaction, rollback to the savepoint if the transaction is nested
+func (trans *Transaction) Rollback(grammar Grammar) error {
+	if trans.Level == 0 {
+		trans.committed = nil
+		return trans.Tx.Rollback()
+	}
+	_, err := trans.Tx.Exec(grammar.CompileSavepointRollBack(trans.Savepoint))
+	return err
+}
+
+// AfterCommit register the callback which will be called after the outermost transaction is committed
+func (trans *Transaction) AfterCommit(fn func()) {
+	top := trans.top()
+	top.committed = append(top.committed, fn)
+}
+
+// top get the outermost transaction
+func (trans *Transaction) top() *Transaction {
+	if trans.root != nil {
+		return trans.root
+	}
+	return trans
+}
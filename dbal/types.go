@@ -1,6 +1,9 @@
 package dbal
 
 import (
+	"container/list"
+	"encoding/json"
+	"sync"
 	"time"
 
 	"github.com/blang/semver/v4"
@@ -185,6 +188,46 @@ type Join struct {
 	Offset int
 }
 
+// CTE the common table expression of the query ( with name as (select ...) )
+// The sub-query is compiled with the bindings offset of the statement, the raw SQL is used if the query is nil.
+type CTE struct {
+	Name         string
+	Columns      []string
+	Recursive    bool
+	Materialized bool
+	Query        *Query
+	SQL          string
+	Offset       int
+}
+
+// Window the window specification of a window function ( func() over (partition by ... order by ... rows ...) )
+type Window struct {
+	Func       string        // The window function, row_number, rank, sum ...
+	Args       []interface{} // The arguments of the window function
+	Name       string        // The name of the window, the named window definition or the window referenced by the function
+	Partitions []interface{} // The partition by columns
+	Orders     []Order       // The order by columns
+	Frame      string        // The frame clause, rows between unbounded preceding and current row ...
+	Alias      string        // The alias of the window function column
+}
+
+// FullText the full-text search of the columns ( match ... against, to_tsvector ... @@ ..., fts5 match )
+type FullText struct {
+	Columns  []string // The columns of the full-text search
+	Search   string   // The search text
+	Mode     string   // The search mode, natural (default) or boolean
+	Language string   // The text search configuration of PostgreSQL, english (default)
+	Table    string   // The FTS5 virtual table of SQLite, the table of the query (default)
+	Alias    string   // The alias of the relevance score column
+}
+
+// Lock the row locking of the query ( for update of "users" skip locked )
+type Lock struct {
+	Mode   string   // The lock mode, share, update, no key update or key share
+	Wait   string   // The waiting policy, nowait or skip locked. waits for the locked rows if empty
+	Tables []string // The tables of the locked rows, all of the tables if empty
+}
+
 // Union the query union statement
 type Union struct {
 	All   bool // Union all
@@ -240,6 +283,7 @@ type Select struct {
 // Query the query builder
 type Query struct {
 	UseWriteConnection bool                     // Whether to use write connection for the select. default is false
+	CTEs               []CTE                    // The common table expressions of the query.
 	Lock               interface{}              //  Indicates whether row locking is being used.
 	From               From                     // The table which the query is targeting.
 	Columns            []interface{}            // The columns that should be returned. (Name or Expression)
@@ -255,10 +299,228 @@ type Query struct {
 	Offset             int                      // The number of records to skip.
 	Groups             []interface{}            // The groupings for the query.
 	Havings            []Having                 // The having constraints for the query.
+	Windows            []Window                 // The named window definitions for the query.
+	Returning          []interface{}            // The columns that should be returned by the insert, update, delete and upsert statements.
 	Bindings           map[string][]interface{} // The current query value bindings.
 	Distinct           bool                     // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct. default is false
 	DistinctColumns    []interface{}            // Indicates if the query returns distinct results. Occasionally contains the columns that should be distinct.
 	IsJoinClause       bool                     // Determine if the query is a join clause.
 	BindingOffset      int                      // The Binding offset before select
 	SQL                string                   // The SQL STMT
+	Remember           *Remember                // The caching option of the query results, the results are not cached if nil.
+	WithoutScopes      []string                 // The names of the global scopes which are not applied to the query, "*" for all of the scopes.
+	OnlyTrashed        bool                     // Determine if only the soft deleted rows of the table are selected.
+}
+
+// Explain the option of the query plan
+type Explain struct {
+	Analyze bool // Execute the statement to get the actual rows and time of the plan nodes, PostgreSQL only
+}
+
+// Plan the node of the query plan, the nodes of the databases are normalized
+type Plan struct {
+	Type       string  // The normalized type of the node, full scan, index scan, join, sort, aggregate or other
+	Operation  string  // The operation of the node given by the database. Seq Scan, ALL, SCAN ...
+	Table      string  // The table of the node
+	Index      string  // The index used by the node
+	Rows       float64 // The estimated rows of the node, zero if not available
+	Cost       float64 // The estimated cost of the node, zero if not available
+	ActualRows float64 // The actual rows of the node, analyzed only
+	ActualTime float64 // The actual time of the node in milliseconds, analyzed only
+	Detail     string  // The description of the node given by the database
+	Children   []*Plan
+}
+
+// Remember the caching option of the query results
+type Remember struct {
+	TTL time.Duration // The time to live of the cached results, never expires if zero
+	Key string        // The key of the cached results, the results could be flushed by the key
+}
+
+// LRUCache the in-memory cache store, the least recently used entries are evicted when the capacity is exceeded
+type LRUCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	tags     map[string]map[string]bool
+	list     *list.List
+	mutex    sync.Mutex
+}
+
+// lruEntry the entry of the in-memory cache store
+type lruEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+	tags    []string
+}
+
+// Transaction the database transaction, the nested transaction is implemented with savepoints
+type Transaction struct {
+	Tx        *sqlx.Tx
+	Level     int
+	Savepoint string
+	root      *Transaction
+	committed []func()
+}
+
+// astQuery the JSON representation of the query ( the query AST )
+type astQuery struct {
+	Version            int                   `json:"version,omitempty"`
+	UseWriteConnection bool                  `json:"use_write_connection"`
+	CTEs               []astCTE              `json:"ctes"`
+	Lock               astValue              `json:"lock"`
+	From               astFrom               `json:"from"`
+	Columns            []astValue            `json:"columns"`
+	Aggregate          astAggregate          `json:"aggregate"`
+	Wheres             []astWhere            `json:"wheres"`
+	Joins              []astJoin             `json:"joins"`
+	Unions             []astUnion            `json:"unions"`
+	UnionLimit         int                   `json:"union_limit"`
+	UnionOffset        int                   `json:"union_offset"`
+	UnionOrders        []astOrder            `json:"union_orders"`
+	Orders             []astOrder            `json:"orders"`
+	Limit              int                   `json:"limit"`
+	Offset             int                   `json:"offset"`
+	Groups             []astValue            `json:"groups"`
+	Havings            []astHaving           `json:"havings"`
+	Windows            []astWindow           `json:"windows"`
+	Returning          []astValue            `json:"returning"`
+	Bindings           map[string][]astValue `json:"bindings"`
+	Distinct           bool                  `json:"distinct"`
+	DistinctColumns    []astValue            `json:"distinct_columns"`
+	IsJoinClause       bool                  `json:"is_join_clause"`
+	BindingOffset      int                   `json:"binding_offset"`
+	SQL                string                `json:"sql"`
+	Remember           *astRemember          `json:"remember"`
+	WithoutScopes      []string              `json:"without_scopes"`
+	OnlyTrashed        bool                  `json:"only_trashed"`
+}
+
+// astValue the JSON representation of a value with its type, so the value could be decoded as the same type
+type astValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// astFrom the JSON representation of the From and the Select
+type astFrom struct {
+	Type   string   `json:"type"`
+	Name   astValue `json:"name"`
+	Alias  string   `json:"alias"`
+	Offset int      `json:"offset"`
+	SQL    string   `json:"sql"`
+}
+
+// astWhere the JSON representation of the Where
+type astWhere struct {
+	Type     string     `json:"type"`
+	Column   astValue   `json:"column"`
+	First    astValue   `json:"first"`
+	Second   astValue   `json:"second"`
+	SQL      string     `json:"sql"`
+	Operator string     `json:"operator"`
+	Boolean  string     `json:"boolean"`
+	Wheres   []astWhere `json:"wheres"`
+	Query    *astQuery  `json:"query"`
+	Value    astValue   `json:"value"`
+	Values   []astValue `json:"values"`
+	ValuesIn astValue   `json:"values_in"`
+	Not      bool       `json:"not"`
+	Offset   int        `json:"offset"`
+}
+
+// astJoin the JSON representation of the Join
+type astJoin struct {
+	Type   string    `json:"type"`
+	Name   astValue  `json:"name"`
+	Query  *astQuery `json:"query"`
+	Alias  string    `json:"alias"`
+	SQL    astValue  `json:"sql"`
+	Offset int       `json:"offset"`
+}
+
+// astCTE the JSON representation of the CTE
+type astCTE struct {
+	Name         string    `json:"name"`
+	Columns      []string  `json:"columns"`
+	Recursive    bool      `json:"recursive"`
+	Materialized bool      `json:"materialized"`
+	Query        *astQuery `json:"query"`
+	SQL          string    `json:"sql"`
+	Offset       int       `json:"offset"`
+}
+
+// astUnion the JSON representation of the Union
+type astUnion struct {
+	All   bool      `json:"all"`
+	Query *astQuery `json:"query"`
+}
+
+// astAggregate the JSON representation of the Aggregate
+type astAggregate struct {
+	Func    string     `json:"func"`
+	Columns []astValue `json:"columns"`
+}
+
+// astHaving the JSON representation of the Having
+type astHaving struct {
+	Type     string     `json:"type"`
+	Column   astValue   `json:"column"`
+	Operator string     `json:"operator"`
+	Value    astValue   `json:"value"`
+	Boolean  string     `json:"boolean"`
+	Offset   int        `json:"offset"`
+	Values   []astValue `json:"values"`
+	Not      bool       `json:"not"`
+	SQL      string     `json:"sql"`
+}
+
+// astOrder the JSON representation of the Order
+type astOrder struct {
+	Type      string   `json:"type"`
+	Column    astValue `json:"column"`
+	Direction string   `json:"direction"`
+	Offset    int      `json:"offset"`
+	SQL       string   `json:"sql"`
+}
+
+// astWindow the JSON representation of the Window
+type astWindow struct {
+	Func       string     `json:"func"`
+	Args       []astValue `json:"args"`
+	Name       string     `json:"name"`
+	Partitions []astValue `json:"partitions"`
+	Orders     []astOrder `json:"orders"`
+	Frame      string     `json:"frame"`
+	Alias      string     `json:"alias"`
+}
+
+// astName the JSON representation of the Name
+type astName struct {
+	Prefix string `json:"prefix"`
+	Name   string `json:"name"`
+	Alias  string `json:"alias"`
+}
+
+// astFullText the JSON representation of the FullText
+type astFullText struct {
+	Columns  []string `json:"columns"`
+	Search   string   `json:"search"`
+	Mode     string   `json:"mode"`
+	Language string   `json:"language"`
+	Table    string   `json:"table"`
+	Alias    string   `json:"alias"`
+}
+
+// astLock the JSON representation of the Lock
+type astLock struct {
+	Mode   string   `json:"mode"`
+	Wait   string   `json:"wait"`
+	Tables []string `json:"tables"`
+}
+
+// astRemember the JSON representation of the Remember
+type astRemember struct {
+	TTL time.Duration `json:"ttl"`
+	Key string        `json:"key"`
 }
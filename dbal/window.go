@@ -0,0 +1,87 @@
+package dbal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Over make a new window function instance. the arguments are the columns or expressions of the function.
+// Over("row_number").PartitionBy("status").OrderBy("vote", "desc").As("rank")
+// Over("sum", "amount").Window("w").As("total")
+func Over(function string, args ...interface{}) *Window {
+	return &Window{
+		Func:       function,
+		Args:       args,
+		Partitions: []interface{}{},
+		Orders:     []Order{},
+	}
+}
+
+// NewWindow make a new window specification instance for the named window definitions
+func NewWindow() *Window {
+	return &Window{
+		Args:       []interface{}{},
+		Partitions: []interface{}{},
+		Orders:     []Order{},
+	}
+}
+
+// PartitionBy Add the "partition by" columns to the window
+func (window *Window) PartitionBy(columns ...interface{}) *Window {
+	window.Partitions = append(window.Partitions, columns...)
+	return window
+}
+
+// OrderBy Add an "order by" column to the window
+func (window *Window) OrderBy(column interface{}, direction ...string) *Window {
+	dir := "asc"
+	if len(direction) > 0 {
+		dir = strings.ToLower(direction[0])
+	}
+	if dir != "asc" && dir != "desc" {
+		panic(fmt.Errorf(`Order direction must be "asc" or "desc`))
+	}
+	window.Orders = append(window.Orders, Order{
+		Type:      "basic",
+		Column:    column,
+		Direction: dir,
+	})
+	return window
+}
+
+// OrderByDesc Add a descending "order by" column to the window
+func (window *Window) OrderByDesc(column interface{}) *Window {
+	return window.OrderBy(column, "desc")
+}
+
+// Rows Set the "rows" frame clause of the window. Rows("between unbounded preceding and current row")
+func (window *Window) Rows(frame string) *Window {
+	window.Frame = fmt.Sprintf("rows %s", frame)
+	return window
+}
+
+// Range Set the "range" frame clause of the window. Range("between unbounded preceding and current row")
+func (window *Window) Range(frame string) *Window {
+	window.Frame = fmt.Sprintf("range %s", frame)
+	return window
+}
+
+// Window Set the name of the window. For a window function, it references a named window definition.
+func (window *Window) Window(name string) *Window {
+	window.Name = name
+	return window
+}
+
+// As Set the alias of the window function column
+func (window *Window) As(alias string) *Window {
+	window.Alias = alias
+	return window
+}
+
+// Copy copy the window instance
+func (window Window) Copy() Window {
+	window.Args = append([]interface{}{}, window.Args...)
+	window.Partitions = append([]interface{}{}, window.Partitions...)
+	window.Orders = append([]Order{}, window.Orders...)
+	return window
+}
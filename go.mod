@@ -12,7 +12,7 @@ require (
 	github.com/jmoiron/sqlx v1.3.1
 	github.com/json-iterator/go v1.1.12
 	github.com/lib/pq v1.9.0
-	github.com/mattn/go-sqlite3 v1.14.6
+	github.com/mattn/go-sqlite3 v1.14.16
 	github.com/qustavo/sqlhooks/v2 v2.1.0
 	github.com/stretchr/testify v1.10.0
 	github.com/yaoapp/kun v0.9.0
@@ -18,12 +18,13 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 
 	// SQL STMT
 	if query.SQL != "" {
-		if !strings.Contains(query.SQL, "limit") && !strings.Contains(query.SQL, "offset") {
+		stmt := grammarSQL.CompileNamedParameters(query.SQL, offset)
+		if !strings.Contains(stmt, "limit") && !strings.Contains(stmt, "offset") {
 			limit := grammarSQL.CompileLimit(query, query.Limit, offset)
 			offset := grammarSQL.CompileOffset(query, query.Offset)
-			return strings.TrimSpace(fmt.Sprintf("%s %s %s", query.SQL, limit, offset))
+			return strings.TrimSpace(fmt.Sprintf("%s %s %s", stmt, limit, offset))
 		}
-		return query.SQL
+		return stmt
 	}
 
 	if len(query.Unions) > 0 && query.Aggregate.Func != "" {
@@ -43,6 +44,7 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 	// To compile the query, we'll spin through each component of the query and
 	// see if that component exists. If it does we'll just call the compiler
 	// function for the component which is responsible for making the SQL.
+	sqls["with"] = grammarSQL.CompileWith(query, query.CTEs, offset)
 	sqls["aggregate"] = grammarSQL.CompileAggregate(query, query.Aggregate)
 	sqls["columns"] = grammarSQL.CompileColumns(query, query.Columns, offset)
 	sqls["from"] = grammarSQL.CompileFrom(query, query.From, offset)
@@ -50,13 +52,14 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -68,20 +71,75 @@ func (grammarSQL MySQL) CompileSelectOffset(query *dbal.Query, offset *int) stri
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	// Compile the common table expressions
+	if sqls["with"] != "" {
+		sql = fmt.Sprintf("%s %s", sqls["with"], sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
 }
 
 // CompileLock the lock into SQL.
+// The "nowait", "skip locked" and "of" options need MySQL 8.0.1+, MariaDB supports "nowait" and "skip locked" only.
 func (grammarSQL MySQL) CompileLock(query *dbal.Query, lock interface{}) string {
-	lockType, ok := lock.(string)
-	if ok == false {
+	value, ok := dbal.MakeLock(lock)
+	if !ok {
 		return ""
-	} else if lockType == "share" {
+	}
+
+	if value.Mode != "share" && value.Mode != "update" {
+		panic(fmt.Errorf("MySQL does not support the %q lock, the lock should be share or update", value.Mode))
+	}
+
+	if value.IsBasic() && value.Mode == "share" {
 		return "lock in share mode"
-	} else if lockType == "update" {
-		return "for update"
 	}
-	return ""
+
+	if grammarSQL.mariaDBVersion() != nil {
+		if len(value.Tables) > 0 {
+			panic(fmt.Errorf("MariaDB does not support locking the rows of the given tables"))
+		}
+		if value.Mode == "share" {
+			return strings.TrimSpace(fmt.Sprintf("lock in share mode %s", value.Wait))
+		}
+	}
+
+	return grammarSQL.CompileLockClause(query, value)
+}
+
+// CompileWith Compile the common table expressions into SQL. MySQL does not support the materialized hint, it will be ignored.
+func (grammarSQL MySQL) CompileWith(query *dbal.Query, ctes []dbal.CTE, offset *int) string {
+	return grammarSQL.SQL.CompileWith(query, withoutMaterialized(ctes), offset)
+}
+
+// CompileUpdate Compile an update statement into SQL.
+func (grammarSQL MySQL) CompileUpdate(query *dbal.Query, values map[string]interface{}) (string, []interface{}) {
+	return grammarSQL.SQL.CompileUpdate(queryWithoutMaterialized(query), values)
+}
+
+// CompileDelete Compile a delete statement into SQL.
+func (grammarSQL MySQL) CompileDelete(query *dbal.Query) (string, []interface{}) {
+	return grammarSQL.SQL.CompileDelete(queryWithoutMaterialized(query))
+}
+
+// withoutMaterialized remove the materialized hint of the common table expressions
+func withoutMaterialized(ctes []dbal.CTE) []dbal.CTE {
+	new := []dbal.CTE{}
+	for _, cte := range ctes {
+		cte.Materialized = false
+		new = append(new, cte)
+	}
+	return new
+}
+
+// queryWithoutMaterialized returns a shallow copy of the query without the materialized hint
+func queryWithoutMaterialized(query *dbal.Query) *dbal.Query {
+	if len(query.CTEs) == 0 {
+		return query
+	}
+	new := *query
+	new.CTEs = withoutMaterialized(query.CTEs)
+	return &new
 }
@@ -2,6 +2,7 @@ package mysql
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun/dbal"
@@ -88,3 +89,98 @@ func TestCompileUpsertEmptyMySQL(t *testing.T) {
 	assert.Contains(t, sql, "default values")
 	assert.Empty(t, bindings)
 }
+
+func TestCompileWithMaterializedMySQL(t *testing.T) {
+	g := newTestMySQL()
+	offset := 0
+	ctes := []dbal.CTE{{Name: "top_users", SQL: "select * from `users` where `vote` > ?", Offset: 1, Materialized: true}}
+	result := g.CompileWith(&dbal.Query{}, ctes, &offset)
+	assert.Equal(t, "with `top_users` as (select * from `users` where `vote` > ?)", result)
+	assert.Equal(t, 1, offset)
+	assert.True(t, ctes[0].Materialized, "The given ctes should not be changed")
+}
+
+func TestCompileUpdateJSONPathMySQL(t *testing.T) {
+	g := newTestMySQL()
+	q := newMySQLQuery("users")
+	sql, bindings := g.CompileUpdate(q, map[string]interface{}{
+		"options->enabled":      true,
+		"options->dining->meal": "salad",
+	})
+	assert.Equal(t, "update `users` set `options`=json_set(json_set(`options`, '$.\"dining\".\"meal\"', cast(? as json)), '$.\"enabled\"', cast(? as json)) ", sql)
+	assert.Equal(t, []interface{}{`"salad"`, "true"}, bindings)
+}
+
+func TestWrapJSONSelectorMySQL(t *testing.T) {
+	g := newTestMySQL()
+	assert.Equal(t, "json_unquote(json_extract(`options`, '$.\"tags\"[0]')) as `tag`", g.Wrap("options->tags->0 as tag"))
+	assert.Equal(t, "json_unquote(json_extract(`users`.`options`, '$.\"dining\"'))", g.Wrap("users.options->dining"))
+}
+
+func TestCompileFullTextMySQL(t *testing.T) {
+	g := newTestMySQL()
+	q := dbal.NewQuery()
+	q.From = dbal.From{Type: "basic", Name: dbal.NewName("posts")}
+	fulltext := dbal.FullText{Columns: []string{"title", "content"}, Search: "+go -java", Mode: "boolean"}
+	q.Columns = []interface{}{"id", dbal.FullText{Columns: []string{"title"}, Search: "go", Alias: "score"}}
+	q.Wheres = []dbal.Where{{Type: "fulltext", Column: fulltext, Value: fulltext.Search, Boolean: "and", Offset: 1}}
+	q.Orders = []dbal.Order{{Type: "basic", Column: fulltext, Direction: "desc"}}
+
+	offset := 0
+	sql := g.CompileSelectOffset(q, &offset)
+	assert.Equal(t, "select `id`, match (`title`) against (? in natural language mode) as `score` from `posts` where match (`title`, `content`) against (? in boolean mode) order by match (`title`, `content`) against (? in boolean mode) desc", sql)
+	assert.Equal(t, 3, offset)
+}
+
+func TestCompileLockOptionsMySQL(t *testing.T) {
+	g := newTestMySQL()
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users")}
+	assert.Equal(t, "lock in share mode", g.CompileLock(query, "share"))
+	assert.Equal(t, "for update", g.CompileLock(query, dbal.Lock{Mode: "update"}))
+	assert.Equal(t, "for share nowait", g.CompileLock(query, dbal.Lock{Mode: "share", Wait: "nowait"}))
+	assert.Equal(t, "for update of `users` skip locked", g.CompileLock(query, dbal.Lock{Mode: "update", Wait: "skip locked", Tables: []string{"users"}}))
+	assert.Panics(t, func() { g.CompileLock(query, dbal.Lock{Mode: "no key update"}) })
+	assert.Panics(t, func() { g.CompileLock(query, "no key update") })
+	assert.Panics(t, func() { g.CompileLock(query, "foo") })
+	assert.Equal(t, "", g.CompileLock(query, ""))
+}
+
+func TestCompileExplainMySQL(t *testing.T) {
+	g := newTestMySQL()
+	assert.Equal(t, "explain format=json select * from `users`", g.CompileExplain("select * from `users`", dbal.Explain{Analyze: true}))
+}
+
+func TestProcessExplainMySQL(t *testing.T) {
+	g := newTestMySQL()
+	text := `{"query_block": {"select_id": 1, "cost_info": {"query_cost": "3.40"}, "ordering_operation": {"using_filesort": true, "nested_loop": [
+		{"table": {"table_name": "o", "access_type": "ALL", "rows_examined_per_scan": 12, "cost_info": {"read_cost": "1.00", "eval_cost": "1.20"}, "attached_condition": "(o.amount > 100)"}},
+		{"table": {"table_name": "u", "access_type": "eq_ref", "key": "PRIMARY", "rows_examined_per_scan": 1, "cost_info": {"read_cost": "0.50", "eval_cost": "0.10"}}}
+	]}}}`
+	plan, err := g.ProcessExplain([]map[string]interface{}{{"EXPLAIN": text}})
+	assert.Nil(t, err)
+	assert.Equal(t, "query_block", plan.Operation)
+	assert.Equal(t, 3.4, plan.Cost)
+	assert.Equal(t, dbal.PlanSort, plan.Children[0].Type)
+	assert.Equal(t, dbal.PlanJoin, plan.Children[0].Children[0].Type)
+
+	tables := plan.Children[0].Children[0].Children
+	assert.Equal(t, 2, len(tables))
+	assert.Equal(t, dbal.PlanFullScan, tables[0].Type)
+	assert.Equal(t, "o", tables[0].Table)
+	assert.Equal(t, float64(12), tables[0].Rows)
+	assert.Equal(t, "(o.amount > 100)", tables[0].Detail)
+	assert.Equal(t, dbal.PlanIndexScan, tables[1].Type)
+	assert.Equal(t, "PRIMARY", tables[1].Index)
+	assert.Equal(t, []*dbal.Plan{tables[0]}, plan.FullScans())
+}
+
+func TestCompileRawSQLMySQL(t *testing.T) {
+	g := newTestMySQL()
+	created := time.Date(2021, 3, 25, 8, 30, 15, 0, time.UTC)
+	sql := g.CompileRawSQL("select * from `users` where `note` = 'it\\'s ?' and `name` = ? and `created_at` = ? and `score` = ?", []interface{}{`a\'b`, created, 96.5})
+	assert.Equal(t, "select * from `users` where `note` = 'it\\'s ?' and `name` = 'a\\\\\\'b' and `created_at` = '2021-03-25 08:30:15' and `score` = 96.5", sql)
+
+	// The line breaks are escaped
+	assert.Equal(t, `select 'it\'s\nmulti-line\r\n'`, g.CompileRawSQL("select ?", []interface{}{"it's\nmulti-line\r\n"}))
+}
@@ -0,0 +1,116 @@
+package mysql
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
+)
+
+// CompileExplain Compile the statement which gets the query plan of the sql in JSON format. The analyze option is ignored.
+// ( explain format=json select * from `users` )
+func (grammarSQL MySQL) CompileExplain(sql string, option dbal.Explain) string {
+	return fmt.Sprintf("explain format=json %s", sql)
+}
+
+// ProcessExplain Parse the JSON document of the explain statement into the normalized query plan
+func (grammarSQL MySQL) ProcessExplain(rows []map[string]interface{}) (*dbal.Plan, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the query plan is not found")
+	}
+
+	text, err := sql.PlanText(rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	document := map[string]interface{}{}
+	err = json.Unmarshal([]byte(text), &document)
+	if err != nil {
+		return nil, err
+	}
+
+	block, ok := document["query_block"].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("the query plan is not found")
+	}
+	return planNode("query_block", block), nil
+}
+
+// planNode Get the plan of the node and the child nodes. The tables of the query block are the "table" nodes,
+// the other nodes are the operations on them.
+func planNode(name string, node map[string]interface{}) *dbal.Plan {
+	plan := &dbal.Plan{Type: planType(name, node), Operation: name, Children: planChildren(node)}
+	cost, _ := node["cost_info"].(map[string]interface{})
+	if name == "query_block" {
+		plan.Cost = sql.PlanNumber(cost["query_cost"])
+	}
+
+	if name == "table" {
+		plan.Operation = sql.PlanString(node["access_type"])
+		plan.Table = sql.PlanString(node["table_name"])
+		plan.Index = sql.PlanString(node["key"])
+		plan.Rows = sql.PlanNumber(node["rows_examined_per_scan"])
+		if plan.Rows == 0 {
+			plan.Rows = sql.PlanNumber(node["rows"])
+		}
+		plan.Cost = sql.PlanNumber(cost["read_cost"]) + sql.PlanNumber(cost["eval_cost"])
+		plan.Detail = sql.PlanString(node["attached_condition"])
+	}
+	return plan
+}
+
+// planChildren Get the plans of the child nodes in the order of the names, the items of the arrays are grouped under the name.
+func planChildren(node map[string]interface{}) []*dbal.Plan {
+	names := []string{}
+	for name := range node {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	children := []*dbal.Plan{}
+	for _, name := range names {
+		switch value := node[name].(type) {
+		case map[string]interface{}:
+			if name == "cost_info" {
+				continue
+			}
+			children = append(children, planNode(name, value))
+
+		case []interface{}:
+			items := []*dbal.Plan{}
+			for _, item := range value {
+				if item, ok := item.(map[string]interface{}); ok {
+					items = append(items, planChildren(item)...)
+				}
+			}
+			if len(items) > 0 {
+				children = append(children, &dbal.Plan{Type: planType(name, nil), Operation: name, Children: items})
+			}
+		}
+	}
+	return children
+}
+
+// planType Get the normalized type of the node, the "index" access type reads the full index.
+func planType(name string, node map[string]interface{}) string {
+	switch name {
+	case "table":
+		switch sql.PlanString(node["access_type"]) {
+		case "ALL":
+			return dbal.PlanFullScan
+		case "":
+			return dbal.PlanOther
+		}
+		return dbal.PlanIndexScan
+	case "nested_loop":
+		return dbal.PlanJoin
+	case "ordering_operation":
+		return dbal.PlanSort
+	case "grouping_operation", "duplicates_removal":
+		return dbal.PlanAggregate
+	}
+	return dbal.PlanOther
+}
@@ -2,7 +2,11 @@ package mysql
 
 import (
 	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 
+	"github.com/blang/semver/v4"
 	"github.com/go-sql-driver/mysql"
 	_ "github.com/go-sql-driver/mysql" //Load mysql driver
 	"github.com/jmoiron/sqlx"
@@ -16,6 +20,11 @@ type MySQL struct {
 	sql.SQL
 }
 
+// mariaDBVersions the cached MariaDB versions of the connections
+var mariaDBVersions = sync.Map{}
+
+var reMariaDBVersion = regexp.MustCompile(`^([0-9]+\.[0-9]+\.[0-9]+)`)
+
 func init() {
 	dbal.Register("mysql", New())
 }
@@ -51,6 +60,7 @@ func (grammarSQL MySQL) NewWith(db *sqlx.DB, config *dbal.Config, option *dbal.O
 	// Create a new Quoter to avoid race conditions when used concurrently
 	grammarSQL.Quoter = &Quoter{}
 	grammarSQL.Quoter.Bind(db, option.Prefix)
+	grammarSQL.Grammar = &grammarSQL
 	return grammarSQL, nil
 }
 
@@ -66,9 +76,69 @@ func (grammarSQL MySQL) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config, re
 	// Create a new Quoter to avoid race conditions when used concurrently
 	grammarSQL.Quoter = &Quoter{}
 	grammarSQL.Quoter.Bind(write, option.Prefix, read)
+	grammarSQL.Grammar = &grammarSQL
 	return grammarSQL, nil
 }
 
+// WithExecutor Create a new grammar interface, using the given executor (*sqlx.DB or *sqlx.Tx) to run statements.
+func (grammarSQL MySQL) WithExecutor(executor dbal.Executor) dbal.Grammar {
+	grammarSQL.Executor = executor
+	return grammarSQL
+}
+
+// SupportsWindowFunctions Determine if the database supports window functions
+func (grammarSQL MySQL) SupportsWindowFunctions() bool {
+	version, err := sql.CachedVersion(grammarSQL.DB, grammarSQL.GetVersion)
+	if err != nil {
+		return false
+	}
+	return version.GTE(semver.MustParse("8.0.0"))
+}
+
+// SupportsReturning Determine if the database supports the "returning" clause of the given statement. (insert, update, delete, upsert)
+// MySQL does not support it, MariaDB supports "delete ... returning" since 10.0.5 and "insert ... returning" since 10.5.0
+func (grammarSQL MySQL) SupportsReturning(statement string) bool {
+	version := grammarSQL.mariaDBVersion()
+	if version == nil {
+		return false
+	}
+
+	switch statement {
+	case "insert":
+		return version.GTE(semver.MustParse("10.5.0"))
+	case "delete":
+		return version.GTE(semver.MustParse("10.0.5"))
+	}
+	return false
+}
+
+// mariaDBVersion get the version of the MariaDB server, returns nil if the server is not MariaDB. the version will be cached after the first call.
+func (grammarSQL MySQL) mariaDBVersion() *semver.Version {
+	if grammarSQL.DB == nil {
+		return nil
+	}
+
+	if version, has := mariaDBVersions.Load(grammarSQL.DB); has {
+		return version.(*semver.Version)
+	}
+
+	var row string
+	err := grammarSQL.DB.Get(&row, "SELECT VERSION()")
+	if err != nil {
+		return nil
+	}
+
+	var version *semver.Version
+	matches := reMariaDBVersion.FindStringSubmatch(row)
+	if strings.Contains(strings.ToLower(row), "mariadb") && len(matches) == 2 {
+		if ver, err := semver.Make(matches[1]); err == nil {
+			version = &ver
+		}
+	}
+	mariaDBVersions.Store(grammarSQL.DB, version)
+	return version
+}
+
 // OnConnected the event will be triggered when db server was connected
 func (grammarSQL MySQL) OnConnected() error {
 	grammarSQL.DB.Exec("SET GLOBAL sql_mode=`STRICT_TRANS_TABLES,NO_ZERO_IN_DATE,NO_ZERO_DATE,ERROR_FOR_DIVISION_BY_ZERO,NO_ENGINE_SUBSTITUTION`;")
@@ -91,5 +161,16 @@ func New(opts ...sql.Option) dbal.Grammar {
 		my.FlipTypes["TIME"] = "time"
 		my.FlipTypes["TIMESTAMP"] = "timestamp"
 	}
+
+	// The sub-queries are compiled by the dialect grammar, NewWith and NewWithRead bind their copies again.
+	my.Grammar = &my
 	return my
 }
+
+// CompileRawSQL Inline the bindings into the "?" placeholders of the sql, for debugging only. The backslashes of the strings are escapes,
+// the time is the wall clock time without time zone. ( select * from `users` where `id` = 1 )
+func (grammarSQL MySQL) CompileRawSQL(statement string, bindings []interface{}) string {
+	return sql.Interpolate(statement, bindings, "?", true, func(value interface{}) string {
+		return grammarSQL.Literal(value, "2006-01-02 15:04:05.999999", "X'%s'")
+	})
+}
@@ -37,6 +37,7 @@ func (quoter *Quoter) VAL(v interface{}) string {
 	default:
 		input = fmt.Sprintf("%v", v)
 	}
+	input = strings.ReplaceAll(input, "\\", "\\\\")
 	input = strings.ReplaceAll(input, "'", "\\'")
 	input = strings.ReplaceAll(input, "\n", "")
 	input = strings.ReplaceAll(input, "\r", "")
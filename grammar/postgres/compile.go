@@ -7,6 +7,8 @@ import (
 
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
+	"github.com/yaoapp/xun/utils"
 )
 
 // CompileSelect Compile a select query into SQL.
@@ -20,12 +22,13 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 
 	// SQL STMT
 	if query.SQL != "" {
-		if !strings.Contains(query.SQL, "limit") && !strings.Contains(query.SQL, "offset") {
+		stmt := grammarSQL.CompileNamedParameters(query.SQL, offset)
+		if !strings.Contains(stmt, "limit") && !strings.Contains(stmt, "offset") {
 			limit := grammarSQL.CompileLimit(query, query.Limit, offset)
 			offset := grammarSQL.CompileOffset(query, query.Offset)
-			return strings.TrimSpace(fmt.Sprintf("%s %s %s", query.SQL, limit, offset))
+			return strings.TrimSpace(fmt.Sprintf("%s %s %s", stmt, limit, offset))
 		}
-		return query.SQL
+		return stmt
 	}
 
 	if len(query.Unions) > 0 && query.Aggregate.Func != "" {
@@ -45,6 +48,7 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 	// To compile the query, we'll spin through each component of the query and
 	// see if that component exists. If it does we'll just call the compiler
 	// function for the component which is responsible for making the SQL.
+	sqls["with"] = grammarSQL.CompileWith(query, query.CTEs, offset)
 	sqls["aggregate"] = grammarSQL.CompileAggregate(query, query.Aggregate)
 	sqls["columns"] = grammarSQL.CompileColumns(query, query.Columns, offset)
 	sqls["from"] = grammarSQL.CompileFrom(query, query.From, offset)
@@ -52,13 +56,14 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -70,6 +75,11 @@ func (grammarSQL Postgres) CompileSelectOffset(query *dbal.Query, offset *int) s
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	// Compile the common table expressions
+	if sqls["with"] != "" {
+		sql = fmt.Sprintf("%s %s", sqls["with"], sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -92,15 +102,7 @@ func (grammarSQL Postgres) CompileColumns(query *dbal.Query, columns []interface
 		sql = "select distinct"
 	}
 
-	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(columns))
-
-	for _, col := range columns {
-		switch col.(type) {
-		case dbal.Select:
-			*bindingOffset = *bindingOffset + col.(dbal.Select).Offset
-		}
-	}
-
+	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(grammarSQL.CompileColumnExpressions(query, columns, bindingOffset)))
 	return sql
 }
 
@@ -142,6 +144,19 @@ func (grammarSQL Postgres) CompileWheres(query *dbal.Query, wheres []dbal.Where,
 	return fmt.Sprintf("%s %s", conjunction, grammarSQL.RemoveLeadingBoolean(strings.Join(clauses, " ")))
 }
 
+// WhereBasic Compile a basic where clause. The numeric values of a JSON selector are compared as numeric.
+func (grammarSQL Postgres) WhereBasic(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column, ok := where.Column.(string)
+	if !ok || !sql.IsJSONSelector(column) || dbal.IsExpression(where.Value) || !utils.IsNumeric(where.Value) {
+		return grammarSQL.SQL.WhereBasic(query, where, bindingOffset)
+	}
+
+	*bindingOffset = *bindingOffset + where.Offset
+	value := grammarSQL.Parameter(where.Value, *bindingOffset)
+	operator := strings.ReplaceAll(where.Operator, "?", "??")
+	return fmt.Sprintf("(%s)::numeric %s %s", grammarSQL.Wrap(column), operator, value)
+}
+
 // WhereDate Compile a "where date" clause.
 func (grammarSQL Postgres) WhereDate(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
 	value := ""
@@ -215,18 +230,39 @@ func (grammarSQL Postgres) WhereJsoncontains(query *dbal.Query, where dbal.Where
 	}
 	*bindingOffset = *bindingOffset + where.Offset
 	value := grammarSQL.Parameter(where.Value, *bindingOffset)
-	return fmt.Sprintf("%s%s::jsonb @> %s", not, grammarSQL.Wrap(where.Column), value)
+	return fmt.Sprintf("%s%s @> %s", not, grammarSQL.wrapJSONB(where.Column), value)
+}
+
+// WhereJsonlength Compile a "where JSON length" clause.
+func (grammarSQL Postgres) WhereJsonlength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	value := ""
+	if !dbal.IsExpression(where.Value) {
+		*bindingOffset = *bindingOffset + where.Offset
+		value = grammarSQL.Parameter(where.Value, *bindingOffset)
+	} else {
+		value = where.Value.(dbal.Expression).GetValue()
+	}
+	return fmt.Sprintf("jsonb_array_length(%s) %s %s", grammarSQL.wrapJSONB(where.Column), where.Operator, value)
+}
+
+// wrapJSONB Wrap the column or the JSON selector as a jsonb value. "tags"::jsonb, ("options"->'languages')::jsonb
+func (grammarSQL Postgres) wrapJSONB(column interface{}) string {
+	if name, ok := column.(string); ok && sql.IsJSONSelector(name) {
+		return grammarSQL.WrapJSONBooleanSelector(name)
+	}
+	return fmt.Sprintf("%s::jsonb", grammarSQL.Wrap(column))
 }
 
-// CompileLock the lock into SQL.
+// CompileLock the lock into SQL. ( for update, for no key update, for share, for key share )
 func (grammarSQL Postgres) CompileLock(query *dbal.Query, lock interface{}) string {
-	lockType, ok := lock.(string)
-	if ok == false {
+	value, ok := dbal.MakeLock(lock)
+	if !ok {
 		return ""
-	} else if lockType == "share" {
-		return "for share"
-	} else if lockType == "update" {
-		return "for update"
 	}
-	return ""
+
+	switch value.Mode {
+	case "share", "update", "no key update", "key share":
+		return grammarSQL.CompileLockClause(query, value)
+	}
+	panic(fmt.Errorf("PostgreSQL does not support the %q lock, the lock should be share, update, no key update or key share", value.Mode))
 }
@@ -3,6 +3,7 @@ package postgres
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun/dbal"
@@ -637,8 +638,9 @@ func TestCompileLockUpdate(t *testing.T) {
 
 func TestCompileLockInvalid(t *testing.T) {
 	pg := newTestPostgres()
-	result := pg.CompileLock(&dbal.Query{}, "invalid")
-	assert.Equal(t, "", result)
+	assert.Panics(t, func() { pg.CompileLock(&dbal.Query{}, "invalid") })
+	assert.Equal(t, "for no key update", pg.CompileLock(&dbal.Query{}, "no key update"))
+	assert.Equal(t, "", pg.CompileLock(&dbal.Query{}, ""))
 }
 
 func TestCompileLockNonString(t *testing.T) {
@@ -653,6 +655,16 @@ func TestCompileLockNil(t *testing.T) {
 	assert.Equal(t, "", result)
 }
 
+func TestCompileLockOptionsPG(t *testing.T) {
+	pg := newTestPostgres()
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users", "xun_")}
+	assert.Equal(t, "for no key update", pg.CompileLock(query, dbal.Lock{Mode: "no key update"}))
+	assert.Equal(t, "for key share nowait", pg.CompileLock(query, dbal.Lock{Mode: "key share", Wait: "nowait"}))
+	assert.Equal(t, `for update of "xun_users", "o" skip locked`, pg.CompileLock(query, dbal.Lock{Mode: "update", Wait: "skip locked", Tables: []string{"users", "o"}}))
+	assert.Panics(t, func() { pg.CompileLock(query, dbal.Lock{Mode: "exclusive"}) })
+}
+
 // --- CompileInsertOrIgnore ---
 
 func TestCompileInsertOrIgnore(t *testing.T) {
@@ -1219,3 +1231,202 @@ func TestGetOperatorsPG(t *testing.T) {
 	assert.Contains(t, ops, "@>")
 	assert.Contains(t, ops, "ilike")
 }
+
+func TestCompileWithPG(t *testing.T) {
+	pg := newTestPostgres()
+	offset := 0
+	ctes := []dbal.CTE{
+		{Name: "top_users", SQL: `select * from "users" where "vote" > $1`, Offset: 1, Materialized: true},
+		{Name: "tree", Columns: []string{"id", "parent_id"}, Recursive: true, SQL: `select "id", "parent_id" from "nodes"`},
+	}
+
+	result := pg.CompileWith(&dbal.Query{}, ctes, &offset)
+	assert.Equal(t, `with recursive "top_users" as materialized (select * from "users" where "vote" > $1), "tree" ("id", "parent_id") as (select "id", "parent_id" from "nodes")`, result)
+	assert.Equal(t, 1, offset)
+}
+
+func TestCompileWithQueryPG(t *testing.T) {
+	pg := newTestPostgres()
+	hot := dbal.NewQuery()
+	hot.From = dbal.From{Type: "basic", Name: dbal.NewName("posts")}
+	hot.Columns = []interface{}{"user_id"}
+	hot.Wheres = []dbal.Where{{Type: "basic", Column: "vote", Operator: ">", Value: 10, Boolean: "and", Offset: 1}}
+	hot.Bindings["where"] = []interface{}{10}
+
+	// update with the ctid sub-query
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users")}
+	query.CTEs = []dbal.CTE{{Name: "hot", Query: hot}}
+	query.Bindings["with"] = []interface{}{10}
+	query.Wheres = []dbal.Where{{Type: "basic", Column: "score", Operator: ">", Value: 3, Boolean: "and", Offset: 1}}
+	query.Bindings["where"] = []interface{}{3}
+	query.Limit = 1
+	sql, bindings := pg.CompileUpdate(query, map[string]interface{}{"name": "Ken"})
+	assert.Equal(t, `update "users" set "name"=$1 where "ctid" in (with "hot" as (select "user_id" from "posts" where "vote" > $2) select "ctid" from "users" where "score" > $3 limit 1)`, sql)
+	assert.Equal(t, []interface{}{"Ken", 10, 3}, bindings)
+
+	// the common table expression of a sub-query
+	sub := dbal.NewQuery()
+	sub.From = dbal.From{Type: "basic", Name: dbal.NewName("hot")}
+	sub.Columns = []interface{}{"user_id"}
+	sub.CTEs = []dbal.CTE{{Name: "hot", Query: hot}}
+	sub.Bindings["with"] = []interface{}{10}
+	query = dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users")}
+	query.Wheres = []dbal.Where{
+		{Type: "basic", Column: "score", Operator: ">", Value: 3, Boolean: "and", Offset: 1},
+		{Type: "exists", Query: sub, Boolean: "and"},
+		{Type: "basic", Column: "name", Operator: "=", Value: "Ken", Boolean: "and", Offset: 1},
+	}
+	offset := 0
+	sql = pg.CompileSelectOffset(query, &offset)
+	assert.Equal(t, `select * from "users" where "score" > $1 and exists (with "hot" as (select "user_id" from "posts" where "vote" > $2) select "user_id" from "hot") and "name" = $3`, sql)
+	assert.Equal(t, 3, offset)
+}
+
+func TestCompileSubQueriesPG(t *testing.T) {
+	pg := newTestPostgres()
+	sub := func(table string, column string) *dbal.Query {
+		query := dbal.NewQuery()
+		query.From = dbal.From{Type: "basic", Name: dbal.NewName(table)}
+		query.Columns = []interface{}{"id"}
+		query.Wheres = []dbal.Where{{Type: "basic", Column: column, Operator: "=", Value: 1, Boolean: "and", Offset: 1}}
+		return query
+	}
+
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "sub", Name: sub("posts", "a"), Alias: "p"}
+	query.Columns = []interface{}{"id", dbal.Select{Type: "sub", Name: sub("users", "b"), Alias: "u"}}
+	query.Wheres = []dbal.Where{
+		{Type: "basic", Column: sub("votes", "c"), Operator: ">", Value: 2, Boolean: "and", Offset: 1},
+		{Type: "in", Column: "id", ValuesIn: sub("tags", "d"), Boolean: "and"},
+	}
+	query.Orders = []dbal.Order{{Type: "basic", Column: sub("ranks", "e"), Direction: "desc"}}
+
+	offset := 0
+	sql := pg.CompileSelectOffset(query, &offset)
+	assert.Equal(t, `select "id", (select "id" from "users" where "b" = $1) as "u" from (select "id" from "posts" where "a" = $2) as "p" where (select "id" from "votes" where "c" = $3) > $4 and "id" in (select "id" from "tags" where "d" = $5) order by (select "id" from "ranks" where "e" = $6) desc`, sql)
+	assert.Equal(t, 6, offset)
+}
+
+func TestCompileWindowPG(t *testing.T) {
+	pg := newTestPostgres()
+	offset := 0
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users", "xun_")}
+	query.Columns = []interface{}{
+		"id",
+		dbal.Over("row_number").PartitionBy("users.status").OrderByDesc("vote").Rows("between unbounded preceding and current row").As("rn").Copy(),
+		dbal.Over("sum", "vote").Window("w").As("total").Copy(),
+	}
+	query.Windows = []dbal.Window{dbal.NewWindow().Window("w").PartitionBy("status").Copy()}
+	query.Orders = []dbal.Order{{Type: "basic", Column: dbal.Over("rank").Window("w").OrderBy("id").As("r").Copy(), Direction: "desc"}}
+
+	sql := pg.CompileSelectOffset(query, &offset)
+	assert.Equal(t, `select "id", row_number() over (partition by "xun_users"."status" order by "vote" desc rows between unbounded preceding and current row) as "rn", sum("vote") over "w" as "total" from "xun_users" window "w" as (partition by "status") order by rank() over ("w" order by "id" asc) desc`, sql)
+}
+
+func TestCompileJSONPathPG(t *testing.T) {
+	pg := newTestPostgres()
+	assert.Equal(t, `"options"->'tags'->>0 as "tag"`, pg.Wrap("options->tags->0 as tag"))
+
+	q := dbal.NewQuery()
+	q.From = dbal.From{Type: "basic", Name: dbal.NewName("users")}
+	sql, bindings := pg.CompileUpdate(q, map[string]interface{}{"options->dining->meal": "salad", "name": "Ken"})
+	assert.Equal(t, `update "users" set "name"=$1, "options"=jsonb_set("options"::jsonb, '{"dining","meal"}', $2) `, sql)
+	assert.Equal(t, []interface{}{"Ken", `"salad"`}, bindings)
+}
+
+func TestCompileFullTextPG(t *testing.T) {
+	pg := newTestPostgres()
+	offset := 0
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("posts")}
+	fulltext := dbal.FullText{Columns: []string{"title", "content"}, Search: "go & sql", Mode: "boolean", Language: "simple"}
+	query.Columns = []interface{}{"id", dbal.FullText{Columns: []string{"title"}, Search: "go", Alias: "score"}}
+	query.Wheres = []dbal.Where{{Type: "fulltext", Column: fulltext, Value: fulltext.Search, Boolean: "and", Offset: 1}}
+	query.Orders = []dbal.Order{{Type: "basic", Column: fulltext, Direction: "desc"}}
+
+	sql := pg.CompileSelectOffset(query, &offset)
+	vector := `(to_tsvector('simple', "title") || to_tsvector('simple', "content"))`
+	assert.Equal(t, `select "id", ts_rank(to_tsvector('english', "title"), websearch_to_tsquery('english', $1)) as "score" from "posts" where `+vector+` @@ to_tsquery('simple', $2) order by ts_rank(`+vector+`, to_tsquery('simple', $3)) desc`, sql)
+}
+
+func TestCompileReturningPG(t *testing.T) {
+	pg := newTestPostgres()
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users")}
+	assert.Equal(t, `returning *`, pg.CompileReturning(query, nil))
+	assert.Equal(t, `returning "id", "name" as "n"`, pg.CompileReturning(query, []interface{}{"id", "name as n"}))
+	assert.True(t, pg.SupportsReturning("update"))
+}
+
+func TestCompileUpdateBatchPG(t *testing.T) {
+	pg := newTestPostgres()
+	query := dbal.NewQuery()
+	query.From = dbal.From{Type: "basic", Name: dbal.NewName("users", "xun_")}
+	query.Wheres = []dbal.Where{{Type: "basic", Column: "status", Operator: "=", Value: "DONE", Boolean: "and", Offset: 1}}
+	query.Bindings["where"] = []interface{}{"DONE"}
+
+	sql, bindings := pg.CompileUpdateBatch(query, "id", []interface{}{"id", "vote"}, [][]interface{}{{1, 10}, {2, nil}})
+	assert.Equal(t, `update "xun_users" set "vote"="xun_batch"."xun_1" from (select (null::"xun_users")."id", (null::"xun_users")."vote" union all values ($1,$2),($3,NULL)) as "xun_batch" ("xun_0", "xun_1") where "xun_users"."id"="xun_batch"."xun_0" and ("status" = $4)`, sql)
+	assert.Equal(t, []interface{}{1, 10, 2, "DONE"}, bindings)
+}
+
+func TestCompileExplainPG(t *testing.T) {
+	pg := newTestPostgres()
+	assert.Equal(t, `explain (format json) select * from "users"`, pg.CompileExplain(`select * from "users"`, dbal.Explain{}))
+	assert.Equal(t, `explain (format json, analyze) select * from "users"`, pg.CompileExplain(`select * from "users"`, dbal.Explain{Analyze: true}))
+}
+
+func TestProcessExplainPG(t *testing.T) {
+	pg := newTestPostgres()
+	text := `[{"Plan": {"Node Type": "Hash Join", "Total Cost": 36.5, "Plan Rows": 10, "Actual Rows": 8, "Actual Total Time": 0.25, "Hash Cond": "(o.user_id = u.id)", "Plans": [
+		{"Node Type": "Seq Scan", "Relation Name": "orders", "Total Cost": 22.7, "Plan Rows": 1270, "Filter": "(amount > 100)"},
+		{"Node Type": "Hash", "Total Cost": 8.3, "Plan Rows": 1, "Plans": [
+			{"Node Type": "Index Scan", "Relation Name": "users", "Index Name": "users_pkey", "Total Cost": 8.3, "Plan Rows": 1, "Index Cond": "(id = 1)"}
+		]}
+	]}}]`
+	plan, err := pg.ProcessExplain([]map[string]interface{}{{"QUERY PLAN": []byte(text)}})
+	assert.Nil(t, err)
+	assert.Equal(t, dbal.PlanJoin, plan.Type)
+	assert.Equal(t, "Hash Join", plan.Operation)
+	assert.Equal(t, 36.5, plan.Cost)
+	assert.Equal(t, float64(8), plan.ActualRows)
+	assert.Equal(t, "(o.user_id = u.id)", plan.Detail)
+	assert.Equal(t, 2, len(plan.Children))
+
+	scans := plan.FullScans()
+	assert.Equal(t, 1, len(scans))
+	assert.Equal(t, "orders", scans[0].Table)
+	assert.Equal(t, float64(1270), scans[0].Rows)
+
+	index := plan.Children[1].Children[0]
+	assert.Equal(t, dbal.PlanIndexScan, index.Type)
+	assert.Equal(t, "users", index.Table)
+	assert.Equal(t, "users_pkey", index.Index)
+
+	_, err = pg.ProcessExplain([]map[string]interface{}{})
+	assert.NotNil(t, err)
+}
+
+func TestCompileRawSQLPG(t *testing.T) {
+	pg := newTestPostgres()
+	created := time.Date(2021, 3, 25, 8, 30, 15, 0, time.UTC)
+	sql := pg.CompileRawSQL(
+		`select * from "users" where "name" = $2 and "tags" ? 'vip' and "hash" = $3 and "created_at" = $1 and "note" = '$1' and "admin" = $4`,
+		[]interface{}{created, "it's", []byte{0xde, 0xad}, false},
+	)
+	assert.Equal(t, `select * from "users" where "name" = 'it''s' and "tags" ? 'vip' and "hash" = '\xdead' and "created_at" = '2021-03-25 08:30:15+00:00' and "note" = '$1' and "admin" = false`, sql)
+
+	// The line breaks are kept in the standard strings
+	assert.Equal(t, "select 'it''s\nmulti-line\r\n\\n'", pg.CompileRawSQL("select $1", []interface{}{"it's\nmulti-line\r\n\\n"}))
+}
+
+func TestCompileNamedParametersPG(t *testing.T) {
+	pg := newTestPostgres()
+	offset := 2
+	sql := pg.CompileNamedParameters("vote > "+dbal.NamedParameter+" and status = "+dbal.NamedParameter, &offset)
+	assert.Equal(t, "vote > $3 and status = $4", sql)
+	assert.Equal(t, 4, offset)
+}
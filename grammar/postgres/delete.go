@@ -13,9 +13,17 @@ func (grammarSQL Postgres) CompileDelete(query *dbal.Query) (string, []interface
 		offset := 0
 		bindings := []interface{}{}
 		table := grammarSQL.WrapTable(query.From)
+		with := grammarSQL.CompileWith(query, query.CTEs, &offset)
+		if len(query.CTEs) > 0 {
+			bindings = append(bindings, query.GetBindings("with")...)
+		}
 		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
 		bindings = append(bindings, query.GetBindings("where")...)
-		return fmt.Sprintf("delete from %s %s", table, wheres), bindings
+		sql := fmt.Sprintf("delete from %s %s", table, wheres)
+		if with != "" {
+			sql = fmt.Sprintf("%s %s", with, sql)
+		}
+		return sql, bindings
 	}
 
 	offset := 0
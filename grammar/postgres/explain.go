@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
+)
+
+// CompileExplain Compile the statement which gets the query plan of the sql in JSON format.
+// The query is executed if the analyze option is given. ( explain (format json, analyze) select * from "users" )
+func (grammarSQL Postgres) CompileExplain(sql string, option dbal.Explain) string {
+	if option.Analyze {
+		return fmt.Sprintf("explain (format json, analyze) %s", sql)
+	}
+	return fmt.Sprintf("explain (format json) %s", sql)
+}
+
+// ProcessExplain Parse the "QUERY PLAN" JSON document into the normalized query plan
+func (grammarSQL Postgres) ProcessExplain(rows []map[string]interface{}) (*dbal.Plan, error) {
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("the query plan is not found")
+	}
+
+	text, err := sql.PlanText(rows[0])
+	if err != nil {
+		return nil, err
+	}
+
+	documents := []struct {
+		Plan map[string]interface{} `json:"Plan"`
+	}{}
+	err = json.Unmarshal([]byte(text), &documents)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(documents) == 0 || documents[0].Plan == nil {
+		return nil, fmt.Errorf("the query plan is not found")
+	}
+	return planNode(documents[0].Plan), nil
+}
+
+// planNode Get the plan of the node and the child nodes
+func planNode(node map[string]interface{}) *dbal.Plan {
+	operation := sql.PlanString(node["Node Type"])
+	plan := &dbal.Plan{
+		Type:       planType(operation),
+		Operation:  operation,
+		Table:      sql.PlanString(node["Relation Name"]),
+		Index:      sql.PlanString(node["Index Name"]),
+		Rows:       sql.PlanNumber(node["Plan Rows"]),
+		Cost:       sql.PlanNumber(node["Total Cost"]),
+		ActualRows: sql.PlanNumber(node["Actual Rows"]),
+		ActualTime: sql.PlanNumber(node["Actual Total Time"]),
+		Children:   []*dbal.Plan{},
+	}
+
+	for _, name := range []string{"Index Cond", "Hash Cond", "Merge Cond", "Join Filter", "Filter"} {
+		if detail := sql.PlanString(node[name]); detail != "" {
+			plan.Detail = detail
+			break
+		}
+	}
+
+	children, _ := node["Plans"].([]interface{})
+	for _, child := range children {
+		if child, ok := child.(map[string]interface{}); ok {
+			plan.Children = append(plan.Children, planNode(child))
+		}
+	}
+	return plan
+}
+
+// planType Get the normalized type of the node
+func planType(operation string) string {
+	switch operation {
+	case "Seq Scan":
+		return dbal.PlanFullScan
+	case "Index Scan", "Index Only Scan", "Bitmap Index Scan", "Bitmap Heap Scan":
+		return dbal.PlanIndexScan
+	case "Nested Loop", "Hash Join", "Merge Join":
+		return dbal.PlanJoin
+	case "Sort", "Incremental Sort":
+		return dbal.PlanSort
+	case "Group":
+		return dbal.PlanAggregate
+	}
+
+	if strings.HasSuffix(operation, "Aggregate") {
+		return dbal.PlanAggregate
+	}
+	return dbal.PlanOther
+}
@@ -72,6 +72,7 @@ func (grammarSQL Postgres) NewWith(db *sqlx.DB, config *dbal.Config, option *dba
 	// Create a new Quoter to avoid race conditions when used concurrently
 	grammarSQL.Quoter = &Quoter{}
 	grammarSQL.Quoter.Bind(db, option.Prefix)
+	grammarSQL.Grammar = &grammarSQL
 	return grammarSQL, nil
 }
 
@@ -87,9 +88,26 @@ func (grammarSQL Postgres) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config,
 	// Create a new Quoter to avoid race conditions when used concurrently
 	grammarSQL.Quoter = &Quoter{}
 	grammarSQL.Quoter.Bind(write, option.Prefix, read)
+	grammarSQL.Grammar = &grammarSQL
 	return grammarSQL, nil
 }
 
+// WithExecutor Create a new grammar interface, using the given executor (*sqlx.DB or *sqlx.Tx) to run statements.
+func (grammarSQL Postgres) WithExecutor(executor dbal.Executor) dbal.Grammar {
+	grammarSQL.Executor = executor
+	return grammarSQL
+}
+
+// SupportsWindowFunctions Determine if the database supports window functions
+func (grammarSQL Postgres) SupportsWindowFunctions() bool {
+	return true
+}
+
+// SupportsReturning Determine if the database supports the "returning" clause of the given statement. (insert, update, delete, upsert)
+func (grammarSQL Postgres) SupportsReturning(statement string) bool {
+	return true
+}
+
 // New Create a new mysql grammar inteface
 func New(opts ...sql.Option) dbal.Grammar {
 	pg := Postgres{
@@ -137,6 +155,8 @@ func New(opts ...sql.Option) dbal.Grammar {
 		pg.FlipTypes["SMALLINT"] = "smallInteger"
 	}
 
+	// The sub-queries are compiled by the dialect grammar, NewWith and NewWithRead bind their copies again.
+	pg.Grammar = &pg
 	return pg
 }
 
@@ -150,3 +170,11 @@ func (grammarSQL Postgres) GetOperators() []string {
 		"is distinct from", "is not distinct from",
 	}
 }
+
+// CompileRawSQL Inline the bindings into the "$n" placeholders of the sql, for debugging only. The byte slices are the bytea
+// hex strings, the time is formatted with the time zone. ( select * from "users" where "id" = 1 )
+func (grammarSQL Postgres) CompileRawSQL(statement string, bindings []interface{}) string {
+	return sql.Interpolate(statement, bindings, "$", false, func(value interface{}) string {
+		return grammarSQL.Literal(value, "2006-01-02 15:04:05.999999-07:00", `'\x%s'`)
+	})
+}
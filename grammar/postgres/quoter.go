@@ -73,6 +73,9 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	if value == "*" {
 		return "*"
 	}
+	if sql.IsJSONSelector(value) {
+		return quoter.WrapJSONSelector(value)
+	}
 	if strings.Contains(value, ".") {
 		arrs := strings.Split(value, ".")
 		table := arrs[0]
@@ -87,6 +90,56 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	return fmt.Sprintf("%s", quoter.ID(name.Fullname()))
 }
 
+// WrapJSONSelector Wrap the given JSON selector. "options"->'dining'->>'meal'
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	column, path, alias := sql.JSONSelector(value)
+	selector := quoter.WrapAliasedValue(column)
+	if len(path) > 0 {
+		last := path[len(path)-1]
+		selector = fmt.Sprintf("%s->>%s", quoter.wrapJSONPath(selector, path[:len(path)-1]), quoter.wrapJSONSegment(last))
+	}
+	if alias != "" {
+		return fmt.Sprintf("%s as %s", selector, quoter.ID(alias))
+	}
+	return selector
+}
+
+// WrapJSONBooleanSelector Wrap the given JSON selector for boolean values. ("options"->'enabled')::jsonb
+func (quoter *Quoter) WrapJSONBooleanSelector(value string) string {
+	column, path, _ := sql.JSONSelector(value)
+	return fmt.Sprintf("(%s)::jsonb", quoter.wrapJSONPath(quoter.WrapAliasedValue(column), path))
+}
+
+// WrapJSONBooleanValue Wrap the given JSON boolean value. 'true'::jsonb
+func (quoter *Quoter) WrapJSONBooleanValue(value string) string {
+	return fmt.Sprintf("'%s'::jsonb", value)
+}
+
+// WrapJSONSet Wrap the partial update of the JSON column. jsonb_set("options"::jsonb, '{"enabled"}', $1)
+func (quoter *Quoter) WrapJSONSet(target string, path []string, parameter string) string {
+	segments := []string{}
+	for _, segment := range path {
+		segments = append(segments, fmt.Sprintf(`"%s"`, segment))
+	}
+	return fmt.Sprintf("jsonb_set(%s::jsonb, '{%s}', %s)", target, strings.Join(segments, ","), parameter)
+}
+
+// wrapJSONPath Wrap the path segments with the -> operator. "options"->'dining'->0
+func (quoter *Quoter) wrapJSONPath(selector string, path []string) string {
+	for _, segment := range path {
+		selector = fmt.Sprintf("%s->%s", selector, quoter.wrapJSONSegment(segment))
+	}
+	return selector
+}
+
+// wrapJSONSegment Wrap a segment of the JSON path, the array indexes are not quoted.
+func (quoter *Quoter) wrapJSONSegment(segment string) string {
+	if sql.IsJSONIndex(segment) {
+		return segment
+	}
+	return fmt.Sprintf("'%s'", segment)
+}
+
 // WrapTable Wrap a table in keyword identifiers.
 func (quoter *Quoter) WrapTable(value interface{}) string {
 	switch value.(type) {
@@ -139,3 +192,39 @@ func (quoter *Quoter) Columnize(columns []interface{}) string {
 	}
 	return strings.Join(wrapColumns, ", ")
 }
+
+// WrapFullText Wrap the full-text search. (to_tsvector('english', "title") || to_tsvector('english', "content")) @@ websearch_to_tsquery('english', $1)
+func (quoter *Quoter) WrapFullText(fulltext dbal.FullText, table string, parameter string) string {
+	vector, query := quoter.wrapTextSearch(fulltext, parameter)
+	return fmt.Sprintf("%s @@ %s", vector, query)
+}
+
+// WrapFullTextScore Wrap the relevance score of the full-text search. ts_rank(to_tsvector('english', "title"), websearch_to_tsquery('english', $1))
+func (quoter *Quoter) WrapFullTextScore(fulltext dbal.FullText, table string, parameter string) string {
+	vector, query := quoter.wrapTextSearch(fulltext, parameter)
+	return fmt.Sprintf("ts_rank(%s, %s)", vector, query)
+}
+
+// wrapTextSearch Wrap the text search vector of the columns and the text search query of the parameter.
+func (quoter *Quoter) wrapTextSearch(fulltext dbal.FullText, parameter string) (string, string) {
+	language := fulltext.Language
+	if language == "" {
+		language = "english"
+	}
+	language = quoter.VAL(language)
+
+	vectors := []string{}
+	for _, column := range fulltext.Columns {
+		vectors = append(vectors, fmt.Sprintf("to_tsvector(%s, %s)", language, quoter.Wrap(column)))
+	}
+	vector := strings.Join(vectors, " || ")
+	if len(vectors) > 1 {
+		vector = fmt.Sprintf("(%s)", vector)
+	}
+
+	function := "websearch_to_tsquery"
+	if fulltext.Mode == "boolean" {
+		function = "to_tsquery"
+	}
+	return vector, fmt.Sprintf("%s(%s, %s)", function, language, parameter)
+}
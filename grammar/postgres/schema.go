@@ -16,7 +16,7 @@ func (grammarSQL Postgres) GetVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.GetExecutor().Select(&rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -72,7 +72,7 @@ func (grammarSQL Postgres) GetTables() ([]string, error) {
 	)
 	defer log.Debug("%s", sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.GetExecutor().Select(&tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -89,7 +89,7 @@ func (grammarSQL Postgres) TableExists(name string) (bool, error) {
 	)
 	defer log.Debug("%s", sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.GetExecutor().Select(&rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -112,7 +112,7 @@ func (grammarSQL Postgres) CreateType(table *dbal.Table, types map[string][]stri
 	END $$;
 	`, table.SchemaName, name, typ)
 		defer log.Debug("%s", typeSQL)
-		_, err := grammarSQL.DB.Exec(typeSQL)
+		_, err := grammarSQL.GetExecutor().Exec(typeSQL)
 		if err != nil {
 			return err
 		}
@@ -178,7 +178,7 @@ func (grammarSQL Postgres) CreateTable(table *dbal.Table, options ...dbal.Create
 
 	// Create table
 	defer log.Debug("%s", sql)
-	_, err = grammarSQL.DB.Exec(sql)
+	_, err = grammarSQL.GetExecutor().Exec(sql)
 	if err != nil {
 		return err
 	}
@@ -242,7 +242,7 @@ func (grammarSQL Postgres) createTableCreateIndex(table *dbal.Table, indexes []*
 	if len(indexStmts) > 0 {
 		sql := strings.Join(indexStmts, ";\n")
 		defer log.Debug("%s", sql)
-		_, err := grammarSQL.DB.Exec(sql)
+		_, err := grammarSQL.GetExecutor().Exec(sql)
 		return err
 	}
 	return nil
@@ -252,7 +252,7 @@ func (grammarSQL Postgres) createTableAddComment(table *dbal.Table, commentStmts
 	if len(commentStmts) > 0 {
 		sql := strings.Join(commentStmts, ";\n")
 		defer log.Debug("%s", sql)
-		_, err := grammarSQL.DB.Exec(sql)
+		_, err := grammarSQL.GetExecutor().Exec(sql)
 		return err
 	}
 	return nil
@@ -262,7 +262,7 @@ func (grammarSQL Postgres) createTableAddComment(table *dbal.Table, commentStmts
 func (grammarSQL Postgres) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	return err
 }
 
@@ -574,7 +574,7 @@ func (grammarSQL Postgres) alterTableRenameIndex(table *dbal.Table, command *dba
 
 // ExecSQL execute sql then update table structure
 func (grammarSQL Postgres) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	if err != nil {
 		return err
 	}
@@ -712,7 +712,7 @@ func (grammarSQL Postgres) GetIndexListing(dbName string, tableName string) ([]*
 	)
 	defer log.Debug("%s", sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.GetExecutor().Select(&indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -777,7 +777,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 	)
 	defer log.Debug("%s", sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.GetExecutor().Select(&columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -805,7 +805,7 @@ func (grammarSQL Postgres) GetColumnListing(dbName string, tableName string) ([]
 				column.Type = "enum"
 				if _, has := enumOptions[column.TypeName]; !has {
 					optionRange := []string{}
-					err := grammarSQL.DB.Select(&optionRange, fmt.Sprintf("select enum_range(null::%s.%s)", dbName, column.TypeName))
+					err := grammarSQL.GetExecutor().Select(&optionRange, fmt.Sprintf("select enum_range(null::%s.%s)", dbName, column.TypeName))
 					if err != nil {
 						return nil, err
 					}
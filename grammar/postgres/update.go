@@ -9,6 +9,7 @@ import (
 	"github.com/yaoapp/kun/log"
 	"github.com/yaoapp/xun"
 	"github.com/yaoapp/xun/dbal"
+	gsql "github.com/yaoapp/xun/grammar/sql"
 	"github.com/yaoapp/xun/utils"
 )
 
@@ -27,7 +28,7 @@ func (grammarSQL Postgres) Upsert(query *dbal.Query, values []xun.R, uniqueBy []
 
 	sql, bindings := grammarSQL.CompileUpsert(query, columns, insertValues, uniqueBy, updateValues)
 	defer log.Debug("%s", sql)
-	return grammarSQL.DB.Exec(sql, bindings...)
+	return grammarSQL.GetExecutor().Exec(sql, bindings...)
 }
 
 // CompileUpsert Upsert new records or update the existing ones.
@@ -71,11 +72,19 @@ func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]in
 		offset := 0
 		bindings := []interface{}{}
 		table := grammarSQL.WrapTable(query.From)
+		with := grammarSQL.CompileWith(query, query.CTEs, &offset)
+		if len(query.CTEs) > 0 {
+			bindings = append(bindings, query.GetBindings("with")...)
+		}
 		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
 		bindings = append(bindings, columnsBindings...)
 		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
 		bindings = append(bindings, query.GetBindings("where")...)
-		return fmt.Sprintf("update %s set %s %s", table, columns, wheres), bindings
+		sql := fmt.Sprintf("update %s set %s %s", table, columns, wheres)
+		if with != "" {
+			sql = fmt.Sprintf("%s %s", with, sql)
+		}
+		return sql, bindings
 	}
 
 	offset := 0
@@ -99,3 +108,56 @@ func (grammarSQL Postgres) CompileUpdate(query *dbal.Query, values map[string]in
 
 	return sql, bindings
 }
+
+// CompileUpdateBatch Compile a batch update statement into SQL, each row is updated with its own values and matched by the key column.
+// The values are typed by the columns of the table with a null row: (null::"users")."name"
+// update "users" set "name"="xun_batch"."xun_1" from (select (null::"users")."id", (null::"users")."name" union all values ($1,$2),($3,$4)) as "xun_batch" ("xun_0", "xun_1") where "users"."id"="xun_batch"."xun_0"
+func (grammarSQL Postgres) CompileUpdateBatch(query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	offset := 0
+	bindings := []interface{}{}
+	name, ok := query.From.Name.(dbal.Name)
+	if !ok {
+		panic(fmt.Errorf("the batch update should be run on a table"))
+	}
+
+	table := grammarSQL.ID(name.Fullname())
+	target := table
+	if name.Alias != "" {
+		target = grammarSQL.ID(name.Alias)
+	}
+
+	index := gsql.BatchKeyIndex(key, columns)
+	typed := []string{}
+	aliases := []string{}
+	sets := []string{}
+	for i, column := range columns {
+		alias := grammarSQL.ID(fmt.Sprintf("xun_%d", i))
+		typed = append(typed, fmt.Sprintf("(null::%s).%s", table, grammarSQL.Wrap(column)))
+		aliases = append(aliases, alias)
+		if i != index {
+			sets = append(sets, fmt.Sprintf("%s=%s.%s", grammarSQL.Wrap(column), grammarSQL.ID("xun_batch"), alias))
+		}
+	}
+
+	rows := []string{}
+	for _, value := range values {
+		rows = append(rows, fmt.Sprintf("(%s)", grammarSQL.Parameterize(value, offset)))
+		for _, v := range value {
+			if !dbal.IsExpression(v) && !utils.IsNil(v) {
+				bindings = append(bindings, v)
+				offset++
+			}
+		}
+	}
+
+	keys := fmt.Sprintf("%s.%s=%s.%s", target, grammarSQL.Wrap(key), grammarSQL.ID("xun_batch"), aliases[index])
+	wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+	bindings = append(bindings, query.GetBindings("where")...)
+
+	return fmt.Sprintf(
+		"update %s set %s from (select %s union all values %s) as %s (%s) %s",
+		grammarSQL.WrapTable(query.From), strings.Join(sets, ", "),
+		strings.Join(typed, ", "), strings.Join(rows, ","), grammarSQL.ID("xun_batch"), strings.Join(aliases, ", "),
+		gsql.BatchWheres(keys, wheres),
+	), bindings
+}
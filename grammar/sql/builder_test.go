@@ -0,0 +1,18 @@
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+)
+
+func TestSQLAddColumnQuotedValues(t *testing.T) {
+	grammar := NewSQL(&Quoter{})
+	length := 50
+	comment := `it's C:\temp`
+	column := &dbal.Column{Name: "note", Type: "string", Length: &length, Default: `it's C:\temp`, Comment: &comment}
+	sql := grammar.SQLAddColumn(column)
+	assert.Contains(t, sql, `DEFAULT 'it\'s C:\\temp'`)
+	assert.Contains(t, sql, `COMMENT 'it\'s C:\\temp'`)
+}
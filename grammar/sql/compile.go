@@ -21,13 +21,13 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 
 	// SQL STMT
 	if query.SQL != "" {
-
-		if !strings.Contains(query.SQL, "limit") && !strings.Contains(query.SQL, "offset") {
+		stmt := grammarSQL.CompileNamedParameters(query.SQL, offset)
+		if !strings.Contains(stmt, "limit") && !strings.Contains(stmt, "offset") {
 			limit := grammarSQL.CompileLimit(query, query.Limit, offset)
 			offset := grammarSQL.CompileOffset(query, query.Offset)
-			return strings.TrimSpace(fmt.Sprintf("%s %s %s", query.SQL, limit, offset))
+			return strings.TrimSpace(fmt.Sprintf("%s %s %s", stmt, limit, offset))
 		}
-		return query.SQL
+		return stmt
 	}
 
 	if len(query.Unions) > 0 && query.Aggregate.Func != "" {
@@ -47,6 +47,7 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 	// To compile the query, we'll spin through each component of the query and
 	// see if that component exists. If it does we'll just call the compiler
 	// function for the component which is responsible for making the SQL.
+	sqls["with"] = grammarSQL.CompileWith(query, query.CTEs, offset)
 	sqls["aggregate"] = grammarSQL.CompileAggregate(query, query.Aggregate)
 	sqls["columns"] = grammarSQL.CompileColumns(query, query.Columns, offset)
 	sqls["from"] = grammarSQL.CompileFrom(query, query.From, offset)
@@ -54,13 +55,14 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -72,6 +74,11 @@ func (grammarSQL SQL) CompileSelectOffset(query *dbal.Query, offset *int) string
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	// Compile the common table expressions
+	if sqls["with"] != "" {
+		sql = fmt.Sprintf("%s %s", sqls["with"], sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -153,7 +160,7 @@ func (grammarSQL SQL) CompileJoins(query *dbal.Query, joins []dbal.Join, offset
 		// Join Raw inteface()
 		if join.Type == "raw" {
 			if raw, ok := join.SQL.(string); ok {
-				sql = strings.Trim(sql+" "+raw, " ")
+				sql = strings.Trim(sql+" "+grammarSQL.CompileNamedParameters(raw, offset), " ")
 			}
 			continue
 		}
@@ -180,11 +187,15 @@ func (grammarSQL SQL) CompileJoins(query *dbal.Query, joins []dbal.Join, offset
 	return sql
 }
 
-// CompileSub Parse the subquery into SQL and bindings.
+// CompileSub Parse the subquery into SQL and bindings. The query is compiled by the grammar of the
+// database if it is given, the placeholders are numbered from the offset.
 func (grammarSQL SQL) CompileSub(sub interface{}, offset *int) string {
 	switch sub.(type) {
 	case *dbal.Query:
 		query := sub.(*dbal.Query)
+		if grammarSQL.Grammar != nil {
+			return grammarSQL.Grammar.CompileSelectOffset(query, offset)
+		}
 		return grammarSQL.CompileSelectOffset(query, offset)
 	case dbal.Expression:
 		return sub.(dbal.Expression).GetValue()
@@ -194,6 +205,14 @@ func (grammarSQL SQL) CompileSub(sub interface{}, offset *int) string {
 	panic(fmt.Errorf("a subquery must be a query builder instance, a Closure, or a string"))
 }
 
+// WrapSub Wrap the value, the sub-query is compiled with the bindings offset and wrapped in parentheses.
+func (grammarSQL SQL) WrapSub(value interface{}, offset *int) string {
+	if sub, ok := value.(*dbal.Query); ok {
+		return fmt.Sprintf("(%s)", grammarSQL.CompileSub(sub, offset))
+	}
+	return grammarSQL.Wrap(value)
+}
+
 // CompileColumns Compile the "select *" portion of the query.
 func (grammarSQL SQL) CompileColumns(query *dbal.Query, columns []interface{}, bindingOffset *int) string {
 
@@ -209,15 +228,34 @@ func (grammarSQL SQL) CompileColumns(query *dbal.Query, columns []interface{}, b
 		sql = "select distinct"
 	}
 
-	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(columns))
-	for _, col := range columns {
-		switch col.(type) {
+	sql = fmt.Sprintf("%s %s", sql, grammarSQL.Columnize(grammarSQL.CompileColumnExpressions(query, columns, bindingOffset)))
+	return sql
+}
+
+// CompileColumnExpressions Compile the window functions and the full-text scores of the given columns into expressions.
+func (grammarSQL SQL) CompileColumnExpressions(query *dbal.Query, columns []interface{}, bindingOffset *int) []interface{} {
+	compiled := []interface{}{}
+	for _, column := range columns {
+		switch col := column.(type) {
 		case dbal.Select:
-			*bindingOffset = *bindingOffset + col.(dbal.Select).Offset
+			if sub, ok := col.Name.(*dbal.Query); ok {
+				col.SQL = grammarSQL.WrapSub(sub, bindingOffset)
+				column = col
+			} else {
+				*bindingOffset = *bindingOffset + col.Offset
+			}
+		case dbal.Expression:
+			if strings.Contains(col.GetValue(), dbal.NamedParameter) {
+				column = dbal.Raw(grammarSQL.CompileNamedParameters(col.GetValue(), bindingOffset))
+			}
+		case dbal.Window:
+			column = dbal.Raw(grammarSQL.CompileOver(query, col))
+		case dbal.FullText:
+			column = dbal.Raw(fmt.Sprintf("%s as %s", grammarSQL.CompileFullTextScore(query, col, bindingOffset), grammarSQL.ID(col.Alias)))
 		}
+		compiled = append(compiled, column)
 	}
-
-	return sql
+	return compiled
 }
 
 // CompileFrom  Compile the "from" portion of the query.
@@ -230,10 +268,14 @@ func (grammarSQL SQL) CompileFrom(query *dbal.Query, from dbal.From, bindingOffs
 	if from.Type == "raw" {
 		sql = fmt.Sprintf("from %s", from.SQL)
 	} else if from.Type == "sub" {
+		sub := from.SQL
+		if query, ok := from.Name.(*dbal.Query); ok {
+			sub = grammarSQL.WrapSub(query, bindingOffset)
+		}
 		if from.Alias != "" {
-			sql = fmt.Sprintf("from %s as %s", from.SQL, grammarSQL.ID(from.Alias))
+			sql = fmt.Sprintf("from %s as %s", sub, grammarSQL.ID(from.Alias))
 		} else {
-			sql = fmt.Sprintf("from %s", from.SQL)
+			sql = fmt.Sprintf("from %s", sub)
 		}
 	} else {
 		sql = fmt.Sprintf("from %s", grammarSQL.WrapTable(from))
@@ -268,7 +310,7 @@ func (grammarSQL SQL) CompileHaving(query *dbal.Query, having dbal.Having, bindi
 	// without doing any more processing on it. Otherwise, we will compile the
 	// clause into SQL based on the components that make it up from builder.
 	if having.Type == "raw" {
-		return fmt.Sprintf("%s %s", having.Boolean, having.SQL)
+		return fmt.Sprintf("%s %s", having.Boolean, grammarSQL.CompileNamedParameters(having.SQL, bindingOffset))
 	} else if having.Type == "between" {
 		return grammarSQL.HavingBetween(query, having, bindingOffset)
 	} else if having.Type == "null" {
@@ -324,9 +366,14 @@ func (grammarSQL SQL) CompileOrders(query *dbal.Query, orders []dbal.Order, bind
 	clauses := []string{}
 	for _, order := range orders {
 		if order.SQL != "" {
-			clauses = append(clauses, order.SQL)
+			clauses = append(clauses, grammarSQL.CompileNamedParameters(order.SQL, bindingOffset))
+		} else if window, ok := order.Column.(dbal.Window); ok {
+			window.Alias = ""
+			clauses = append(clauses, fmt.Sprintf("%s %s", grammarSQL.CompileOver(query, window), order.Direction))
+		} else if fulltext, ok := order.Column.(dbal.FullText); ok {
+			clauses = append(clauses, fmt.Sprintf("%s %s", grammarSQL.CompileFullTextScore(query, fulltext, bindingOffset), order.Direction))
 		} else {
-			clauses = append(clauses, fmt.Sprintf("%s %s", grammarSQL.Wrap(order.Column), order.Direction))
+			clauses = append(clauses, fmt.Sprintf("%s %s", grammarSQL.WrapSub(order.Column, bindingOffset), order.Direction))
 		}
 	}
 	return fmt.Sprintf("order by %s", strings.Join(clauses, ", "))
@@ -354,7 +401,32 @@ func (grammarSQL SQL) CompileLock(query *dbal.Query, lock interface{}) string {
 	if ok {
 		return sql
 	}
-	return ""
+
+	value, ok := dbal.MakeLock(lock)
+	if !ok {
+		return ""
+	}
+	return grammarSQL.CompileLockClause(query, value)
+}
+
+// CompileLockClause Compile the locking clause into SQL. ( for update of `users` skip locked )
+func (grammarSQL SQL) CompileLockClause(query *dbal.Query, lock dbal.Lock) string {
+	sql := fmt.Sprintf("for %s", lock.Mode)
+	if len(lock.Tables) > 0 {
+		tables := []string{}
+		for _, table := range lock.Tables {
+			if from, ok := query.From.Name.(dbal.Name); ok && from.Alias == "" && from.Name == table {
+				table = from.Fullname()
+			}
+			tables = append(tables, grammarSQL.ID(table))
+		}
+		sql = fmt.Sprintf("%s of %s", sql, strings.Join(tables, ", "))
+	}
+
+	if lock.Wait != "" {
+		sql = fmt.Sprintf("%s %s", sql, lock.Wait)
+	}
+	return sql
 }
 
 // CompileWheres Compile an update statement into SQL.
@@ -397,6 +469,7 @@ func (grammarSQL SQL) CompileWheres(query *dbal.Query, wheres []dbal.Where, bind
 
 // WhereBasic Compile a date based where clause.
 func (grammarSQL SQL) WhereBasic(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	column := grammarSQL.WrapSub(where.Column, bindingOffset)
 	value := ""
 	if !dbal.IsExpression(where.Value) {
 		*bindingOffset = *bindingOffset + where.Offset
@@ -407,7 +480,12 @@ func (grammarSQL SQL) WhereBasic(query *dbal.Query, where dbal.Where, bindingOff
 
 	operator := strings.ReplaceAll(where.Operator, "?", "??")
 
-	return fmt.Sprintf("%s %s %s", grammarSQL.Wrap(where.Column), operator, value)
+	// The boolean values of a JSON selector are added as raw expressions by the query builder
+	if column, ok := where.Column.(string); ok && IsJSONSelector(column) && (value == "true" || value == "false") {
+		return fmt.Sprintf("%s %s %s", grammarSQL.WrapJSONBooleanSelector(column), operator, grammarSQL.WrapJSONBooleanValue(value))
+	}
+
+	return fmt.Sprintf("%s %s %s", column, operator, value)
 }
 
 // WhereColumn Compile a where clause comparing two columns.
@@ -471,7 +549,7 @@ func (grammarSQL SQL) WhereNested(query *dbal.Query, where dbal.Where, bindingOf
 
 // WhereSub Compile a where condition with a sub-select.
 func (grammarSQL SQL) WhereSub(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
-	selectSQL := grammarSQL.CompileSelectOffset(where.Query, bindingOffset)
+	selectSQL := grammarSQL.CompileSub(where.Query, bindingOffset)
 	return fmt.Sprintf("%s %s (%s)", grammarSQL.Wrap(where.Column), where.Operator, selectSQL)
 }
 
@@ -481,7 +559,7 @@ func (grammarSQL SQL) WhereExists(query *dbal.Query, where dbal.Where, bindingOf
 	if where.Not {
 		exists = "not exists"
 	}
-	selectSQL := grammarSQL.CompileSelectOffset(where.Query, bindingOffset)
+	selectSQL := grammarSQL.CompileSub(where.Query, bindingOffset)
 	return fmt.Sprintf("%s (%s)", exists, selectSQL)
 }
 
@@ -492,7 +570,7 @@ func (grammarSQL SQL) WhereNull(query *dbal.Query, where dbal.Where, bindingOffs
 
 // WhereRaw Compile a raw where clause.
 func (grammarSQL SQL) WhereRaw(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
-	return where.SQL
+	return grammarSQL.CompileNamedParameters(where.SQL, bindingOffset)
 }
 
 // WhereNotnull Compile a "where not null" clause.
@@ -551,6 +629,8 @@ func (grammarSQL SQL) WhereIn(query *dbal.Query, where dbal.Where, bindingOffset
 				}
 			}
 			*bindingOffset = *bindingOffset + count
+		} else if sub, ok := where.ValuesIn.(*dbal.Query); ok {
+			sql = fmt.Sprintf("%s %s %s", grammarSQL.Wrap(where.Column), in, grammarSQL.WrapSub(sub, bindingOffset))
 		} else if _, ok := where.ValuesIn.(dbal.Expression); ok {
 			*bindingOffset = *bindingOffset + where.Offset
 			sql = fmt.Sprintf("%s %s (%s)", grammarSQL.Wrap(where.Column), in, where.ValuesIn.(dbal.Expression).GetValue())
@@ -570,7 +650,32 @@ func (grammarSQL SQL) WhereJsoncontains(query *dbal.Query, where dbal.Where, bin
 	}
 	*bindingOffset = *bindingOffset + where.Offset
 	value := grammarSQL.Parameter(where.Value, *bindingOffset)
-	return fmt.Sprintf("%sJSON_CONTAINS(%s, %s)", not, grammarSQL.Wrap(where.Column), value)
+	return fmt.Sprintf("%sJSON_CONTAINS(%s)", not, grammarSQL.WrapJSONFieldAndPath(where.Column, value))
+}
+
+// WhereJsonlength Compile a "where JSON length" clause.
+func (grammarSQL SQL) WhereJsonlength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	value := ""
+	if !dbal.IsExpression(where.Value) {
+		*bindingOffset = *bindingOffset + where.Offset
+		value = grammarSQL.Parameter(where.Value, *bindingOffset)
+	} else {
+		value = where.Value.(dbal.Expression).GetValue()
+	}
+	return fmt.Sprintf("json_length(%s) %s %s", grammarSQL.WrapJSONFieldAndPath(where.Column), where.Operator, value)
+}
+
+// WrapJSONFieldAndPath Wrap the column as the arguments of the JSON functions, the JSON path is passed as the last argument.
+// `tags` , `options`, '$."languages"' , `options`, ?, '$."languages"'
+func (grammarSQL SQL) WrapJSONFieldAndPath(column interface{}, args ...string) string {
+	name, ok := column.(string)
+	if !ok || !IsJSONSelector(name) {
+		return strings.Join(append([]string{grammarSQL.Wrap(column)}, args...), ", ")
+	}
+	field, path, _ := JSONSelector(name)
+	arguments := append([]string{grammarSQL.Wrap(field)}, args...)
+	arguments = append(arguments, fmt.Sprintf("'%s'", JSONPath(path)))
+	return strings.Join(arguments, ", ")
 }
 
 // RemoveLeadingBoolean Remove the leading boolean from a statement.
@@ -584,3 +689,18 @@ func (grammarSQL SQL) RemoveLeadingBoolean(value string) string {
 func (grammarSQL SQL) Raw(value string) dbal.Expression {
 	return dbal.NewExpression(value)
 }
+
+// CompileNamedParameters Replace the placeholders of the named bindings with the parameters of the database, the binding offset is moved forward.
+func (grammarSQL SQL) CompileNamedParameters(sql string, bindingOffset *int) string {
+	if !strings.Contains(sql, dbal.NamedParameter) {
+		return sql
+	}
+
+	segments := strings.Split(sql, dbal.NamedParameter)
+	compiled := segments[0]
+	for _, segment := range segments[1:] {
+		*bindingOffset = *bindingOffset + 1
+		compiled = compiled + grammarSQL.Parameter(dbal.NamedParameter, *bindingOffset) + segment
+	}
+	return compiled
+}
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/yaoapp/xun/dbal"
@@ -728,3 +729,20 @@ func TestWhereJsoncontainsMySQLOffset(t *testing.T) {
 	assert.Equal(t, "JSON_CONTAINS(`tags`, ?)", result)
 	assert.Equal(t, 4, offset)
 }
+
+func TestCompileRawSQL(t *testing.T) {
+	g := newTestSQL()
+	created := time.Date(2021, 3, 25, 8, 30, 15, 500000000, time.FixedZone("CST", 8*3600))
+	name := "Lee"
+	sql := g.CompileRawSQL(
+		"select * from `users` where `name` in (?,?) and `note` = '?' and `hash` = ? and `created_at` = ? and `deleted_at` is ? and `admin` = ? and `vote` = ? /* ? */ -- ?",
+		[]interface{}{`it\'s`, &name, []byte("xun"), created, nil, true, uint(3)},
+	)
+	assert.Equal(t, "select * from `users` where `name` in ('it\\\\\\'s','Lee') and `note` = '?' and `hash` = X'78756e' and `created_at` = '2021-03-25 08:30:15.5+08:00' and `deleted_at` is NULL and `admin` = true and `vote` = 3 /* ? */ -- ?", sql)
+
+	// The placeholders without bindings are kept
+	assert.Equal(t, "select 1, ?", g.CompileRawSQL("select ?, ?", []interface{}{1}))
+
+	// The line breaks are escaped
+	assert.Equal(t, `select 'line 1\nline 2\r\n\\n'`, g.CompileRawSQL("select ?", []interface{}{"line 1\nline 2\r\n\\n"}))
+}
@@ -15,6 +15,14 @@ func (grammarSQL SQL) CompileDelete(query *dbal.Query) (string, []interface{}) {
 	table := grammarSQL.WrapTable(query.From)
 	alias := ""
 
+	with := grammarSQL.CompileWith(query, query.CTEs, &offset)
+	if len(query.CTEs) > 0 {
+		bindings = append(bindings, query.GetBindings("with")...)
+	}
+	if with != "" {
+		with = with + " "
+	}
+
 	joins := ""
 	if len(query.Joins) > 0 {
 		joins = grammarSQL.CompileJoins(query, query.Joins, &offset)
@@ -30,10 +38,10 @@ func (grammarSQL SQL) CompileDelete(query *dbal.Query) (string, []interface{}) {
 	bindings = append(bindings, query.GetBindings("where")...)
 
 	if len(query.Joins) > 0 {
-		return fmt.Sprintf("delete %s from %s %s %s", alias, table, joins, wheres), bindings
+		return fmt.Sprintf("%sdelete %s from %s %s %s", with, alias, table, joins, wheres), bindings
 	}
 
-	return fmt.Sprintf("delete from %s %s", table, wheres), bindings
+	return fmt.Sprintf("%sdelete from %s %s", with, table, wheres), bindings
 }
 
 // CompileTruncate Compile a truncate table statement into SQL.
@@ -0,0 +1,64 @@
+package sql
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// CompileExplain Compile the statement which gets the query plan of the sql. ( explain select * from `users` )
+func (grammarSQL SQL) CompileExplain(sql string, option dbal.Explain) string {
+	return fmt.Sprintf("explain %s", sql)
+}
+
+// ProcessExplain Parse the rows of the explain statement into the normalized query plan
+func (grammarSQL SQL) ProcessExplain(rows []map[string]interface{}) (*dbal.Plan, error) {
+	return nil, fmt.Errorf("the query plan of the %s database is not supported", grammarSQL.Driver)
+}
+
+// PlanText Get the text of the first column of the explain row
+func PlanText(row map[string]interface{}) (string, error) {
+	keys := []string{}
+	for key := range row {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		switch value := row[key].(type) {
+		case string:
+			return value, nil
+		case []byte:
+			return string(value), nil
+		}
+	}
+	return "", fmt.Errorf("the query plan is not found")
+}
+
+// PlanString Get the string value of the plan field, returns an empty string if the field is not a string
+func PlanString(value interface{}) string {
+	if text, ok := value.(string); ok {
+		return text
+	}
+	return ""
+}
+
+// PlanNumber Get the number value of the plan field, the numbers given as strings are parsed. returns 0 if the field is not a number
+func PlanNumber(value interface{}) float64 {
+	switch value := value.(type) {
+	case float64:
+		return value
+	case int64:
+		return float64(value)
+	case int:
+		return float64(value)
+	case string:
+		number, err := strconv.ParseFloat(value, 64)
+		if err == nil {
+			return number
+		}
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// WhereFulltext Compile a "where full-text" clause.
+func (grammarSQL SQL) WhereFulltext(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	fulltext, ok := where.Column.(dbal.FullText)
+	if !ok {
+		panic(fmt.Errorf("the column of the full-text search should be dbal.FullText"))
+	}
+	*bindingOffset = *bindingOffset + where.Offset
+	fulltext, table := grammarSQL.FullTextOf(query, fulltext)
+	return grammarSQL.WrapFullText(fulltext, table, grammarSQL.Parameter(where.Value, *bindingOffset))
+}
+
+// CompileFullTextScore Compile the relevance score of the full-text search.
+func (grammarSQL SQL) CompileFullTextScore(query *dbal.Query, fulltext dbal.FullText, bindingOffset *int) string {
+	*bindingOffset = *bindingOffset + 1
+	fulltext, table := grammarSQL.FullTextOf(query, fulltext)
+	return grammarSQL.WrapFullTextScore(fulltext, table, grammarSQL.Parameter(fulltext.Search, *bindingOffset))
+}
+
+// FullTextOf Get the full-text search of the query and the wrapped name of the table which the search matched with.
+// The table of the full-text search is the table of the query if not given, the alias of the table is used if given.
+func (grammarSQL SQL) FullTextOf(query *dbal.Query, fulltext dbal.FullText) (dbal.FullText, string) {
+	from, ok := query.From.Name.(dbal.Name)
+	if !ok {
+		return fulltext, grammarSQL.WrapTable(query.From.Name)
+	}
+	if fulltext.Table == "" {
+		fulltext.Table = from.Fullname()
+	}
+	if from.Alias != "" {
+		return fulltext, grammarSQL.ID(from.Alias)
+	}
+	return fulltext, grammarSQL.ID(from.Fullname())
+}
+
+// WrapFullText Wrap the full-text search. match (`title`, `content`) against (? in natural language mode)
+func (quoter *Quoter) WrapFullText(fulltext dbal.FullText, table string, parameter string) string {
+	columns := []string{}
+	for _, column := range fulltext.Columns {
+		columns = append(columns, quoter.Wrap(column))
+	}
+
+	mode := "natural language mode"
+	if fulltext.Mode == "boolean" {
+		mode = "boolean mode"
+	}
+	return fmt.Sprintf("match (%s) against (%s in %s)", strings.Join(columns, ", "), parameter, mode)
+}
+
+// WrapFullTextScore Wrap the relevance score of the full-text search. match (`title`, `content`) against (? in natural language mode)
+func (quoter *Quoter) WrapFullTextScore(fulltext dbal.FullText, table string, parameter string) string {
+	return quoter.WrapFullText(fulltext, table, parameter)
+}
@@ -44,13 +44,7 @@ func (grammarSQL SQL) CompileInsertGetID(query *dbal.Query, columns []interface{
 
 // ProcessInsertGetID Execute an insert and get ID statement and return the id
 func (grammarSQL SQL) ProcessInsertGetID(sql string, bindings []interface{}, sequence string) (int64, error) {
-	stmt, err := grammarSQL.DB.Prepare(sql)
-	if err != nil {
-		return 0, err
-	}
-
-	defer stmt.Close()
-	res, err := stmt.Exec(bindings...)
+	res, err := grammarSQL.GetExecutor().Exec(sql, bindings...)
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,80 @@
+package sql
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var reJSONAlias = regexp.MustCompile(`(?i)\s+as\s+`)
+var reJSONIndex = regexp.MustCompile(`^[0-9]+$`)
+
+// IsJSONSelector Determine if the given value is a JSON selector ( column->path->to->key )
+func IsJSONSelector(value string) bool {
+	return strings.Contains(value, "->")
+}
+
+// JSONSelector Split the JSON selector into the column, the path segments and the alias.
+// "options->dining->meal as meal" => "options", ["dining", "meal"], "meal"
+func JSONSelector(value string) (string, []string, string) {
+	alias := ""
+	parts := reJSONAlias.Split(strings.TrimSpace(value), 2)
+	if len(parts) == 2 {
+		alias = strings.TrimSpace(parts[1])
+	}
+
+	segments := strings.Split(parts[0], "->")
+	column := strings.TrimSpace(segments[0])
+	path := []string{}
+	for _, segment := range segments[1:] {
+		segment = strings.TrimSpace(segment)
+		segment = strings.Trim(segment, `'"`)
+		segment = strings.NewReplacer("'", "", `"`, "", "\n", "", "\r", "").Replace(segment)
+		path = append(path, segment)
+	}
+	return column, path, alias
+}
+
+// JSONPath Make the JSON path of the path segments. ["dining", "0", "meal"] => $."dining"[0]."meal"
+func JSONPath(path []string) string {
+	jsonPath := "$"
+	for _, segment := range path {
+		if IsJSONIndex(segment) {
+			jsonPath = fmt.Sprintf("%s[%s]", jsonPath, segment)
+			continue
+		}
+		jsonPath = fmt.Sprintf(`%s."%s"`, jsonPath, segment)
+	}
+	return jsonPath
+}
+
+// IsJSONIndex Determine if the given path segment is an array index
+func IsJSONIndex(segment string) bool {
+	return reJSONIndex.MatchString(segment)
+}
+
+// WrapJSONSelector Wrap the given JSON selector. json_unquote(json_extract(`options`, '$."dining"."meal"'))
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	column, path, alias := JSONSelector(value)
+	sql := fmt.Sprintf("json_unquote(json_extract(%s, '%s'))", quoter.WrapAliasedValue(column), JSONPath(path))
+	if alias != "" {
+		return fmt.Sprintf("%s as %s", sql, quoter.ID(alias))
+	}
+	return sql
+}
+
+// WrapJSONBooleanSelector Wrap the given JSON selector for boolean values. json_extract(`options`, '$."enabled"')
+func (quoter *Quoter) WrapJSONBooleanSelector(value string) string {
+	column, path, _ := JSONSelector(value)
+	return fmt.Sprintf("json_extract(%s, '%s')", quoter.WrapAliasedValue(column), JSONPath(path))
+}
+
+// WrapJSONBooleanValue Wrap the given JSON boolean value.
+func (quoter *Quoter) WrapJSONBooleanValue(value string) string {
+	return value
+}
+
+// WrapJSONSet Wrap the partial update of the JSON column. json_set(`options`, '$."enabled"', cast(? as json))
+func (quoter *Quoter) WrapJSONSet(target string, path []string, parameter string) string {
+	return fmt.Sprintf("json_set(%s, '%s', cast(%s as json))", target, JSONPath(path), parameter)
+}
@@ -65,6 +65,7 @@ func (quoter *Quoter) VAL(value interface{}) string {
 		input = fmt.Sprintf("%v", value)
 	}
 
+	input = strings.ReplaceAll(input, "\\", "\\\\")
 	input = strings.ReplaceAll(input, "'", "\\'")
 	input = strings.ReplaceAll(input, "\n", "")
 	input = strings.ReplaceAll(input, "\r", "")
@@ -100,6 +101,9 @@ func (quoter *Quoter) WrapAliasedValue(value string) string {
 	if value == "*" {
 		return "*"
 	}
+	if IsJSONSelector(value) {
+		return quoter.WrapJSONSelector(value)
+	}
 	if strings.Contains(value, ".") {
 		arrs := strings.Split(value, ".")
 		table := arrs[0]
@@ -0,0 +1,158 @@
+package sql
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/utils"
+)
+
+// CompileRawSQL Inline the bindings into the "?" placeholders of the sql, for debugging only. ( select * from `users` where `id` = 1 )
+func (grammarSQL SQL) CompileRawSQL(statement string, bindings []interface{}) string {
+	return Interpolate(statement, bindings, "?", false, func(value interface{}) string {
+		return grammarSQL.Literal(value, "2006-01-02 15:04:05.999999999-07:00", "X'%s'")
+	})
+}
+
+// Literal Get the SQL literal of the binding value, the strings are escaped by the quoter.
+// The time is formatted with the layout, the byte slice is the hex string formatted with the format.
+func (grammarSQL SQL) Literal(value interface{}, layout string, format string) string {
+	if utils.IsNil(value) {
+		return "NULL"
+	}
+
+	switch value := value.(type) {
+	case dbal.Expression:
+		return value.GetValue()
+	case time.Time:
+		return grammarSQL.quote(value.Format(layout))
+	case []byte:
+		return fmt.Sprintf(format, hex.EncodeToString(value))
+	case driver.Valuer:
+		v, err := value.Value()
+		if err != nil {
+			return grammarSQL.quote(fmt.Sprintf("%v", value))
+		}
+		return grammarSQL.Literal(v, layout, format)
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Ptr:
+		return grammarSQL.Literal(reflectValue.Elem().Interface(), layout, format)
+	case reflect.Bool:
+		return strconv.FormatBool(reflectValue.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(reflectValue.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(reflectValue.Uint(), 10)
+	case reflect.Float32:
+		return strconv.FormatFloat(reflectValue.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		return strconv.FormatFloat(reflectValue.Float(), 'f', -1, 64)
+	case reflect.String:
+		return grammarSQL.quote(reflectValue.String())
+	}
+	return grammarSQL.quote(fmt.Sprintf("%v", value))
+}
+
+// quote Quote the string literal with the quoter, the line breaks removed by the quoter are kept. The quoters which escape
+// the backslashes (MySQL) get the escaped line breaks ( 'a\nb' ), the line breaks of the others are kept as they are.
+func (grammarSQL SQL) quote(value string) string {
+	escape := grammarSQL.VAL(`\`) != `'\'`
+	var literal strings.Builder
+	start := 0
+	for i := 0; i <= len(value); i++ {
+		if i < len(value) && value[i] != '\n' && value[i] != '\r' {
+			continue
+		}
+
+		quoted := grammarSQL.VAL(value[start:i])
+		literal.WriteString(quoted[1 : len(quoted)-1])
+		if i < len(value) {
+			if escape {
+				literal.WriteString(map[byte]string{'\n': `\n`, '\r': `\r`}[value[i]])
+			} else {
+				literal.WriteByte(value[i])
+			}
+		}
+		start = i + 1
+	}
+	return "'" + literal.String() + "'"
+}
+
+// Interpolate Replace the placeholders of the sql with the literals of the bindings. The placeholder is "?" or the numbered "$" ($1),
+// the placeholders in the quoted strings, the quoted identifiers and the comments are kept. The backslash escapes the next
+// character of the quoted strings if escape is true. The placeholders without bindings are kept.
+func Interpolate(sql string, bindings []interface{}, placeholder string, escape bool, literal func(value interface{}) string) string {
+	var raw strings.Builder
+	next := 0
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := i + 1
+			for ; end < len(sql); end++ {
+				if escape && sql[end] == '\\' {
+					end++
+					continue
+				}
+				if sql[end] == c {
+					break
+				}
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			raw.WriteString(sql[i : end+1])
+			i = end
+
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+			raw.WriteString(sql[i : i+end+1])
+			i = i + end
+
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				end = len(sql) - i - 4
+			}
+			raw.WriteString(sql[i : i+end+4])
+			i = i + end + 3
+
+		case placeholder == "?" && c == '?':
+			if next < len(bindings) {
+				raw.WriteString(literal(bindings[next]))
+			} else {
+				raw.WriteByte(c)
+			}
+			next++
+
+		case placeholder == "$" && c == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+			end := i + 1
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+			num, _ := strconv.Atoi(sql[i+1 : end])
+			if num > 0 && num <= len(bindings) {
+				raw.WriteString(literal(bindings[num-1]))
+			} else {
+				raw.WriteString(sql[i:end])
+			}
+			i = end - 1
+
+		default:
+			raw.WriteByte(c)
+		}
+	}
+	return raw.String()
+}
@@ -0,0 +1,15 @@
+package sql
+
+import (
+	"fmt"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// CompileReturning Compile the "returning" clause of the insert, update, delete and upsert statements. ( returning `id`, `name` )
+func (grammarSQL SQL) CompileReturning(query *dbal.Query, columns []interface{}) string {
+	if len(columns) == 0 {
+		return "returning *"
+	}
+	return fmt.Sprintf("returning %s", grammarSQL.Columnize(columns))
+}
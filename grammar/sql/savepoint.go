@@ -0,0 +1,16 @@
+package sql
+
+// CompileSavepoint Compile the SQL statement to define a savepoint.
+func (grammarSQL SQL) CompileSavepoint(name string) string {
+	return "SAVEPOINT " + grammarSQL.ID(name)
+}
+
+// CompileSavepointRelease Compile the SQL statement to release a savepoint.
+func (grammarSQL SQL) CompileSavepointRelease(name string) string {
+	return "RELEASE SAVEPOINT " + grammarSQL.ID(name)
+}
+
+// CompileSavepointRollBack Compile the SQL statement to rollback to a savepoint.
+func (grammarSQL SQL) CompileSavepointRollBack(name string) string {
+	return "ROLLBACK TO SAVEPOINT " + grammarSQL.ID(name)
+}
@@ -27,7 +27,7 @@ func (grammarSQL SQL) GetVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.GetExecutor().Select(&rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -75,7 +75,7 @@ func (grammarSQL SQL) GetTables() ([]string, error) {
 	sql := "SHOW TABLES"
 	defer log.Debug("%s", sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.GetExecutor().Select(&tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +87,7 @@ func (grammarSQL SQL) TableExists(name string) (bool, error) {
 	sql := fmt.Sprintf("SHOW TABLES like %s", grammarSQL.VAL(name))
 	defer log.Debug("%s", sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.GetExecutor().Select(&rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -204,7 +204,7 @@ func (grammarSQL SQL) GetIndexListing(dbName string, tableName string) ([]*dbal.
 	)
 	defer log.Debug("%s", sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.GetExecutor().Select(&indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -267,7 +267,7 @@ func (grammarSQL SQL) GetColumnListing(dbName string, tableName string) ([]*dbal
 	)
 	defer log.Debug("%s", sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.GetExecutor().Select(&columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -389,7 +389,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table, options ...dbal.CreateTable
 	)
 
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 
 	// Callback
 	for _, cmd := range cbCommands {
@@ -402,7 +402,7 @@ func (grammarSQL SQL) CreateTable(table *dbal.Table, options ...dbal.CreateTable
 func (grammarSQL SQL) DropTable(name string) error {
 	sql := fmt.Sprintf("DROP TABLE %s", grammarSQL.ID(name))
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	return err
 }
 
@@ -410,7 +410,7 @@ func (grammarSQL SQL) DropTable(name string) error {
 func (grammarSQL SQL) DropTableIfExists(name string) error {
 	sql := fmt.Sprintf("DROP TABLE IF EXISTS %s", grammarSQL.ID(name))
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	return err
 }
 
@@ -418,7 +418,7 @@ func (grammarSQL SQL) DropTableIfExists(name string) error {
 func (grammarSQL SQL) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	return err
 }
 
@@ -637,7 +637,7 @@ func (grammarSQL SQL) alterTableDropPrimary(table *dbal.Table, command *dbal.Com
 
 // ExecSQL execute sql then update table structure
 func (grammarSQL SQL) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	if err != nil {
 		return err
 	}
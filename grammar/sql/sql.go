@@ -4,12 +4,16 @@ import (
 	"fmt"
 	"net/url"
 	"path/filepath"
+	"sync"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
 )
 
+// versions the cached database versions of the connections
+var versions = sync.Map{}
+
 // SQL the SQL Grammar
 type SQL struct {
 	Driver       string
@@ -24,6 +28,7 @@ type SQL struct {
 	Read         *sqlx.DB
 	ReadConfig   *dbal.Config
 	Option       *dbal.Option
+	Executor     dbal.Executor
 	dbal.Grammar
 	dbal.Quoter
 }
@@ -148,6 +153,20 @@ func (grammarSQL SQL) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config, read
 	return grammarSQL, nil
 }
 
+// WithExecutor Create a new grammar interface, using the given executor (*sqlx.DB or *sqlx.Tx) to run statements.
+func (grammarSQL SQL) WithExecutor(executor dbal.Executor) dbal.Grammar {
+	grammarSQL.Executor = executor
+	return grammarSQL
+}
+
+// GetExecutor get the executor, returns the write connection if the executor was not set.
+func (grammarSQL SQL) GetExecutor() dbal.Executor {
+	if grammarSQL.Executor != nil {
+		return grammarSQL.Executor
+	}
+	return grammarSQL.DB
+}
+
 // OnConnected the event will be triggered when db server was connected
 func (grammarSQL SQL) OnConnected() error {
 	return nil
@@ -165,6 +184,39 @@ func (grammarSQL SQL) GetOperators() []string {
 	}
 }
 
+// SupportsWindowFunctions Determine if the database supports window functions
+func (grammarSQL SQL) SupportsWindowFunctions() bool {
+	return false
+}
+
+// SupportsReturning Determine if the database supports the "returning" clause of the given statement. (insert, update, delete, upsert)
+func (grammarSQL SQL) SupportsReturning(statement string) bool {
+	return false
+}
+
+// GetMaxPlaceholders Get the maximum number of the placeholders in a statement
+func (grammarSQL SQL) GetMaxPlaceholders() int {
+	return 65535
+}
+
+// CachedVersion get the version of the given connection, the version will be cached after the first call.
+func CachedVersion(db *sqlx.DB, getVersion func() (*dbal.Version, error)) (*dbal.Version, error) {
+	if db == nil {
+		return nil, fmt.Errorf("db is nil")
+	}
+
+	if version, has := versions.Load(db); has {
+		return version.(*dbal.Version), nil
+	}
+
+	version, err := getVersion()
+	if err != nil {
+		return nil, err
+	}
+	versions.Store(db, version)
+	return version, nil
+}
+
 // Wrap a value in keyword identifiers.
 func (grammarSQL SQL) Wrap(value interface{}) string {
 	return grammarSQL.Quoter.Wrap(value)
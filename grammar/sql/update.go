@@ -2,8 +2,10 @@ package sql
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	jsoniter "github.com/json-iterator/go"
 	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/utils"
 )
@@ -20,6 +22,11 @@ func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interfa
 	bindings := []interface{}{}
 	table := grammarSQL.WrapTable(query.From)
 
+	with := grammarSQL.CompileWith(query, query.CTEs, &offset)
+	if len(query.CTEs) > 0 {
+		bindings = append(bindings, query.GetBindings("with")...)
+	}
+
 	joins := ""
 	if len(query.Joins) > 0 {
 		joins = grammarSQL.CompileJoins(query, query.Joins, &offset)
@@ -33,19 +40,118 @@ func (grammarSQL SQL) CompileUpdate(query *dbal.Query, values map[string]interfa
 	wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
 	bindings = append(bindings, query.GetBindings("where")...)
 
-	return fmt.Sprintf("update %s %sset %s %s", table, joins, columns, wheres), bindings
+	sql := fmt.Sprintf("update %s %sset %s %s", table, joins, columns, wheres)
+	if with != "" {
+		sql = fmt.Sprintf("%s %s", with, sql)
+	}
+	return sql, bindings
 }
 
 // CompileUpdateColumns Compile the columns for an update statement.
 func (grammarSQL SQL) CompileUpdateColumns(query *dbal.Query, values map[string]interface{}, offset *int) (string, []interface{}) {
 	columns := []string{}
 	bindings := []interface{}{}
+	selectors := []string{}
 	for key, value := range values {
+		if IsJSONSelector(key) {
+			selectors = append(selectors, key)
+			continue
+		}
 		columns = append(columns, fmt.Sprintf("%s=%s", grammarSQL.Wrap(key), grammarSQL.Parameter(value, *offset+1)))
 		if !dbal.IsExpression(value) && !utils.IsNil(value) {
 			bindings = append(bindings, value)
 			*offset++
 		}
 	}
+
+	// The JSON selectors of the same column are merged into one partial update.
+	// options = json_set(json_set(`options`, '$."a"', ?), '$."b"', ?)
+	sort.Strings(selectors)
+	names := []string{}
+	targets := map[string]string{}
+	for _, selector := range selectors {
+		column, path, _ := JSONSelector(selector)
+		target, has := targets[column]
+		if !has {
+			target = grammarSQL.Wrap(column)
+			names = append(names, column)
+		}
+
+		value := values[selector]
+		if !dbal.IsExpression(value) {
+			bytes, err := jsoniter.Marshal(value)
+			utils.PanicIF(err)
+			value = string(bytes)
+			bindings = append(bindings, value)
+			*offset++
+		}
+		targets[column] = grammarSQL.WrapJSONSet(target, path, grammarSQL.Parameter(value, *offset))
+	}
+
+	for _, name := range names {
+		columns = append(columns, fmt.Sprintf("%s=%s", grammarSQL.Wrap(name), targets[name]))
+	}
 	return strings.Join(columns, ", "), bindings
 }
+
+// CompileUpdateBatch Compile a batch update statement into SQL, each row is updated with its own values and matched by the key column.
+// update `users` set `name`=case `id` when ? then ? when ? then ? else `name` end where `id` in (?,?)
+func (grammarSQL SQL) CompileUpdateBatch(query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	offset := 0
+	sets, keys, bindings := grammarSQL.CompileUpdateBatchCases(query, key, columns, values, &offset)
+	wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+	bindings = append(bindings, query.GetBindings("where")...)
+	return fmt.Sprintf("update %s set %s %s", grammarSQL.WrapTable(query.From), sets, BatchWheres(keys, wheres)), bindings
+}
+
+// CompileUpdateBatchCases Compile the case expressions of the batch update and the key constraint of the rows.
+// `name`=case `id` when ? then ? else `name` end , `id` in (?,?)
+func (grammarSQL SQL) CompileUpdateBatchCases(query *dbal.Query, key string, columns []interface{}, values [][]interface{}, offset *int) (string, string, []interface{}) {
+	bindings := []interface{}{}
+	parameter := func(value interface{}) string {
+		param := grammarSQL.Parameter(value, *offset+1)
+		if !dbal.IsExpression(value) && !utils.IsNil(value) {
+			bindings = append(bindings, value)
+			*offset++
+		}
+		return param
+	}
+
+	index := BatchKeyIndex(key, columns)
+	sets := []string{}
+	for i, column := range columns {
+		if i == index {
+			continue
+		}
+		cases := []string{}
+		for _, value := range values {
+			cases = append(cases, fmt.Sprintf("when %s then %s", parameter(value[index]), parameter(value[i])))
+		}
+		wrapped := grammarSQL.Wrap(column)
+		sets = append(sets, fmt.Sprintf("%s=case %s %s else %s end", wrapped, grammarSQL.Wrap(key), strings.Join(cases, " "), wrapped))
+	}
+
+	keys := []string{}
+	for _, value := range values {
+		keys = append(keys, parameter(value[index]))
+	}
+	return strings.Join(sets, ", "), fmt.Sprintf("%s in (%s)", grammarSQL.Wrap(key), strings.Join(keys, ",")), bindings
+}
+
+// BatchKeyIndex Get the index of the key column in the columns of the batch update
+func BatchKeyIndex(key string, columns []interface{}) int {
+	for i, column := range columns {
+		if fmt.Sprintf("%v", column) == key {
+			return i
+		}
+	}
+	panic(fmt.Errorf("the key column %s of the batch update is not found", key))
+}
+
+// BatchWheres Join the key constraint of the batch update with the compiled where clauses
+func BatchWheres(keys string, wheres string) string {
+	if wheres == "" {
+		return fmt.Sprintf("where %s", keys)
+	}
+	return fmt.Sprintf("where %s and (%s)", keys, strings.TrimPrefix(wheres, "where "))
+}
@@ -0,0 +1,92 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// CompileWindows Compile the named window definitions into SQL. ( window `w` as (partition by ... order by ...), ... )
+func (grammarSQL SQL) CompileWindows(query *dbal.Query, windows []dbal.Window, offset *int) string {
+	if len(windows) == 0 {
+		return ""
+	}
+
+	definitions := []string{}
+	for _, window := range windows {
+		definitions = append(definitions, fmt.Sprintf("%s as (%s)", grammarSQL.ID(window.Name), grammarSQL.compileWindowSpec(query, window, "")))
+	}
+	return fmt.Sprintf("window %s", strings.Join(definitions, ", "))
+}
+
+// CompileOver Compile a window function into SQL. ( row_number() over (partition by ... order by ...) as `alias` )
+func (grammarSQL SQL) CompileOver(query *dbal.Query, window dbal.Window) string {
+
+	function := window.Func
+	if !strings.Contains(function, "(") {
+		args := []string{}
+		for _, arg := range window.Args {
+			args = append(args, grammarSQL.wrapWindowColumn(query, arg))
+		}
+		function = fmt.Sprintf("%s(%s)", function, strings.Join(args, ", "))
+	}
+
+	// Reference the named window directly: rank() over `w`
+	over := ""
+	if window.Name != "" && len(window.Partitions) == 0 && len(window.Orders) == 0 && window.Frame == "" {
+		over = grammarSQL.ID(window.Name)
+	} else {
+		over = fmt.Sprintf("(%s)", grammarSQL.compileWindowSpec(query, window, window.Name))
+	}
+
+	sql := fmt.Sprintf("%s over %s", function, over)
+	if window.Alias != "" {
+		sql = fmt.Sprintf("%s as %s", sql, grammarSQL.ID(window.Alias))
+	}
+	return sql
+}
+
+// compileWindowSpec Compile the window specification ( `base` partition by ... order by ... rows ... )
+func (grammarSQL SQL) compileWindowSpec(query *dbal.Query, window dbal.Window, base string) string {
+	clauses := []string{}
+	if base != "" {
+		clauses = append(clauses, grammarSQL.ID(base))
+	}
+
+	if len(window.Partitions) > 0 {
+		partitions := []string{}
+		for _, column := range window.Partitions {
+			partitions = append(partitions, grammarSQL.wrapWindowColumn(query, column))
+		}
+		clauses = append(clauses, fmt.Sprintf("partition by %s", strings.Join(partitions, ", ")))
+	}
+
+	if len(window.Orders) > 0 {
+		orders := []string{}
+		for _, order := range window.Orders {
+			orders = append(orders, fmt.Sprintf("%s %s", grammarSQL.wrapWindowColumn(query, order.Column), order.Direction))
+		}
+		clauses = append(clauses, fmt.Sprintf("order by %s", strings.Join(orders, ", ")))
+	}
+
+	if window.Frame != "" {
+		clauses = append(clauses, window.Frame)
+	}
+	return strings.Join(clauses, " ")
+}
+
+// wrapWindowColumn Wrap a column of the window, the table of the query will be prefixed. ( `prefix_table`.`column` )
+func (grammarSQL SQL) wrapWindowColumn(query *dbal.Query, column interface{}) string {
+	value, ok := column.(string)
+	if !ok || !strings.Contains(value, ".") {
+		return grammarSQL.Wrap(column)
+	}
+
+	arrs := strings.SplitN(value, ".", 2)
+	table := arrs[0]
+	if from, ok := query.From.Name.(dbal.Name); ok && from.Alias == "" && from.Name == table {
+		table = from.Fullname()
+	}
+	return fmt.Sprintf("%s.%s", grammarSQL.ID(table), grammarSQL.Wrap(arrs[1]))
+}
@@ -0,0 +1,67 @@
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+)
+
+// CompileWith Compile the common table expressions into SQL. ( with recursive name (columns) as materialized (sql), ... )
+func (grammarSQL SQL) CompileWith(query *dbal.Query, ctes []dbal.CTE, offset *int) string {
+	if len(ctes) == 0 {
+		return ""
+	}
+
+	recursive := ""
+	expressions := []string{}
+	for _, cte := range ctes {
+		if cte.Recursive {
+			recursive = "recursive "
+		}
+
+		columns := ""
+		if len(cte.Columns) > 0 {
+			names := []string{}
+			for _, column := range cte.Columns {
+				names = append(names, grammarSQL.ID(column))
+			}
+			columns = fmt.Sprintf(" (%s)", strings.Join(names, ", "))
+		}
+
+		materialized := ""
+		if cte.Materialized {
+			materialized = "materialized "
+		}
+
+		sql := cte.SQL
+		if cte.Query != nil {
+			sql = grammarSQL.CompileCTE(cte.Query, cte.Recursive, offset)
+		} else {
+			*offset = *offset + cte.Offset
+		}
+		expressions = append(expressions, fmt.Sprintf("%s%s as %s(%s)", grammarSQL.ID(cte.Name), columns, materialized, sql))
+	}
+
+	return fmt.Sprintf("with %s%s", recursive, strings.Join(expressions, ", "))
+}
+
+// CompileCTE Compile the subquery of the common table expression. The unions of a recursive
+// expression must not be wrapped, the anchor part and the recursive part are joined directly.
+func (grammarSQL SQL) CompileCTE(query *dbal.Query, recursive bool, offset *int) string {
+	if !recursive || len(query.Unions) == 0 {
+		return grammarSQL.CompileSub(query, offset)
+	}
+
+	anchor := *query
+	anchor.Unions = []dbal.Union{}
+	sql := grammarSQL.CompileSub(&anchor, offset)
+	for _, union := range query.Unions {
+		conjunction := "union"
+		if union.All {
+			conjunction = "union all"
+		}
+		sql = fmt.Sprintf("%s %s %s", sql, conjunction, grammarSQL.CompileSub(union.Query, offset))
+	}
+	return sql
+}
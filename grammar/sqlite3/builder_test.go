@@ -0,0 +1,15 @@
+package sqlite3
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/yaoapp/xun/dbal"
+)
+
+func TestSQLAddColumnQuotedValuesSQLite3(t *testing.T) {
+	grammar := newTestSQLite3WithQuoter()
+	length := 50
+	column := &dbal.Column{Name: "note", Type: "string", Length: &length, Default: `it's C:\temp`}
+	assert.Equal(t, "`note` VARCHAR(50) NOT NULL DEFAULT 'it''s C:\\temp'", grammar.SQLAddColumn(column))
+}
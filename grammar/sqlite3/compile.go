@@ -1,6 +1,7 @@
 package sqlite3
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
@@ -20,12 +21,13 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 
 	// SQL STMT
 	if query.SQL != "" {
-		if !strings.Contains(query.SQL, "limit") && !strings.Contains(query.SQL, "offset") {
+		stmt := grammarSQL.CompileNamedParameters(query.SQL, offset)
+		if !strings.Contains(stmt, "limit") && !strings.Contains(stmt, "offset") {
 			limit := grammarSQL.CompileLimit(query, query.Limit, offset)
 			offset := grammarSQL.CompileOffset(query, query.Offset)
-			return strings.TrimSpace(fmt.Sprintf("%s %s %s", query.SQL, limit, offset))
+			return strings.TrimSpace(fmt.Sprintf("%s %s %s", stmt, limit, offset))
 		}
-		return query.SQL
+		return stmt
 	}
 
 	if len(query.Unions) > 0 && query.Aggregate.Func != "" {
@@ -45,6 +47,7 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 	// To compile the query, we'll spin through each component of the query and
 	// see if that component exists. If it does we'll just call the compiler
 	// function for the component which is responsible for making the SQL.
+	sqls["with"] = grammarSQL.CompileWith(query, query.CTEs, offset)
 	sqls["aggregate"] = grammarSQL.CompileAggregate(query, query.Aggregate)
 	sqls["columns"] = grammarSQL.CompileColumns(query, query.Columns, offset)
 	sqls["from"] = grammarSQL.CompileFrom(query, query.From, offset)
@@ -52,13 +55,14 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 	sqls["wheres"] = grammarSQL.CompileWheres(query, query.Wheres, offset)
 	sqls["groups"] = grammarSQL.CompileGroups(query, query.Groups, offset)
 	sqls["havings"] = grammarSQL.CompileHavings(query, query.Havings, offset)
+	sqls["windows"] = grammarSQL.CompileWindows(query, query.Windows, offset)
 	sqls["orders"] = grammarSQL.CompileOrders(query, query.Orders, offset)
 	sqls["limit"] = grammarSQL.CompileLimit(query, query.Limit, offset)
 	sqls["offset"] = grammarSQL.CompileOffset(query, query.Offset)
 	sqls["lock"] = grammarSQL.CompileLock(query, query.Lock)
 
 	sql := ""
-	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "orders", "limit", "offset", "lock"} {
+	for _, name := range []string{"aggregate", "columns", "from", "joins", "wheres", "groups", "havings", "windows", "orders", "limit", "offset", "lock"} {
 		segment, has := sqls[name]
 		if has && segment != "" {
 			sql = sql + segment + " "
@@ -70,6 +74,11 @@ func (grammarSQL SQLite3) CompileSelectOffset(query *dbal.Query, offset *int) st
 		sql = fmt.Sprintf("%s %s", grammarSQL.WrapUnion(sql), grammarSQL.CompileUnions(query, query.Unions, offset))
 	}
 
+	// Compile the common table expressions
+	if sqls["with"] != "" {
+		sql = fmt.Sprintf("%s %s", sqls["with"], sql)
+	}
+
 	// reset columns
 	query.Columns = columns
 	return strings.Trim(sql, " ")
@@ -165,18 +174,103 @@ func (grammarSQL SQLite3) WhereNested(query *dbal.Query, where dbal.Where, bindi
 	return fmt.Sprintf("(%s)", sql)
 }
 
-// WhereJsoncontains Compile a "where JSON contains" clause. SQLite falls back to LIKE.
+// WhereJsoncontains Compile a "where JSON contains" clause. Every value of the given JSON
+// should be found in the column: the values are expanded with json_each and compared by type and value.
+// Object values are matched by key as well, the nested objects and arrays are compared recursively.
 func (grammarSQL SQLite3) WhereJsoncontains(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
-	not := ""
+	exists := "not exists"
 	if where.Not {
-		not = "not "
+		exists = "exists"
 	}
 	*bindingOffset = *bindingOffset + where.Offset
 	value := grammarSQL.Parameter(where.Value, *bindingOffset)
-	return fmt.Sprintf("%s%s like %s", not, grammarSQL.Wrap(where.Column), value)
+	return fmt.Sprintf(
+		"%s (select 1 from json_each(%s) as candidate where not exists ("+
+			"select 1 from json_each(%s) as target where %s "+
+			"and (candidate.key is null or typeof(candidate.key) = 'integer' or target.key = candidate.key)))",
+		exists, value, grammarSQL.WrapJSONFieldAndPath(where.Column), jsonContainsMatch("target", "candidate", jsonDepth(where.Value)-1),
+	)
+}
+
+// jsonContainsMatch the condition of the target value contains the candidate value. The objects and arrays are
+// expanded with json_each for the given levels: every member of the candidate object should be contained by the
+// target member of the same key, and every element of the candidate array should be contained by a target element.
+func jsonContainsMatch(target string, candidate string, depth int) string {
+	if depth <= 0 {
+		return fmt.Sprintf("%s.type = %s.type and %s.value is %s.value", target, candidate, target, candidate)
+	}
+
+	level := fmt.Sprintf("%d", depth)
+	member := func(key string) string {
+		return fmt.Sprintf(
+			"not exists (select 1 from json_each(%s.value) as %s where not exists (select 1 from json_each(%s.value) as %s where %s%s))",
+			candidate, candidate+level, target, target+level, key, jsonContainsMatch(target+level, candidate+level, depth-1),
+		)
+	}
+	return fmt.Sprintf(
+		"%s.type = %s.type and case %s.type when 'object' then %s when 'array' then %s else %s.value is %s.value end",
+		target, candidate, candidate,
+		member(fmt.Sprintf("%s.key = %s.key and ", target+level, candidate+level)), member(""),
+		target, candidate,
+	)
+}
+
+// jsonDepth the nesting levels of the JSON value, the scalar values are 0.
+func jsonDepth(value interface{}) int {
+	var data interface{} = value
+	switch v := value.(type) {
+	case string:
+		if err := json.Unmarshal([]byte(v), &data); err != nil {
+			return 0
+		}
+	case []byte:
+		if err := json.Unmarshal(v, &data); err != nil {
+			return 0
+		}
+	}
+	return jsonLevels(data)
+}
+
+func jsonLevels(data interface{}) int {
+	items := []interface{}{}
+	switch v := data.(type) {
+	case map[string]interface{}:
+		for _, item := range v {
+			items = append(items, item)
+		}
+	case []interface{}:
+		items = v
+	default:
+		return 0
+	}
+
+	depth := 0
+	for _, item := range items {
+		if d := jsonLevels(item); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
 }
 
-// CompileLock the lock into SQL.
+// WhereJsonlength Compile a "where JSON length" clause.
+func (grammarSQL SQLite3) WhereJsonlength(query *dbal.Query, where dbal.Where, bindingOffset *int) string {
+	value := ""
+	if !dbal.IsExpression(where.Value) {
+		*bindingOffset = *bindingOffset + where.Offset
+		value = grammarSQL.Parameter(where.Value, *bindingOffset)
+	} else {
+		value = where.Value.(dbal.Expression).GetValue()
+	}
+	return fmt.Sprintf("json_array_length(%s) %s %s", grammarSQL.WrapJSONFieldAndPath(where.Column), where.Operator, value)
+}
+
+// CompileLock the lock into SQL. SQLite locks the whole database within the transaction instead of the rows,
+// so the share and update locks are ignored, the other modes and options are not supported.
 func (grammarSQL SQLite3) CompileLock(query *dbal.Query, lock interface{}) string {
-	return ""
+	value, ok := dbal.MakeLock(lock)
+	if !ok || value.IsBasic() {
+		return ""
+	}
+	panic(fmt.Errorf("SQLite does not support the %q lock, only the share and update locks without options are allowed", value.Mode))
 }
@@ -32,20 +32,26 @@ func newBaseQuery(tableName string) *dbal.Query {
 	}
 }
 
+func jsonContainsSQLite(exists string, column string) string {
+	return exists + " (select 1 from json_each(?) as candidate where not exists (" +
+		"select 1 from json_each(" + column + ") as target where target.type = candidate.type and target.value is candidate.value " +
+		"and (candidate.key is null or typeof(candidate.key) = 'integer' or target.key = candidate.key)))"
+}
+
 func TestWhereJsoncontainsSQLite(t *testing.T) {
 	g := newTestSQLite3()
 	offset := 0
 	where := dbal.Where{
 		Type:    "jsoncontains",
 		Column:  "tags",
-		Value:   "%admin%",
+		Value:   `"admin"`,
 		Boolean: "and",
 		Not:     false,
 		Offset:  1,
 	}
 
 	result := g.WhereJsoncontains(&dbal.Query{}, where, &offset)
-	assert.Equal(t, "`tags` like ?", result)
+	assert.Equal(t, jsonContainsSQLite("not exists", "`tags`"), result)
 	assert.Equal(t, 1, offset)
 }
 
@@ -55,14 +61,14 @@ func TestWhereJsoncontainsSQLiteNot(t *testing.T) {
 	where := dbal.Where{
 		Type:    "jsoncontains",
 		Column:  "tags",
-		Value:   "%admin%",
+		Value:   `"admin"`,
 		Boolean: "and",
 		Not:     true,
 		Offset:  1,
 	}
 
 	result := g.WhereJsoncontains(&dbal.Query{}, where, &offset)
-	assert.Equal(t, "not `tags` like ?", result)
+	assert.Equal(t, jsonContainsSQLite("exists", "`tags`"), result)
 	assert.Equal(t, 1, offset)
 }
 
@@ -72,17 +78,34 @@ func TestWhereJsoncontainsSQLiteOffset(t *testing.T) {
 	where := dbal.Where{
 		Type:    "jsoncontains",
 		Column:  "tags",
-		Value:   "%test%",
+		Value:   `"test"`,
 		Boolean: "and",
 		Not:     false,
 		Offset:  1,
 	}
 
 	result := g.WhereJsoncontains(&dbal.Query{}, where, &offset)
-	assert.Equal(t, "`tags` like ?", result)
+	assert.Equal(t, jsonContainsSQLite("not exists", "`tags`"), result)
 	assert.Equal(t, 6, offset)
 }
 
+func TestWhereJsoncontainsSQLiteNested(t *testing.T) {
+	g := newTestSQLite3()
+	offset := 0
+	where := dbal.Where{Type: "jsoncontains", Column: "tags", Value: `{"tags":["x"]}`, Boolean: "and", Offset: 1}
+
+	result := g.WhereJsoncontains(&dbal.Query{}, where, &offset)
+	match := "target.type = candidate.type and case candidate.type " +
+		"when 'object' then not exists (select 1 from json_each(candidate.value) as candidate1 where not exists (select 1 from json_each(target.value) as target1 where target1.key = candidate1.key and target1.type = candidate1.type and target1.value is candidate1.value)) " +
+		"when 'array' then not exists (select 1 from json_each(candidate.value) as candidate1 where not exists (select 1 from json_each(target.value) as target1 where target1.type = candidate1.type and target1.value is candidate1.value)) " +
+		"else target.value is candidate.value end"
+	assert.Equal(t, "not exists (select 1 from json_each(?) as candidate where not exists (select 1 from json_each(`tags`) as target where "+match+
+		" and (candidate.key is null or typeof(candidate.key) = 'integer' or target.key = candidate.key)))", result)
+	assert.Equal(t, 1, offset)
+	assert.Equal(t, 0, jsonDepth(`"admin"`))
+	assert.Equal(t, 3, jsonDepth(`{"a":[{"b":1}],"c":"[[1]]"}`))
+}
+
 func newDeleteQuery(tableName string, wheres []dbal.Where, whereBindings []interface{}) *dbal.Query {
 	return &dbal.Query{
 		From:   dbal.From{Name: dbal.NewName(tableName)},
@@ -110,12 +133,12 @@ func TestCompileDeleteSimpleSQLite(t *testing.T) {
 func TestCompileDeleteWithJsonContainsSQLite(t *testing.T) {
 	g := newTestSQLite3()
 	query := newDeleteQuery("assistants", []dbal.Where{
-		{Type: "jsoncontains", Column: "tags", Value: "%test%", Boolean: "and", Offset: 1},
-	}, []interface{}{"%test%"})
+		{Type: "jsoncontains", Column: "tags", Value: `"test"`, Boolean: "and", Offset: 1},
+	}, []interface{}{`"test"`})
 
 	sql, bindings := g.CompileDelete(query)
-	assert.Equal(t, "delete from `assistants` where `tags` like ?", sql)
-	assert.Equal(t, []interface{}{"%test%"}, bindings)
+	assert.Equal(t, "delete from `assistants` where "+jsonContainsSQLite("not exists", "`tags`"), sql)
+	assert.Equal(t, []interface{}{`"test"`}, bindings)
 }
 
 func TestCompileDeleteNoWhereSQLite(t *testing.T) {
@@ -144,13 +167,13 @@ func TestCompileUpdateSimpleSQLite(t *testing.T) {
 func TestCompileUpdateWithJsonContainsSQLite(t *testing.T) {
 	g := newTestSQLite3()
 	query := newDeleteQuery("assistants", []dbal.Where{
-		{Type: "jsoncontains", Column: "tags", Value: "%old%", Boolean: "and", Offset: 1},
-	}, []interface{}{"%old%"})
+		{Type: "jsoncontains", Column: "tags", Value: `"old"`, Boolean: "and", Offset: 1},
+	}, []interface{}{`"old"`})
 
 	sql, bindings := g.CompileUpdate(query, map[string]interface{}{"name": "updated"})
 	assert.Contains(t, sql, "update `assistants` set")
-	assert.Contains(t, sql, "`tags` like")
-	assert.Contains(t, bindings, "%old%")
+	assert.Contains(t, sql, "json_each(`tags`)")
+	assert.Contains(t, bindings, `"old"`)
 	assert.Contains(t, bindings, "updated")
 }
 
@@ -250,20 +273,20 @@ func TestCompileDeleteNestedJsonContainsSQLite(t *testing.T) {
 			Boolean: "and",
 			Query: &dbal.Query{
 				Wheres: []dbal.Where{
-					{Type: "jsoncontains", Column: "tags", Value: "%a%", Boolean: "and", Offset: 1},
-					{Type: "jsoncontains", Column: "tags", Value: "%b%", Boolean: "or", Offset: 1},
+					{Type: "jsoncontains", Column: "tags", Value: `"a"`, Boolean: "and", Offset: 1},
+					{Type: "jsoncontains", Column: "tags", Value: `"b"`, Boolean: "or", Offset: 1},
 				},
 				Bindings: map[string][]interface{}{
 					"select": {}, "from": {}, "join": {},
-					"where":   {"%a%", "%b%"},
+					"where":   {`"a"`, `"b"`},
 					"groupBy": {}, "having": {}, "order": {},
 				},
 			},
 		},
-	}, []interface{}{"%a%", "%b%"})
+	}, []interface{}{`"a"`, `"b"`})
 
 	sql, bindings := g.CompileDelete(query)
-	assert.Contains(t, sql, "`tags` like")
+	assert.Contains(t, sql, "json_each(`tags`)")
 	assert.Contains(t, sql, "delete from")
 	assert.Len(t, bindings, 2)
 }
@@ -558,8 +581,28 @@ func TestWhereNestedNormalQuery(t *testing.T) {
 
 func TestCompileLockReturnsEmpty(t *testing.T) {
 	g := newTestSQLite3()
-	result := g.CompileLock(&dbal.Query{}, "for update")
-	assert.Equal(t, "", result)
+	assert.Equal(t, "", g.CompileLock(&dbal.Query{}, "update"))
+	assert.Equal(t, "", g.CompileLock(&dbal.Query{}, "share"))
+	assert.Equal(t, "", g.CompileLock(&dbal.Query{}, nil))
+	assert.Panics(t, func() { g.CompileLock(&dbal.Query{}, "for update") })
+	assert.Panics(t, func() { g.CompileLock(&dbal.Query{}, "no key update") })
+}
+
+func TestCompileLockOptionsSQLite(t *testing.T) {
+	g := newTestSQLite3()
+	assert.Equal(t, "", g.CompileLock(&dbal.Query{}, dbal.Lock{Mode: "update"}))
+	assert.Panics(t, func() { g.CompileLock(&dbal.Query{}, dbal.Lock{Mode: "update", Wait: "skip locked"}) })
+	assert.Panics(t, func() { g.CompileLock(&dbal.Query{}, dbal.Lock{Mode: "key share"}) })
+}
+
+// ---------------------------------------------------------------------------
+// CompileRawSQL
+// ---------------------------------------------------------------------------
+
+func TestCompileRawSQLSQLite(t *testing.T) {
+	g := newTestSQLite3WithQuoter()
+	sql := g.CompileRawSQL("select * from `users` where `name` = ? and `note` = ?", []interface{}{"it's", "line 1\nline 2\r\n\\n"})
+	assert.Equal(t, "select * from `users` where `name` = 'it''s' and `note` = 'line 1\nline 2\r\n\\n'", sql)
 }
 
 // ---------------------------------------------------------------------------
@@ -776,3 +819,48 @@ func TestGetOperatorsSQLite(t *testing.T) {
 	assert.Contains(t, ops, "like")
 	assert.Contains(t, ops, "ilike")
 }
+
+func TestWhereJsonlengthSQLite(t *testing.T) {
+	g := newTestSQLite3()
+	offset := 0
+	where := dbal.Where{Type: "jsonlength", Column: "options->languages", Operator: ">", Value: 1, Boolean: "and", Offset: 1}
+	result := g.WhereJsonlength(&dbal.Query{}, where, &offset)
+	assert.Equal(t, "json_array_length(`options`, '$.\"languages\"') > ?", result)
+	assert.Equal(t, 1, offset)
+
+	where = dbal.Where{Type: "jsoncontains", Column: "options->languages", Value: `"en"`, Boolean: "and", Offset: 1}
+	result = g.WhereJsoncontains(&dbal.Query{}, where, &offset)
+	assert.Equal(t, jsonContainsSQLite("not exists", "`options`, '$.\"languages\"'"), result)
+}
+
+func TestCompileUpdateBatchSQLite3(t *testing.T) {
+	g := newTestSQLite3WithQuoter()
+	q := newBaseQuery("users")
+	q.Wheres = []dbal.Where{{Type: "basic", Column: "status", Operator: "=", Value: "DONE", Boolean: "and", Offset: 1}}
+	q.Bindings["where"] = []interface{}{"DONE"}
+
+	sql, bindings := g.CompileUpdateBatch(q, "id", []interface{}{"id", "name", "vote"}, [][]interface{}{{1, "Max", 10}, {2, "Kim", nil}})
+	assert.Equal(t, "update `users` set `name`=case `id` when ? then ? when ? then ? else `name` end, `vote`=case `id` when ? then ? when ? then NULL else `vote` end where `id` in (?,?) and (`status` = ?)", sql)
+	assert.Equal(t, []interface{}{1, "Max", 2, "Kim", 1, 10, 2, 1, 2, "DONE"}, bindings)
+}
+
+func TestProcessExplainSQLite3(t *testing.T) {
+	g := newTestSQLite3()
+	assert.Equal(t, "explain query plan select * from `users`", g.CompileExplain("select * from `users`", dbal.Explain{}))
+
+	plan, err := g.ProcessExplain([]map[string]interface{}{
+		{"id": int64(3), "parent": int64(0), "notused": int64(0), "detail": "SCAN o"},
+		{"id": int64(5), "parent": int64(0), "notused": int64(0), "detail": "SEARCH u USING INTEGER PRIMARY KEY (rowid=?)"},
+		{"id": int64(8), "parent": int64(0), "notused": int64(0), "detail": "SEARCH i USING COVERING INDEX items_order_id (order_id=?)"},
+		{"id": int64(12), "parent": int64(0), "notused": int64(0), "detail": "USE TEMP B-TREE FOR ORDER BY"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, len(plan.Children))
+	assert.Equal(t, dbal.PlanFullScan, plan.Children[0].Type)
+	assert.Equal(t, "o", plan.Children[0].Table)
+	assert.Equal(t, dbal.PlanIndexScan, plan.Children[1].Type)
+	assert.Equal(t, "INTEGER PRIMARY KEY", plan.Children[1].Index)
+	assert.Equal(t, "items_order_id", plan.Children[2].Index)
+	assert.Equal(t, dbal.PlanSort, plan.Children[3].Type)
+	assert.Equal(t, 1, len(plan.FullScans()))
+}
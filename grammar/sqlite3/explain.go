@@ -0,0 +1,85 @@
+package sqlite3
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
+)
+
+// CompileExplain Compile the statement which gets the query plan of the sql. The analyze option is ignored.
+// ( explain query plan select * from `users` )
+func (grammarSQL SQLite3) CompileExplain(sql string, option dbal.Explain) string {
+	return fmt.Sprintf("explain query plan %s", sql)
+}
+
+// ProcessExplain Parse the rows of the query plan into the normalized query plan, the rows are the nodes of the plan
+// linked by the "id" and "parent" columns.
+func (grammarSQL SQLite3) ProcessExplain(rows []map[string]interface{}) (*dbal.Plan, error) {
+	root := &dbal.Plan{Type: dbal.PlanOther, Operation: "QUERY PLAN", Children: []*dbal.Plan{}}
+	nodes := map[float64]*dbal.Plan{0: root}
+	for _, row := range rows {
+		plan := planNode(sql.PlanString(row["detail"]))
+		parent, has := nodes[sql.PlanNumber(row["parent"])]
+		if !has {
+			parent = root
+		}
+		parent.Children = append(parent.Children, plan)
+		nodes[sql.PlanNumber(row["id"])] = plan
+	}
+	return root, nil
+}
+
+// planNode Get the plan of the node by the detail. ( SCAN users, SEARCH users USING INDEX users_email (email=?) )
+func planNode(detail string) *dbal.Plan {
+	plan := &dbal.Plan{Type: dbal.PlanOther, Detail: detail, Children: []*dbal.Plan{}}
+	fields := strings.Fields(detail)
+	if len(fields) == 0 {
+		return plan
+	}
+
+	plan.Operation = fields[0]
+	switch {
+	case (fields[0] == "SCAN" || fields[0] == "SEARCH") && len(fields) > 1:
+		fields = fields[1:]
+		if fields[0] == "TABLE" && len(fields) > 1 {
+			fields = fields[1:]
+		}
+
+		// The constant rows and the results of the sub-queries are not tables
+		if fields[0] == "CONSTANT" || fields[0] == "SUBQUERY" || strings.HasPrefix(fields[0], "(") {
+			return plan
+		}
+
+		plan.Table = fields[0]
+		plan.Index = planIndex(detail)
+		plan.Type = dbal.PlanIndexScan
+		if plan.Operation == "SCAN" && plan.Index == "" {
+			plan.Type = dbal.PlanFullScan
+		}
+
+	case strings.Contains(detail, "ORDER BY"):
+		plan.Type = dbal.PlanSort
+
+	case strings.Contains(detail, "GROUP BY") || strings.Contains(detail, "DISTINCT"):
+		plan.Type = dbal.PlanAggregate
+	}
+	return plan
+}
+
+// planIndex Get the index used by the node
+func planIndex(detail string) string {
+	for _, using := range []string{"USING COVERING INDEX ", "USING INDEX "} {
+		if i := strings.Index(detail, using); i >= 0 {
+			return strings.Fields(detail[i+len(using):])[0]
+		}
+	}
+
+	for _, key := range []string{"INTEGER PRIMARY KEY", "PRIMARY KEY"} {
+		if strings.Contains(detail, "USING "+key) {
+			return key
+		}
+	}
+	return ""
+}
@@ -2,8 +2,11 @@ package sqlite3
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/yaoapp/xun/dbal"
 	"github.com/yaoapp/xun/grammar/sql"
+	"github.com/yaoapp/xun/utils"
 )
 
 // Quoter the database quoting query text SQL type
@@ -11,7 +14,88 @@ type Quoter struct {
 	sql.Quoter
 }
 
+// VAL quoting query value ( 'value' ), the single quotes are escaped by doubling them.
+func (quoter *Quoter) VAL(v interface{}) string {
+	input := ""
+	switch v.(type) {
+	case *string:
+		input = utils.StringVal(v.(*string))
+	case string:
+		input = v.(string)
+	default:
+		input = fmt.Sprintf("%v", v)
+	}
+	input = strings.ReplaceAll(input, "'", "''")
+	input = strings.ReplaceAll(input, "\n", "")
+	input = strings.ReplaceAll(input, "\r", "")
+	return "'" + input + "'"
+}
+
 // WrapUnion a union subquery in parentheses.
 func (quoter *Quoter) WrapUnion(sql string) string {
 	return fmt.Sprintf("select * from (%s)", sql)
 }
+
+// Wrap a value in keyword identifiers.
+func (quoter *Quoter) Wrap(value interface{}) string {
+	if column, ok := value.(string); ok && sql.IsJSONSelector(column) {
+		return quoter.WrapJSONSelector(column)
+	}
+	return quoter.Quoter.Wrap(value)
+}
+
+// Columnize Convert an array of column names into a delimited string.
+func (quoter *Quoter) Columnize(columns []interface{}) string {
+	wrapColumns := []string{}
+	for _, col := range columns {
+		wrapColumns = append(wrapColumns, quoter.Wrap(col))
+	}
+	return strings.Join(wrapColumns, ", ")
+}
+
+// WrapJSONSelector Wrap the given JSON selector. json_extract(`options`, '$."dining"."meal"')
+func (quoter *Quoter) WrapJSONSelector(value string) string {
+	column, path, alias := sql.JSONSelector(value)
+	selector := fmt.Sprintf("json_extract(%s, '%s')", quoter.WrapAliasedValue(column), sql.JSONPath(path))
+	if alias != "" {
+		return fmt.Sprintf("%s as %s", selector, quoter.ID(alias))
+	}
+	return selector
+}
+
+// WrapJSONSet Wrap the partial update of the JSON column. json_set(`options`, '$."enabled"', json(?))
+func (quoter *Quoter) WrapJSONSet(target string, path []string, parameter string) string {
+	return fmt.Sprintf("json_set(%s, '%s', json(%s))", target, sql.JSONPath(path), parameter)
+}
+
+// WrapFullText Wrap the FTS5 full-text search. `posts`.`posts` match '{title content} : (' || ? || ')'
+// When the FTS5 table is not the table of the query, the rows are matched by the rowid.
+func (quoter *Quoter) WrapFullText(fulltext dbal.FullText, table string, parameter string) string {
+	fts := quoter.ID(fulltext.Table)
+	if fts == table {
+		return fmt.Sprintf("%s.%s match %s", table, fts, quoter.wrapFTS5Query(fulltext, parameter))
+	}
+	return fmt.Sprintf("%s.rowid in (select rowid from %s where %s match %s)", table, fts, fts, quoter.wrapFTS5Query(fulltext, parameter))
+}
+
+// WrapFullTextScore Wrap the relevance score of the FTS5 full-text search, the higher score is the more relevant.
+// (select -bm25(`fts`.`posts`) from `posts` as `fts` where `fts`.`posts` match ... and `fts`.rowid = `posts`.rowid)
+func (quoter *Quoter) WrapFullTextScore(fulltext dbal.FullText, table string, parameter string) string {
+	fts := quoter.ID(fulltext.Table)
+	return fmt.Sprintf(
+		"(select -bm25(`fts`.%s) from %s as `fts` where `fts`.%s match %s and `fts`.rowid = %s.rowid)",
+		fts, fts, fts, quoter.wrapFTS5Query(fulltext, parameter), table,
+	)
+}
+
+// wrapFTS5Query Wrap the FTS5 query, the search is limited to the given columns. '{title content} : (' || ? || ')'
+func (quoter *Quoter) wrapFTS5Query(fulltext dbal.FullText, parameter string) string {
+	if len(fulltext.Columns) == 0 {
+		return parameter
+	}
+	columns := []string{}
+	for _, column := range fulltext.Columns {
+		columns = append(columns, strings.NewReplacer("'", "", "{", "", "}", "", " ", "").Replace(column))
+	}
+	return fmt.Sprintf("'{%s} : (' || %s || ')'", strings.Join(columns, " "), parameter)
+}
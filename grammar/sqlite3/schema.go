@@ -18,7 +18,7 @@ func (grammarSQL SQLite3) GetVersion() (*dbal.Version, error) {
 	sql := fmt.Sprintf("SELECT SQLITE_VERSION()")
 	// defer logger.Debug(logger.RETRIEVE, sql).TimeCost(time.Now())
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.GetExecutor().Select(&rows, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +42,7 @@ func (grammarSQL SQLite3) GetTables() ([]string, error) {
 	sql := fmt.Sprintf("SELECT `name` FROM `sqlite_master` WHERE type='table'")
 	defer log.Debug("%s", sql)
 	tables := []string{}
-	err := grammarSQL.DB.Select(&tables, sql)
+	err := grammarSQL.GetExecutor().Select(&tables, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +54,7 @@ func (grammarSQL SQLite3) TableExists(name string) (bool, error) {
 	sql := fmt.Sprintf("SELECT `name` FROM `sqlite_master` WHERE type='table' AND name=%s", grammarSQL.VAL(name))
 	defer log.Debug("%s", sql)
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, sql)
+	err := grammarSQL.GetExecutor().Select(&rows, sql)
 	if err != nil {
 		return false, err
 	}
@@ -120,7 +120,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table, options ...dbal.CreateT
 
 	// Create table
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	if err != nil {
 		return err
 	}
@@ -133,7 +133,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table, options ...dbal.CreateT
 		)
 	}
 	defer log.Debug("%s", strings.Join(indexStmts, ";\n"))
-	_, err = grammarSQL.DB.Exec(strings.Join(indexStmts, ";\n"))
+	_, err = grammarSQL.GetExecutor().Exec(strings.Join(indexStmts, ";\n"))
 
 	for _, cmd := range cbCommands {
 		cmd.Callback(err)
@@ -150,7 +150,7 @@ func (grammarSQL SQLite3) CreateTable(table *dbal.Table, options ...dbal.CreateT
 func (grammarSQL SQLite3) RenameTable(old string, new string) error {
 	sql := fmt.Sprintf("ALTER TABLE %s RENAME TO %s", grammarSQL.ID(old), grammarSQL.ID(new))
 	defer log.Debug("%s", sql)
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	return err
 }
 
@@ -280,7 +280,7 @@ func (grammarSQL SQLite3) GetIndexListing(dbName string, tableName string) ([]*d
 	)
 	defer log.Debug("%s", sql)
 	indexes := []*dbal.Index{}
-	err := grammarSQL.DB.Select(&indexes, sql)
+	err := grammarSQL.GetExecutor().Select(&indexes, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -333,7 +333,7 @@ func (grammarSQL SQLite3) GetColumnListing(schemaName string, tableName string)
 	)
 	defer log.Debug("%s", sql)
 	columns := []*dbal.Column{}
-	err := grammarSQL.DB.Select(&columns, sql)
+	err := grammarSQL.GetExecutor().Select(&columns, sql)
 	if err != nil {
 		return nil, err
 	}
@@ -453,7 +453,7 @@ func (grammarSQL SQLite3) AlterTable(table *dbal.Table) error {
 // GetConstraintListing get the constraints of the table
 func (grammarSQL SQLite3) GetConstraintListing(schemaName string, tableName string) (map[string]*dbal.Constraint, error) {
 	rows := []string{}
-	err := grammarSQL.DB.Select(&rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
+	err := grammarSQL.GetExecutor().Select(&rows, "SELECT `sql` FROM sqlite_master WHERE type='table' and name=?", tableName)
 	if err != nil {
 		return nil, err
 	}
@@ -499,7 +499,7 @@ func (grammarSQL SQLite3) parseConstraint(schemaName string, tableName string, l
 
 // ExecSQL execute sql then update table structure
 func (grammarSQL SQLite3) ExecSQL(table *dbal.Table, sql string) error {
-	_, err := grammarSQL.DB.Exec(sql)
+	_, err := grammarSQL.GetExecutor().Exec(sql)
 	if err != nil {
 		return err
 	}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/blang/semver/v4"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/mattn/go-sqlite3" // Load sqlite3 driver
 	"github.com/yaoapp/xun/dbal"
@@ -68,6 +69,7 @@ func (grammarSQL SQLite3) NewWith(db *sqlx.DB, config *dbal.Config, option *dbal
 	// Create a new Quoter to avoid race conditions when used concurrently
 	grammarSQL.Quoter = &Quoter{}
 	grammarSQL.Quoter.Bind(db, option.Prefix)
+	grammarSQL.Grammar = &grammarSQL
 	return grammarSQL, nil
 }
 
@@ -83,9 +85,43 @@ func (grammarSQL SQLite3) NewWithRead(write *sqlx.DB, writeConfig *dbal.Config,
 	// Create a new Quoter to avoid race conditions when used concurrently
 	grammarSQL.Quoter = &Quoter{}
 	grammarSQL.Quoter.Bind(write, option.Prefix, read)
+	grammarSQL.Grammar = &grammarSQL
 	return grammarSQL, nil
 }
 
+// WithExecutor Create a new grammar interface, using the given executor (*sqlx.DB or *sqlx.Tx) to run statements.
+func (grammarSQL SQLite3) WithExecutor(executor dbal.Executor) dbal.Grammar {
+	grammarSQL.Executor = executor
+	return grammarSQL
+}
+
+// SupportsWindowFunctions Determine if the database supports window functions
+func (grammarSQL SQLite3) SupportsWindowFunctions() bool {
+	version, err := sql.CachedVersion(grammarSQL.DB, grammarSQL.GetVersion)
+	if err != nil {
+		return false
+	}
+	return version.GTE(semver.MustParse("3.25.0"))
+}
+
+// SupportsReturning Determine if the database supports the "returning" clause of the given statement. (insert, update, delete, upsert)
+func (grammarSQL SQLite3) SupportsReturning(statement string) bool {
+	version, err := sql.CachedVersion(grammarSQL.DB, grammarSQL.GetVersion)
+	if err != nil {
+		return false
+	}
+	return version.GTE(semver.MustParse("3.35.0"))
+}
+
+// GetMaxPlaceholders Get the maximum number of the placeholders in a statement, it was 999 before SQLite 3.32.0
+func (grammarSQL SQLite3) GetMaxPlaceholders() int {
+	version, err := sql.CachedVersion(grammarSQL.DB, grammarSQL.GetVersion)
+	if err != nil || version.LT(semver.MustParse("3.32.0")) {
+		return 999
+	}
+	return 32766
+}
+
 // New Create a new mysql grammar inteface
 func New(opts ...sql.Option) dbal.Grammar {
 	sqlite := SQLite3{
@@ -117,6 +153,8 @@ func New(opts ...sql.Option) dbal.Grammar {
 		sqlite.FlipTypes["UNSIGNED BIG INT"] = "bigInteger"
 	}
 
+	// The sub-queries are compiled by the dialect grammar, NewWith and NewWithRead bind their copies again.
+	sqlite.Grammar = &sqlite
 	return sqlite
 }
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/yaoapp/xun/dbal"
+	"github.com/yaoapp/xun/grammar/sql"
 	"github.com/yaoapp/xun/utils"
 )
 
@@ -50,11 +51,19 @@ func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]int
 		offset := 0
 		bindings := []interface{}{}
 		table := grammarSQL.WrapTable(query.From)
+		with := grammarSQL.CompileWith(query, query.CTEs, &offset)
+		if len(query.CTEs) > 0 {
+			bindings = append(bindings, query.GetBindings("with")...)
+		}
 		columns, columnsBindings := grammarSQL.CompileUpdateColumns(query, values, &offset)
 		bindings = append(bindings, columnsBindings...)
 		wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
 		bindings = append(bindings, query.GetBindings("where")...)
-		return fmt.Sprintf("update %s set %s %s", table, columns, wheres), bindings
+		sql := fmt.Sprintf("update %s set %s %s", table, columns, wheres)
+		if with != "" {
+			sql = fmt.Sprintf("%s %s", with, sql)
+		}
+		return sql, bindings
 	}
 
 	offset := 0
@@ -78,3 +87,12 @@ func (grammarSQL SQLite3) CompileUpdate(query *dbal.Query, values map[string]int
 
 	return sql, bindings
 }
+
+// CompileUpdateBatch Compile a batch update statement into SQL, each row is updated with its own values and matched by the key column.
+func (grammarSQL SQLite3) CompileUpdateBatch(query *dbal.Query, key string, columns []interface{}, values [][]interface{}) (string, []interface{}) {
+	offset := 0
+	sets, keys, bindings := grammarSQL.CompileUpdateBatchCases(query, key, columns, values, &offset)
+	wheres := grammarSQL.CompileWheres(query, query.Wheres, &offset)
+	bindings = append(bindings, query.GetBindings("where")...)
+	return fmt.Sprintf("update %s set %s %s", grammarSQL.WrapTable(query.From), sets, sql.BatchWheres(keys, wheres)), bindings
+}
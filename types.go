@@ -15,6 +15,19 @@ type T struct {
 	Time interface{}
 }
 
+// DBTag the options of the "db" tag of a struct field, the options are separated by commas.
+// db:"id,auto" the column is filled by the database, it is skipped on insert if the value is zero and never updated.
+// db:"name,omitempty" the column is skipped if the value is zero.
+// db:"meta,json" the value is marshalled into JSON.
+// db:"-" the field is not a column.
+type DBTag struct {
+	Name      string
+	Auto      bool
+	OmitEmpty bool
+	JSON      bool
+	Skip      bool
+}
+
 // P an Paginator struct, P is the first letter of "Paginator"
 type P struct {
 	Items        []interface{}          `json:"items"`
@@ -28,6 +41,23 @@ type P struct {
 	Options      map[string]interface{} `json:"options,omtempty"`
 }
 
+// SP an simple paginator struct without total, SP is the first letters of "Simple Paginator"
+type SP struct {
+	Items        []interface{} `json:"items"`
+	PageSize     int           `json:"page_size"`
+	CurrentPage  int           `json:"current_page"`
+	NextPage     int           `json:"next_page"`
+	PreviousPage int           `json:"previous_page"`
+}
+
+// CP an cursor paginator struct, CP is the first letters of "Cursor Paginator"
+type CP struct {
+	Items          []interface{} `json:"items"`
+	PageSize       int           `json:"page_size"`
+	NextCursor     string        `json:"next_cursor"`
+	PreviousCursor string        `json:"previous_cursor"`
+}
+
 // UploadFile deprecated -> gou.UploadFile upload file
 type UploadFile struct {
 	Name     string
@@ -207,6 +207,57 @@ func MakeP(total int, pageSize int, currentPage int, items ...interface{}) P {
 
 }
 
+// MakeSP create a new SP struct
+func MakeSP(pageSize int, currentPage int, hasMore bool, items ...interface{}) SP {
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	if currentPage < 1 {
+		currentPage = 1
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	next := -1
+	if hasMore {
+		next = currentPage + 1
+	}
+
+	prev := currentPage - 1
+	if prev <= 0 {
+		prev = -1
+	}
+
+	return SP{
+		Items:        items,
+		PageSize:     pageSize,
+		CurrentPage:  currentPage,
+		NextPage:     next,
+		PreviousPage: prev,
+	}
+}
+
+// MakeCP create a new CP struct
+func MakeCP(pageSize int, nextCursor string, previousCursor string, items ...interface{}) CP {
+	if pageSize < 1 {
+		pageSize = 15
+	}
+
+	if items == nil {
+		items = []interface{}{}
+	}
+
+	return CP{
+		Items:          items,
+		PageSize:       pageSize,
+		NextCursor:     nextCursor,
+		PreviousCursor: previousCursor,
+	}
+}
+
 // Value get the value of the given key ( alias Get)
 func (row R) Value(key interface{}) interface{} {
 	return row.Get(key)
@@ -420,6 +471,16 @@ func structToR(value interface{}) R {
 			if !reflectValue.Field(i).CanInterface() {
 				continue
 			}
+
+			// The fields with the "db" tag are the columns, the values are not converted.
+			if dbTag, has := GetDBTag(reflectType.Field(i)); has {
+				if dbTag.Skip || ((dbTag.Auto || dbTag.OmitEmpty) && reflectValue.Field(i).IsZero()) {
+					continue
+				}
+				r[dbTag.Name] = dbTag.Value(reflectValue.Field(i).Interface())
+				continue
+			}
+
 			tag := GetTagName(reflectType.Field(i), "json")
 			field := reflectValue.Field(i).Interface()
 			if tag != "" && tag != "-" {
@@ -446,6 +507,58 @@ func GetTagName(field reflect.StructField, name string) string {
 	return tag
 }
 
+// GetDBTag get the options of the "db" tag of the reflect.StructField, returns false if the field has no "db" tag.
+// The column name is the field name in snake case if it is not given.
+func GetDBTag(field reflect.StructField) (DBTag, bool) {
+	value, has := field.Tag.Lookup("db")
+	if !has {
+		return DBTag{}, false
+	}
+
+	if value == "-" {
+		return DBTag{Skip: true}, true
+	}
+
+	options := strings.Split(value, ",")
+	tag := DBTag{Name: strings.TrimSpace(options[0])}
+	if tag.Name == "" {
+		tag.Name = ToSnakeCase(field.Name)
+	}
+
+	for _, option := range options[1:] {
+		switch strings.TrimSpace(option) {
+		case "auto":
+			tag.Auto = true
+		case "omitempty":
+			tag.OmitEmpty = true
+		case "json":
+			tag.JSON = true
+		}
+	}
+	return tag, true
+}
+
+// Value get the column value of the field value, the value is marshalled into JSON if the json option is given.
+// The nil pointers, maps and slices are null.
+func (tag DBTag) Value(value interface{}) interface{} {
+	if !tag.JSON || value == nil {
+		return value
+	}
+
+	switch reflectValue := reflect.ValueOf(value); reflectValue.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if reflectValue.IsNil() {
+			return nil
+		}
+	}
+
+	bytes, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Errorf("the value of %s could not be marshalled into JSON: %s", tag.Name, err))
+	}
+	return string(bytes)
+}
+
 // MakeNum Create a new xun.N struct ( alias MakeN )
 func MakeNum(v interface{}) N {
 	return MakeN(v)
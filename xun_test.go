@@ -1,6 +1,7 @@
 package xun
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +52,53 @@ func TestMakeR(t *testing.T) {
 	assert.Equal(t, "hello nested mapstr", r.Get("nested.mapstr.key2"), `r["nested.mapstr.key2"] should be "hello nested mapstr"`)
 }
 
+func TestMakeRWithDBTag(t *testing.T) {
+	type User struct {
+		ID       int                    `db:"id,auto"`
+		Email    string                 `db:"email"`
+		Name     string                 `db:"name,omitempty"`
+		NickName string                 `db:""`
+		Meta     map[string]interface{} `db:"meta,json"`
+		Secret   string                 `db:"-"`
+	}
+
+	r := MakeR(User{Email: "john@yao.run", Meta: map[string]interface{}{"level": 1}, Secret: "secret"})
+	assert.Equal(t, R{"email": "john@yao.run", "nick_name": "", "meta": `{"level":1}`}, r, "the zero auto and omitempty fields should be skipped")
+
+	r = MakeR(&User{ID: 1, Email: "john@yao.run", Name: "John"})
+	assert.Equal(t, R{"id": 1, "email": "john@yao.run", "name": "John", "nick_name": "", "meta": nil}, r, "the nil json field should be null")
+}
+
+func TestGetDBTag(t *testing.T) {
+	type User struct {
+		ID     int    `db:"id,auto"`
+		Name   string `db:"name, omitempty,json"`
+		Email  string `db:""`
+		Secret string `db:"-"`
+		Vote   int
+	}
+
+	userType := reflect.TypeOf(User{})
+	tag, has := GetDBTag(userType.Field(0))
+	assert.True(t, has)
+	assert.Equal(t, DBTag{Name: "id", Auto: true}, tag)
+
+	tag, has = GetDBTag(userType.Field(1))
+	assert.True(t, has)
+	assert.Equal(t, DBTag{Name: "name", OmitEmpty: true, JSON: true}, tag)
+
+	tag, has = GetDBTag(userType.Field(2))
+	assert.True(t, has)
+	assert.Equal(t, DBTag{Name: "email"}, tag)
+
+	tag, has = GetDBTag(userType.Field(3))
+	assert.True(t, has)
+	assert.True(t, tag.Skip)
+
+	_, has = GetDBTag(userType.Field(4))
+	assert.False(t, has)
+}
+
 func TestMakeRSlice(t *testing.T) {
 	type User struct {
 		Email string `json:"email"`